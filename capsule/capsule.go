@@ -24,6 +24,7 @@ func New() *Manager {
 		Pool:        &Pool{},
 		Connections: &sync.Map{},
 		Option:      &dbal.Option{},
+		Scopes:      query.NewScopes(),
 	}
 }
 
@@ -1,6 +1,7 @@
 package capsule
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -70,4 +71,9 @@ func TestPing(t *testing.T) {
 
 	err = conn.Ping(1 * time.Second)
 	assert.Equal(t, "context deadline exceeded", err.Error())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = conn.PingContext(ctx)
+	assert.Equal(t, context.Canceled, err)
 }
@@ -7,24 +7,26 @@ import (
 
 // Ping verifies a connection to the database is still alive,
 // establishing a connection if necessary.
-func (conn *Connection) Ping(timeout time.Duration) (err error) {
-
-	done := make(chan bool, 1)
+func (conn *Connection) Ping(timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
+	return conn.PingContext(ctx)
+}
 
+// PingContext verifies a connection to the database is still alive using the given context,
+// establishing a connection if necessary. It returns as soon as the context is done,
+// even if the driver does not respect the context.
+func (conn *Connection) PingContext(ctx context.Context) error {
+
+	done := make(chan error, 1)
 	go func() {
-		err = conn.DB.PingContext(ctx)
-		done <- true
+		done <- conn.DB.PingContext(ctx)
 	}()
 
 	select {
 	case <-ctx.Done():
-		err = ctx.Err()
-		break
-	case <-done:
-		break
+		return ctx.Err()
+	case err := <-done:
+		return err
 	}
-
-	return err
 }
@@ -92,6 +92,7 @@ func (manager *Manager) Query() query.Query {
 			Read:        &read.DB,
 			ReadConfig:  read.Config,
 			Option:      manager.Option,
+			Scopes:      manager.Scopes,
 		})
 }
 
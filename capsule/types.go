@@ -5,6 +5,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/dbal/query"
 )
 
 // Manager The database manager
@@ -12,6 +13,7 @@ type Manager struct {
 	Pool        *Pool
 	Connections *sync.Map // map[string]*Connection
 	Option      *dbal.Option
+	Scopes      *query.Scopes // the scopes shared by the query builders of the manager
 }
 
 // Pool the connection pool
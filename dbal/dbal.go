@@ -11,7 +11,7 @@ var Grammars = map[string]Grammar{}
 
 // BindingKeys the binding key orders
 var BindingKeys = []string{
-	"select", "from", "join", "where",
+	"with", "select", "from", "join", "where",
 	"groupBy", "having",
 	"order",
 	"union", "unionOrder",
@@ -59,6 +59,8 @@ func NewTable(name string, schemaName string, dbName string) *Table {
 		ColumnMap:  map[string]*Column{},
 		Indexes:    []*Index{},
 		IndexMap:   map[string]*Index{},
+		Foreigns:   []*Foreign{},
+		ForeignMap: map[string]*Foreign{},
 		Commands:   []*Command{},
 	}
 }
@@ -96,6 +98,7 @@ func NewQuery() *Query {
 		UnionOffset:        -1,
 		BindingOffset:      0,
 		Bindings: map[string][]interface{}{
+			"with":       {},
 			"select":     {},
 			"from":       {},
 			"join":       {},
@@ -123,6 +126,27 @@ func Raw(value interface{}) Expression {
 	return NewExpression(value)
 }
 
+// CopyFromRows Create a CopyFromSource from the rows
+func CopyFromRows(rows [][]interface{}) CopyFromSource {
+	return &copyFromRows{rows: rows, index: -1}
+}
+
+// Next Advance to the next row
+func (source *copyFromRows) Next() bool {
+	source.index++
+	return source.index < len(source.rows)
+}
+
+// Values The values of the current row
+func (source *copyFromRows) Values() ([]interface{}, error) {
+	return source.rows[source.index], nil
+}
+
+// Err The error occurred while iterating
+func (source *copyFromRows) Err() error {
+	return nil
+}
+
 // IsExpression Determine if the given value is a raw expression.
 func IsExpression(value interface{}) bool {
 	switch value.(type) {
@@ -217,6 +241,36 @@ func (table *Table) GetIndex(name string) *Index {
 	return table.IndexMap[name]
 }
 
+// NewForeign create a new foreign key intstance
+func (table *Table) NewForeign(name string, columns ...*Column) *Foreign {
+	return &Foreign{
+		DBName:     table.DBName,
+		TableName:  table.TableName,
+		Table:      table,
+		Name:       name,
+		Columns:    columns,
+		References: []string{},
+	}
+}
+
+// PushForeign push a foreign key instance to the table foreign keys
+func (table *Table) PushForeign(foreign *Foreign) *Table {
+	table.ForeignMap[foreign.Name] = foreign
+	table.Foreigns = append(table.Foreigns, foreign)
+	return table
+}
+
+// HasForeign checking if the given name foreign key exists
+func (table *Table) HasForeign(name string) bool {
+	_, has := table.ForeignMap[name]
+	return has
+}
+
+// GetForeign get the given name foreign key instance
+func (table *Table) GetForeign(name string) *Foreign {
+	return table.ForeignMap[name]
+}
+
 // AddCommand Add a new command to the table.
 //
 // The commands must be:
@@ -227,6 +281,8 @@ func (table *Table) GetIndex(name string) *Index {
 //    CreateIndex(index *Index) for creating a index
 //    DropIndex( name string) for  dropping a index
 //    RenameIndex(old string,new string)  for renaming a index
+//    CreateForeign(foreign *Foreign) for creating a foreign key
+//    DropForeign(name string) for dropping a foreign key
 func (table *Table) AddCommand(name string, success func(), fail func(), params ...interface{}) {
 	table.Commands = append(table.Commands, &Command{
 		Name:    name,
@@ -255,6 +311,16 @@ func (index *Index) AddColumn(column *Column) {
 	index.Columns = append(index.Columns, column)
 }
 
+// AddColumn add column to foreign key
+func (foreign *Foreign) AddColumn(column *Column) {
+	for _, col := range foreign.Columns {
+		if col.Name == column.Name {
+			return
+		}
+	}
+	foreign.Columns = append(foreign.Columns, column)
+}
+
 // Fullname get the name name with prefix
 func (name Name) Fullname() string {
 	return fmt.Sprintf("%s%s", name.Prefix, name.Name)
@@ -281,6 +347,7 @@ func (query *Query) Clone() *Query {
 	new := Query{
 		UseWriteConnection: query.UseWriteConnection,    // Whether to use write connection for the select. default is false
 		Lock:               query.CopyLock(),            //  Indicates whether row locking is being used.
+		CTEs:               query.CopyCTEs(),            // The common table expressions of the query.
 		From:               query.CopyFrom(),            // The table which the query is targeting.
 		Columns:            query.CopyColumns(),         // The columns that should be returned. (Name or Expression)
 		Aggregate:          query.CopyAggregate(),       // An aggregate function and column to be run.
@@ -295,6 +362,13 @@ func (query *Query) Clone() *Query {
 		Offset:             query.Offset,                // The number of records to skip.
 		Groups:             query.CopyGroups(),          // The groupings for the query.
 		Havings:            query.CopyHavings(),         // The having constraints for the query.
+		Windows:            query.CopyWindows(),         // The named windows of the query.
+		Returning:          query.CopyReturning(),       // The columns returned by the insert, update, upsert and delete statements.
+		Conflict:           query.CopyConflict(),        // The conflict handling of the upsert statements.
+		SoftDeletes:        query.SoftDeletes,           // The soft delete column, the trashed records are excluded from the query if it is set.
+		Trashed:            query.Trashed,               // The trashed records to query, with or only. default is excluding the trashed records.
+		WithoutScopes:      query.CopyWithoutScopes(),   // The global scopes removed from the query, * removes all of them.
+		Timestamps:         query.Timestamps,            // The type of the timestamps maintained by the insert and update statements, timestamp or timestampTz.
 		Bindings:           query.CopyBindings(),        // The current query value bindings.
 		Distinct:           query.Distinct,              // Indicates if the query returns distinct results. Occasionally contains the columns that should be distinct. default is false
 		DistinctColumns:    query.CopyDistinctColumns(), // Indicates if the query returns distinct results. Occasionally contains the columns that should be distinct.
@@ -386,6 +460,47 @@ func (query *Query) CopyUnions() []Union {
 	return new
 }
 
+// CopyCTEs copy CTEs
+func (query *Query) CopyCTEs() []CTE {
+	new := []CTE{}
+	for _, cte := range query.CTEs {
+		new = append(new, cte)
+	}
+	return new
+}
+
+// CopyWindows copy Windows
+func (query *Query) CopyWindows() []Window {
+	new := []Window{}
+	for _, window := range query.Windows {
+		new = append(new, window)
+	}
+	return new
+}
+
+// CopyReturning copy Returning
+func (query *Query) CopyReturning() []interface{} {
+	new := []interface{}{}
+	new = append(new, query.Returning...)
+	return new
+}
+
+// CopyWithoutScopes copy WithoutScopes
+func (query *Query) CopyWithoutScopes() []string {
+	new := []string{}
+	new = append(new, query.WithoutScopes...)
+	return new
+}
+
+// CopyConflict copy Conflict
+func (query *Query) CopyConflict() Conflict {
+	new := Conflict{Constraint: query.Conflict.Constraint}
+	if query.Conflict.Where != nil {
+		new.Where = query.Conflict.Where.Clone()
+	}
+	return new
+}
+
 // CopyUnionOrders copy UnionOrders
 func (query *Query) CopyUnionOrders() []Order {
 	new := []Order{}
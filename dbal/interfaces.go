@@ -1,6 +1,10 @@
 package dbal
 
 import (
+	"context"
+	"database/sql"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 )
 
@@ -8,6 +12,8 @@ import (
 type Grammar interface {
 	NewWith(db *sqlx.DB, config *Config, option *Option) (Grammar, error)
 	NewWithRead(write *sqlx.DB, writeConfig *Config, read *sqlx.DB, readConfig *Config, option *Option) (Grammar, error)
+	WithTx(tx *sqlx.Tx) Grammar
+	WithContext(ctx context.Context) Grammar
 
 	Wrap(value interface{}) string
 	WrapTable(value interface{}) string
@@ -17,6 +23,7 @@ type Grammar interface {
 	GetVersion() (*Version, error)
 	GetDatabase() string
 	GetSchema() string
+	GetLocation() *time.Location
 	GetOperators() []string
 
 	// Grammar for migrating
@@ -37,14 +44,28 @@ type Grammar interface {
 	CompileInsertGetID(query *Query, columns []interface{}, values [][]interface{}, sequence string) (string, []interface{})
 	CompileInsertUsing(query *Query, columns []interface{}, sql string) string
 	CompileUpsert(query *Query, columns []interface{}, values [][]interface{}, uniqueBy []interface{}, updateValues interface{}) (string, []interface{})
+	CompileExcluded(column string) string
 	CompileUpdate(query *Query, values map[string]interface{}) (string, []interface{})
 	CompileDelete(query *Query) (string, []interface{})
 	CompileTruncate(query *Query) ([]string, [][]interface{})
+	CompileReturning(query *Query, columns []interface{}) (string, error)
 	CompileSelect(query *Query) string
 	CompileSelectOffset(query *Query, offset *int) string
 	CompileExists(query *Query) string
 
+	CompileSavepoint(name string) string
+	CompileReleaseSavepoint(name string) string
+	CompileRollbackToSavepoint(name string) string
+
 	ProcessInsertGetID(sql string, bindings []interface{}, sequence string) (int64, error)
+	ProcessCopyFrom(query *Query, columns []string, rows CopyFromSource) (int64, error)
+}
+
+// CopyFromSource the source of the rows imported by the CopyFrom method
+type CopyFromSource interface {
+	Next() bool                     // Advance to the next row, returns false if there are no more rows or an error occurred
+	Values() ([]interface{}, error) // The values of the current row, in the order of the columns
+	Err() error                     // The error occurred while iterating
 }
 
 // Quoter the database quoting query text intrface
@@ -60,3 +81,17 @@ type Quoter interface {
 	Parameterize(values []interface{}, offset int) string
 	Columnize(columns []interface{}) string
 }
+
+// Executor the database handle interface, both *sqlx.DB and *sqlx.Tx implement it.
+type Executor interface {
+	sqlx.Ext
+	sqlx.ExtContext
+	sqlx.Preparer
+	sqlx.PreparerContext
+	Get(dest interface{}, query string, args ...interface{}) error
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRow(query string, args ...interface{}) *sql.Row
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
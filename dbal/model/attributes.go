@@ -0,0 +1,92 @@
+package model
+
+import (
+	"reflect"
+
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/utils"
+)
+
+// GetAttributes Get the values of the columns to be written to the database
+func (model *Model) GetAttributes() (xun.R, error) {
+	attributes := xun.R{}
+	for _, field := range model.meta.fields {
+		value, err := dbValue(model.value.FieldByIndex(field.index))
+		if err != nil {
+			return nil, err
+		}
+		attributes[field.column] = value
+	}
+	return attributes, nil
+}
+
+// MustGetAttributes Get the values of the columns to be written to the database
+func (model *Model) MustGetAttributes() xun.R {
+	attributes, err := model.GetAttributes()
+	utils.PanicIF(err)
+	return attributes
+}
+
+// GetOriginal Get the values of the columns when the model was retrieved or saved
+func (model *Model) GetOriginal() xun.R {
+	original := xun.R{}
+	for column, value := range model.original {
+		original[column] = value
+	}
+	return original
+}
+
+// SyncOriginal Sync the original values with the current values of the columns
+func (model *Model) SyncOriginal() error {
+	attributes, err := model.GetAttributes()
+	if err != nil {
+		return err
+	}
+	model.original = attributes
+	return nil
+}
+
+// GetDirty Get the columns changed since the model was retrieved or saved
+func (model *Model) GetDirty() (xun.R, error) {
+	attributes, err := model.GetAttributes()
+	if err != nil {
+		return nil, err
+	}
+
+	dirty := xun.R{}
+	for column, value := range attributes {
+		original, has := model.original[column]
+		if !has || !reflect.DeepEqual(original, value) {
+			dirty[column] = value
+		}
+	}
+	return dirty, nil
+}
+
+// MustGetDirty Get the columns changed since the model was retrieved or saved
+func (model *Model) MustGetDirty() xun.R {
+	dirty, err := model.GetDirty()
+	utils.PanicIF(err)
+	return dirty
+}
+
+// IsDirty Determine if the model or the given columns have been changed, e.g.
+//    user.IsDirty()                // any of the columns
+//    user.IsDirty("name", "vote")  // name or vote
+func (model *Model) IsDirty(columns ...string) bool {
+	dirty := model.MustGetDirty()
+	if len(columns) == 0 {
+		return len(dirty) > 0
+	}
+	for _, column := range columns {
+		if _, has := dirty[column]; has {
+			return true
+		}
+	}
+	return false
+}
+
+// IsClean Determine if the model or the given columns have not been changed
+func (model *Model) IsClean(columns ...string) bool {
+	return !model.IsDirty(columns...)
+}
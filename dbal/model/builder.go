@@ -0,0 +1,151 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/xun/utils"
+)
+
+// Query Create a query builder of the models, the v is the pointer of a slice of the model pointers or the pointer of a model.
+// The table of the model is used if the query builder has no table, e.g.
+//    users := []*User{}
+//    err := model.Query(qb.Where("vote", ">", 10), &users).With("posts.comments").Get()
+func Query(qb query.Query, v interface{}) *Builder {
+	return &Builder{query: qb, value: v, with: []string{}, scopes: []func(qb query.Query) error{}}
+}
+
+// With Set the relations to be eager loaded, the nested relations are separated by dots, e.g. "posts.comments"
+func (builder *Builder) With(relations ...string) *Builder {
+	builder.with = append(builder.with, relations...)
+	return builder
+}
+
+// Scope Apply the local scope registered on the table of the model, e.g.
+// the error is returned by Get or First if the scope is not registered.
+//    model.AddScope(qb, &User{}, "active", func(qb query.Query, args ...interface{}) { qb.Where("status", "active") })
+//    model.Query(qb.New(), &users).Scope("active").Get()
+func (builder *Builder) Scope(name string, args ...interface{}) *Builder {
+	builder.scopes = append(builder.scopes, func(qb query.Query) error {
+		_, err := qb.Scope(name, args...)
+		return err
+	})
+	return builder
+}
+
+// WithoutGlobalScope Remove the global scope of the model from the query
+func (builder *Builder) WithoutGlobalScope(name string) *Builder {
+	builder.query.WithoutGlobalScope(name)
+	return builder
+}
+
+// Get Execute the query, fill the slice with the models and load the relations.
+func (builder *Builder) Get() error {
+	value := reflect.ValueOf(builder.value)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Slice ||
+		value.Elem().Type().Elem().Kind() != reflect.Ptr || value.Elem().Type().Elem().Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("the models should be a pointer of a slice of the model pointers, %T given", builder.value)
+	}
+
+	slice := value.Elem()
+	meta, err := getMeta(slice.Type().Elem().Elem())
+	if err != nil {
+		return err
+	}
+
+	qb, err := builder.prepare(meta)
+	if err != nil {
+		return err
+	}
+
+	rows, err := qb.Get()
+	if err != nil {
+		return err
+	}
+
+	models, err := newModels(builder.query.New(), meta, rows)
+	if err != nil {
+		return err
+	}
+
+	err = eagerLoad(builder.query.New(), meta, models, builder.with)
+	if err != nil {
+		return err
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, len(models))
+	slice.Set(reflect.Append(result, models...))
+	return nil
+}
+
+// MustGet Execute the query, fill the slice with the models and load the relations.
+func (builder *Builder) MustGet() {
+	err := builder.Get()
+	utils.PanicIF(err)
+}
+
+// First Execute the query, fill the model with the first record and load the relations.
+func (builder *Builder) First() error {
+	value := reflect.ValueOf(builder.value)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("the model should be a pointer of struct, %T given", builder.value)
+	}
+
+	meta, err := getMeta(value.Elem().Type())
+	if err != nil {
+		return err
+	}
+
+	qb, err := builder.prepare(meta)
+	if err != nil {
+		return err
+	}
+
+	row, err := qb.First()
+	if err != nil {
+		return err
+	}
+
+	if row.IsEmpty() {
+		return fmt.Errorf("the %s is not found", meta.table)
+	}
+
+	model := bind(builder.query.New(), meta, value.Elem())
+	err = model.fill(row, false)
+	if err != nil {
+		return err
+	}
+
+	model.exists = true
+	err = model.SyncOriginal()
+	if err != nil {
+		return err
+	}
+
+	return eagerLoad(model.query, meta, []reflect.Value{value}, builder.with)
+}
+
+// MustFirst Execute the query, fill the model with the first record and load the relations.
+func (builder *Builder) MustFirst() {
+	err := builder.First()
+	utils.PanicIF(err)
+}
+
+// prepare set the table and the columns of the model to the query if they are not set, and apply the local scopes.
+func (builder *Builder) prepare(m *meta) (query.Query, error) {
+	qb := builder.query
+	if qb.Builder().Query.From.IsEmpty() {
+		qb.From(m.table)
+	}
+	for _, scope := range builder.scopes {
+		err := scope(qb)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(qb.Builder().Query.Columns) == 0 {
+		qb.Select(m.qualifiedColumnNames()...)
+	}
+	return qb, nil
+}
@@ -0,0 +1,256 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/utils"
+)
+
+var modelType = reflect.TypeOf(Model{})
+
+// the parsed bindings of the model structs
+var metas = sync.Map{}
+
+// the model struct types of the tables, the polymorphic relations find the related models by the table name.
+var morphTypes = sync.Map{}
+
+// the relation types could be defined in the xun tag of a struct field
+var relationTypes = map[string]bool{
+	"hasOne": true, "hasMany": true, "belongsTo": true, "belongsToMany": true,
+	"morphOne": true, "morphMany": true, "morphTo": true,
+}
+
+// Register Parse the bindings of the model structs ahead,
+// the models referenced by the morphTo relations should be registered or bound before loading.
+func Register(v ...interface{}) error {
+	for _, model := range v {
+		if _, err := metaOf(model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MustRegister Parse the bindings of the model structs ahead
+func MustRegister(v ...interface{}) {
+	err := Register(v...)
+	utils.PanicIF(err)
+}
+
+// metaOf get the table binding of the model struct or the pointer of it
+func metaOf(v interface{}) (*meta, error) {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("the model should be a struct, %T given", v)
+	}
+	return getMeta(typ)
+}
+
+// getMeta get the table binding of the model struct, the bindings are parsed once for each type.
+func getMeta(typ reflect.Type) (*meta, error) {
+	if cached, has := metas.Load(typ); has {
+		return cached.(*meta), nil
+	}
+
+	m := &meta{
+		typ:       typ,
+		table:     xun.ToSnakeCase(typ.Name()),
+		primary:   "id",
+		fields:    []field{},
+		columns:   map[string]int{},
+		relations: map[string]*relation{},
+	}
+
+	err := m.parse(typ, []int{})
+	if err != nil {
+		return nil, err
+	}
+
+	if m.base == nil {
+		return nil, fmt.Errorf("the %s struct should embed the model.Model", typ.Name())
+	}
+
+	if _, has := m.columns[m.primary]; !has {
+		return nil, fmt.Errorf("the primary key %s is not a field of the %s struct", m.primary, typ.Name())
+	}
+
+	for _, rel := range m.relations {
+		m.relationDefaults(rel)
+	}
+
+	metas.Store(typ, m)
+	morphTypes.LoadOrStore(m.table, typ)
+	return m, nil
+}
+
+// parse the fields of the struct, the anonymous structs are flattened.
+func (m *meta) parse(typ reflect.Type, index []int) error {
+	for i := 0; i < typ.NumField(); i++ {
+		structField := typ.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if structField.Type == modelType {
+			m.base = fieldIndex
+			_, options := parseTag(structField.Tag.Get("xun"))
+			if options["table"] != "" {
+				m.table = options["table"]
+			}
+			if options["primary"] != "" {
+				m.primary = options["primary"]
+			}
+			continue
+		}
+
+		if structField.PkgPath != "" {
+			continue
+		}
+
+		if structField.Anonymous && structField.Type.Kind() == reflect.Struct && structField.Tag.Get("json") == "" {
+			err := m.parse(structField.Type, fieldIndex)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		column := strings.Split(xun.GetTagName(structField, "json"), ",")[0]
+		if typ, options := parseTag(structField.Tag.Get("xun")); relationTypes[typ] {
+			if column == "" || column == "-" {
+				column = xun.ToSnakeCase(structField.Name)
+			}
+			rel, err := newRelation(column, typ, structField, options)
+			if err != nil {
+				return err
+			}
+			rel.index = fieldIndex
+			m.relations[rel.name] = rel
+			continue
+		}
+
+		if column == "" || column == "-" {
+			continue
+		}
+
+		m.columns[column] = len(m.fields)
+		m.fields = append(m.fields, field{column: column, index: fieldIndex})
+	}
+	return nil
+}
+
+// newRelation create the relation binding of the struct field
+func newRelation(name string, typ string, structField reflect.StructField, options map[string]string) (*relation, error) {
+	rel := &relation{
+		name:    name,
+		typ:     typ,
+		foreign: options["foreign"],
+		local:   options["local"],
+		owner:   options["owner"],
+		pivot:   options["pivot"],
+		related: options["related"],
+		morph:   options["morph"],
+	}
+
+	if typ == "morphTo" {
+		if structField.Type.Kind() != reflect.Interface {
+			return nil, fmt.Errorf("the morphTo relation %s should be an interface{} field", name)
+		}
+		if rel.morph == "" {
+			rel.morph = xun.ToSnakeCase(structField.Name)
+		}
+		return rel, nil
+	}
+
+	target := structField.Type
+	many := typ == "hasMany" || typ == "belongsToMany" || typ == "morphMany"
+	if many && target.Kind() == reflect.Slice {
+		target = target.Elem()
+	}
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Struct || (many && structField.Type.Kind() != reflect.Slice) {
+		expected := "a pointer of the model"
+		if many {
+			expected = "a slice of the model pointers"
+		}
+		return nil, fmt.Errorf("the %s relation %s should be %s, %s given", typ, name, expected, structField.Type)
+	}
+	rel.target = target.Elem()
+
+	if typ == "belongsTo" && rel.foreign == "" {
+		rel.foreign = fmt.Sprintf("%s_id", xun.ToSnakeCase(structField.Name))
+	}
+
+	if typ == "belongsToMany" && rel.pivot == "" {
+		return nil, fmt.Errorf("the pivot table of the belongsToMany relation %s is required", name)
+	}
+
+	if (typ == "morphOne" || typ == "morphMany") && rel.morph == "" {
+		return nil, fmt.Errorf("the morph name of the %s relation %s is required", typ, name)
+	}
+
+	return rel, nil
+}
+
+// relationDefaults set the default keys of the relation depending on the model
+func (m *meta) relationDefaults(rel *relation) {
+	parent := fmt.Sprintf("%s_id", xun.ToSnakeCase(m.typ.Name()))
+	switch rel.typ {
+	case "hasOne", "hasMany":
+		if rel.foreign == "" {
+			rel.foreign = parent
+		}
+	case "belongsToMany":
+		if rel.foreign == "" {
+			rel.foreign = parent
+		}
+		if rel.related == "" {
+			rel.related = fmt.Sprintf("%s_id", xun.ToSnakeCase(rel.target.Name()))
+		}
+	}
+
+	if rel.local == "" && rel.typ != "belongsTo" && rel.typ != "morphTo" {
+		rel.local = m.primary
+	}
+}
+
+// parseTag parse the xun tag, returns the first bare option and the key-value options, e.g.
+//    xun:"table:users,primary:id"      => "", {table: users, primary: id}
+//    xun:"hasMany,foreign:user_id"     => hasMany, {foreign: user_id}
+func parseTag(tag string) (string, map[string]string) {
+	typ := ""
+	options := map[string]string{}
+	for i, option := range strings.Split(tag, ",") {
+		kv := strings.SplitN(option, ":", 2)
+		if len(kv) != 2 {
+			if i == 0 {
+				typ = strings.TrimSpace(option)
+			}
+			continue
+		}
+		options[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return typ, options
+}
+
+// columnNames get the names of the columns
+func (m *meta) columnNames() []interface{} {
+	columns := []interface{}{}
+	for _, field := range m.fields {
+		columns = append(columns, field.column)
+	}
+	return columns
+}
+
+// qualifiedColumnNames get the names of the columns qualified by the table name, e.g. users.id
+func (m *meta) qualifiedColumnNames() []interface{} {
+	columns := []interface{}{}
+	for _, field := range m.fields {
+		columns = append(columns, fmt.Sprintf("%s.%s", m.table, field.column))
+	}
+	return columns
+}
@@ -0,0 +1,232 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/xun/utils"
+)
+
+// Bind Bind the struct embedding the model to its table, the statements are executed by the query builder, e.g.
+//    user := &User{}
+//    err := model.Bind(qb, user)
+//    err = user.Find(1)
+func Bind(qb query.Query, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("the model should be a pointer of struct, %T given", v)
+	}
+
+	meta, err := getMeta(value.Elem().Type())
+	if err != nil {
+		return err
+	}
+
+	bind(qb, meta, value.Elem())
+	return nil
+}
+
+// MustBind Bind the struct embedding the model to its table.
+func MustBind(qb query.Query, v interface{}) {
+	err := Bind(qb, v)
+	utils.PanicIF(err)
+}
+
+// bind reset the model embedded in the struct, and bind it to the table
+func bind(qb query.Query, m *meta, value reflect.Value) *Model {
+	model := value.FieldByIndex(m.base).Addr().Interface().(*Model)
+	model.meta = m
+	model.query = qb
+	model.value = value
+	model.original = xun.R{}
+	model.exists = false
+	return model
+}
+
+// Table Get the table name of the model
+func (model *Model) Table() string {
+	return model.meta.table
+}
+
+// PrimaryKey Get the primary key name of the model
+func (model *Model) PrimaryKey() string {
+	return model.meta.primary
+}
+
+// Key Get the value of the primary key
+func (model *Model) Key() interface{} {
+	return model.field(model.meta.primary).Interface()
+}
+
+// Exists Determine if the model exists in the database
+func (model *Model) Exists() bool {
+	return model.exists
+}
+
+// Query Get a new query builder of the table of the model
+func (model *Model) Query() query.Query {
+	return model.query.New().Table(model.meta.table)
+}
+
+// Find Find the model by the primary key, and fill the fields with the values of the record.
+func (model *Model) Find(id interface{}) error {
+	row, err := model.Query().
+		Select(model.meta.columnNames()...).
+		Where(model.meta.primary, id).
+		First()
+	if err != nil {
+		return err
+	}
+
+	if row.IsEmpty() {
+		return fmt.Errorf("the %s %v is not found", model.meta.table, id)
+	}
+
+	err = model.Fill(row)
+	if err != nil {
+		return err
+	}
+
+	model.exists = true
+	return model.SyncOriginal()
+}
+
+// MustFind Find the model by the primary key
+func (model *Model) MustFind(id interface{}) {
+	err := model.Find(id)
+	utils.PanicIF(err)
+}
+
+// Fill Fill the fields of the model with the values, the keys are the column names, e.g.
+//    err := user.Fill(xun.R{"name": "Ken", "vote": 10})
+func (model *Model) Fill(v interface{}) error {
+	return model.fill(xun.MakeR(v), true)
+}
+
+// MustFill Fill the fields of the model with the values
+func (model *Model) MustFill(v interface{}) {
+	err := model.Fill(v)
+	utils.PanicIF(err)
+}
+
+// fill the fields with the values, the columns not defined in the model are skipped unless strict is true.
+func (model *Model) fill(values xun.R, strict bool) error {
+	for column, value := range values {
+		if _, has := model.meta.columns[column]; !has {
+			if !strict {
+				continue
+			}
+			return fmt.Errorf("the column %s is not a field of the %s model", column, model.meta.table)
+		}
+		err := assign(model.field(column), value)
+		if err != nil {
+			return fmt.Errorf("the column %s: %s", column, err)
+		}
+	}
+	return nil
+}
+
+// Create Insert the model into the database.
+// The primary key is generated by the database if it is the zero value.
+func (model *Model) Create() error {
+	values, err := model.GetAttributes()
+	if err != nil {
+		return err
+	}
+
+	primary := model.meta.primary
+	if !model.field(primary).IsZero() {
+		err = model.Query().Insert(values)
+		if err != nil {
+			return err
+		}
+		model.exists = true
+		return model.SyncOriginal()
+	}
+
+	values.Del(primary)
+	id, err := model.Query().InsertGetID(values, primary)
+	if err != nil {
+		return err
+	}
+
+	err = assign(model.field(primary), id)
+	if err != nil {
+		return err
+	}
+
+	model.exists = true
+	return model.SyncOriginal()
+}
+
+// MustCreate Insert the model into the database
+func (model *Model) MustCreate() {
+	err := model.Create()
+	utils.PanicIF(err)
+}
+
+// Save Save the model to the database, the model is created if it does not exist,
+// otherwise the dirty columns are updated.
+func (model *Model) Save() error {
+	if !model.exists {
+		return model.Create()
+	}
+
+	dirty, err := model.GetDirty()
+	if err != nil {
+		return err
+	}
+
+	if len(dirty) == 0 {
+		return nil
+	}
+
+	_, err = model.Query().Where(model.meta.primary, model.originalKey()).Update(dirty)
+	if err != nil {
+		return err
+	}
+
+	return model.SyncOriginal()
+}
+
+// MustSave Save the model to the database
+func (model *Model) MustSave() {
+	err := model.Save()
+	utils.PanicIF(err)
+}
+
+// Delete Delete the model from the database
+func (model *Model) Delete() error {
+	if !model.exists {
+		return fmt.Errorf("the %s model does not exist", model.meta.table)
+	}
+
+	_, err := model.Query().Where(model.meta.primary, model.originalKey()).Delete()
+	if err != nil {
+		return err
+	}
+
+	model.exists = false
+	return nil
+}
+
+// MustDelete Delete the model from the database
+func (model *Model) MustDelete() {
+	err := model.Delete()
+	utils.PanicIF(err)
+}
+
+// field get the struct field of the column
+func (model *Model) field(column string) reflect.Value {
+	return model.value.FieldByIndex(model.meta.fields[model.meta.columns[column]].index)
+}
+
+// originalKey get the primary key of the record, the primary key could be changed before saving.
+func (model *Model) originalKey() interface{} {
+	if key, has := model.original[model.meta.primary]; has {
+		return key
+	}
+	return model.Key()
+}
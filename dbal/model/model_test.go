@@ -0,0 +1,207 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+var testSchema schema.Schema
+var testQuery query.Query
+
+func getTestSchema() schema.Schema {
+	unit.SetLogger()
+	if testSchema == nil {
+		testSchema = schema.New(unit.Driver(), unit.DSN())
+	}
+	return testSchema
+}
+
+func getTestQuery() query.Query {
+	unit.SetLogger()
+	if testQuery == nil {
+		testQuery = query.New(unit.Driver(), unit.DSN())
+	}
+	return testQuery
+}
+
+type testUser struct {
+	Model   `json:"-" xun:"table:table_test_model_users,primary:id"`
+	ID      int64                  `json:"id"`
+	Email   string                 `json:"email"`
+	Name    *string                `json:"name,omitempty"`
+	Vote    int                    `json:"vote"`
+	Score   float64                `json:"score"`
+	Options map[string]interface{} `json:"options"`
+	Note    string                 `json:"-"`
+}
+
+type testAuthor struct {
+	Model
+	ID    int64
+	Email string
+}
+
+func TestModelBind(t *testing.T) {
+	user := &testUser{}
+	err := Bind(getTestQuery(), user)
+	assert.Nil(t, err, "the return error should be nil")
+	assert.Equal(t, "table_test_model_users", user.Table(), "the table should be taken from the tag")
+	assert.Equal(t, "id", user.PrimaryKey(), "the primary key should be taken from the tag")
+	assert.False(t, user.Exists(), "the model should not exist")
+	assert.Equal(t, []interface{}{"id", "email", "name", "vote", "score", "options"}, user.meta.columnNames(), "the columns should be mapped by the json tags")
+
+	author := &testAuthor{}
+	MustBind(getTestQuery(), author)
+	assert.Equal(t, "test_author", author.Table(), "the table should be the snake case of the struct name")
+	assert.Equal(t, []interface{}{"id", "email"}, author.meta.columnNames(), "the columns should be the snake case of the field names")
+}
+
+func TestModelBindError(t *testing.T) {
+	err := Bind(getTestQuery(), testUser{})
+	assert.Equal(t, "the model should be a pointer of struct, model.testUser given", err.Error(), "the error message should be returned")
+
+	err = Bind(getTestQuery(), &struct{ ID int }{})
+	assert.Equal(t, "the  struct should embed the model.Model", err.Error(), "the error message should be returned")
+
+	err = Bind(getTestQuery(), &struct {
+		Model `xun:"primary:uid"`
+		ID    int
+	}{})
+	assert.Equal(t, "the primary key uid is not a field of the  struct", err.Error(), "the error message should be returned")
+}
+
+func TestModelFind(t *testing.T) {
+	NewTableForModelTest()
+	user := &testUser{}
+	MustBind(getTestQuery(), user)
+	user.MustFind(2)
+
+	assert.True(t, user.Exists(), "the model should exist")
+	assert.Equal(t, int64(2), user.ID, "the id should be 2")
+	assert.Equal(t, "lee@yao.run", user.Email, "the email should be lee@yao.run")
+	assert.Equal(t, "Lee", *user.Name, "the name should be Lee")
+	assert.Equal(t, 5, user.Vote, "the vote should be 5")
+	assert.Equal(t, 64.56, user.Score, "the score should be 64.56")
+	assert.Equal(t, "dark", user.Options["theme"], "the options should be decoded")
+	assert.True(t, user.IsClean(), "the model should be clean")
+
+	err := user.Find(99)
+	assert.Equal(t, "the table_test_model_users 99 is not found", err.Error(), "the not found error should be returned")
+}
+
+func TestModelCreate(t *testing.T) {
+	NewTableForModelTest()
+	name := "Max"
+	user := &testUser{Email: "max@yao.run", Name: &name, Vote: 3, Score: 81.5, Options: map[string]interface{}{"theme": "light"}}
+	MustBind(getTestQuery(), user)
+	user.MustCreate()
+
+	assert.True(t, user.Exists(), "the model should exist")
+	assert.Equal(t, int64(3), user.ID, "the id should be generated")
+
+	row := getTestQuery().Table("table_test_model_users").MustFind(3)
+	assert.Equal(t, "max@yao.run", row.GetString("email"), "the email should be saved")
+	assert.Equal(t, 3, row.GetInt("vote"), "the vote should be saved")
+
+	found := &testUser{}
+	MustBind(getTestQuery(), found)
+	found.MustFind(3)
+	assert.Equal(t, "light", found.Options["theme"], "the options should be saved as JSON")
+}
+
+func TestModelSave(t *testing.T) {
+	NewTableForModelTest()
+	user := &testUser{}
+	MustBind(getTestQuery(), user)
+	user.MustFind(1)
+
+	user.Vote = 11
+	user.Options["theme"] = "light"
+	assert.True(t, user.IsDirty(), "the model should be dirty")
+	assert.True(t, user.IsDirty("vote", "name"), "the vote should be dirty")
+	assert.False(t, user.IsDirty("name"), "the name should be clean")
+	assert.Equal(t, xun.R{"vote": 11, "options": `{"theme":"light"}`}, user.MustGetDirty(), "the vote and the options should be dirty")
+	assert.Equal(t, int64(10), xun.MakeN(user.GetOriginal()["vote"]).MustInt64(), "the original vote should be 10")
+
+	user.MustSave()
+	assert.True(t, user.IsClean(), "the model should be clean after saving")
+
+	row := getTestQuery().Table("table_test_model_users").MustFind(1)
+	assert.Equal(t, 11, row.GetInt("vote"), "the vote should be updated")
+	assert.Equal(t, "John", row.GetString("name"), "the name should not be changed")
+
+	user.Name = nil
+	user.MustSave()
+	row = getTestQuery().Table("table_test_model_users").MustFind(1)
+	assert.Nil(t, row["name"], "the name should be null")
+
+	created := &testUser{Email: "ada@yao.run"}
+	MustBind(getTestQuery(), created)
+	created.MustSave()
+	assert.Equal(t, int64(3), created.ID, "the model should be created if it does not exist")
+}
+
+func TestModelDelete(t *testing.T) {
+	NewTableForModelTest()
+	user := &testUser{}
+	MustBind(getTestQuery(), user)
+	user.MustFind(1)
+	user.MustDelete()
+
+	assert.False(t, user.Exists(), "the model should not exist after deleting")
+	assert.Equal(t, int64(1), getTestQuery().Table("table_test_model_users").MustCount(), "the rows count should be 1, after delete")
+
+	err := user.Delete()
+	assert.Equal(t, "the table_test_model_users model does not exist", err.Error(), "the error message should be returned")
+}
+
+func TestModelFill(t *testing.T) {
+	user := &testUser{}
+	MustBind(getTestQuery(), user)
+	user.MustFill(xun.R{"id": "7", "name": "Ken", "vote": int64(12), "score": "90.5", "options": []byte(`{"theme":"dark"}`)})
+	assert.Equal(t, int64(7), user.ID, "the id should be converted")
+	assert.Equal(t, "Ken", *user.Name, "the name should be assigned to the pointer")
+	assert.Equal(t, 12, user.Vote, "the vote should be converted")
+	assert.Equal(t, 90.5, user.Score, "the score should be converted")
+	assert.Equal(t, "dark", user.Options["theme"], "the options should be decoded")
+
+	user.MustFill(map[string]interface{}{"name": nil})
+	assert.Nil(t, user.Name, "the name should be nil")
+
+	err := user.Fill(xun.R{"note": "hello"})
+	assert.Equal(t, "the column note is not a field of the table_test_model_users model", err.Error(), "the error message should be returned")
+
+	err = user.Fill(xun.R{"vote": []int{1}})
+	assert.Equal(t, "the column vote: the value [1] ([]int) could not be assigned to int", err.Error(), "the error message should be returned")
+}
+
+// clean the test data
+func TestModelClean(t *testing.T) {
+	builder := getTestSchema()
+	builder.DropTableIfExists("table_test_model_users")
+}
+
+func NewTableForModelTest() {
+	defer unit.Catch()
+	builder := getTestSchema()
+	builder.DropTableIfExists("table_test_model_users")
+	builder.MustCreateTable("table_test_model_users", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.String("name").Null()
+		table.Integer("vote").SetDefault(0)
+		table.Float("score", 5, 2).SetDefault(0)
+		table.JSON("options").Null()
+	})
+
+	qb := getTestQuery()
+	qb.Table("table_test_model_users").Insert([]xun.R{
+		{"email": "john@yao.run", "name": "John", "vote": 10, "score": 96.32, "options": `{"theme":"dark"}`},
+		{"email": "lee@yao.run", "name": "Lee", "vote": 5, "score": 64.56, "options": `{"theme":"dark"}`},
+	})
+}
@@ -0,0 +1,422 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/xun/utils"
+)
+
+// the alias of the pivot key selected with the related models of the belongsToMany relations
+const pivotKey = "xun_pivot_key"
+
+// Load Load the relations of the model, the nested relations are separated by dots, e.g.
+//    err := user.Load("posts.comments", "profile")
+func (model *Model) Load(relations ...string) error {
+	return eagerLoad(model.query, model.meta, []reflect.Value{model.value.Addr()}, relations)
+}
+
+// MustLoad Load the relations of the model
+func (model *Model) MustLoad(relations ...string) {
+	err := model.Load(relations...)
+	utils.PanicIF(err)
+}
+
+// eagerLoad load the relations of the models, one query is executed for each relation.
+func eagerLoad(qb query.Query, m *meta, parents []reflect.Value, relations []string) error {
+	if len(parents) == 0 || len(relations) == 0 {
+		return nil
+	}
+
+	names := []string{}
+	nested := map[string][]string{}
+	for _, path := range relations {
+		segments := strings.SplitN(path, ".", 2)
+		name := segments[0]
+		if _, has := nested[name]; !has {
+			names = append(names, name)
+			nested[name] = []string{}
+		}
+		if len(segments) == 2 {
+			nested[name] = append(nested[name], segments[1])
+		}
+	}
+
+	for _, name := range names {
+		rel, has := m.relations[name]
+		if !has {
+			return fmt.Errorf("the relation %s is not defined on the %s model", name, m.table)
+		}
+
+		children, err := rel.load(qb, m, parents)
+		if err != nil {
+			return err
+		}
+
+		if len(nested[name]) == 0 {
+			continue
+		}
+
+		// the children of the morphTo relations could be different models
+		types := []reflect.Type{}
+		groups := map[reflect.Type][]reflect.Value{}
+		for _, child := range children {
+			typ := child.Elem().Type()
+			if _, has := groups[typ]; !has {
+				types = append(types, typ)
+			}
+			groups[typ] = append(groups[typ], child)
+		}
+
+		for _, typ := range types {
+			target, err := getMeta(typ)
+			if err != nil {
+				return err
+			}
+			err = eagerLoad(qb, target, groups[typ], nested[name])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// load query the related models of the parents, and set them to the relation fields of the parents.
+func (rel *relation) load(qb query.Query, m *meta, parents []reflect.Value) ([]reflect.Value, error) {
+	switch rel.typ {
+	case "belongsTo":
+		return rel.loadBelongsTo(qb, m, parents)
+	case "belongsToMany":
+		return rel.loadBelongsToMany(qb, m, parents)
+	case "morphTo":
+		return rel.loadMorphTo(qb, m, parents)
+	}
+	return rel.loadHas(qb, m, parents)
+}
+
+// loadHas load the hasOne, hasMany, morphOne and morphMany relations
+func (rel *relation) loadHas(qb query.Query, m *meta, parents []reflect.Value) ([]reflect.Value, error) {
+	target, err := getMeta(rel.target)
+	if err != nil {
+		return nil, err
+	}
+
+	foreign := rel.foreign
+	if rel.morph != "" {
+		foreign = fmt.Sprintf("%s_id", rel.morph)
+	}
+
+	keys, err := m.keys(parents, rel.local)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []reflect.Value{}
+	if len(keys) > 0 {
+		related := qb.New().Table(target.table).
+			Select(target.columnNames()...).
+			WhereIn(foreign, keys)
+		if rel.morph != "" {
+			related.Where(fmt.Sprintf("%s_type", rel.morph), m.table)
+		}
+
+		rows, err := related.OrderBy(target.primary).Get()
+		if err != nil {
+			return nil, err
+		}
+
+		children, err = newModels(qb, target, rows)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	groups, err := target.group(children, foreign)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, parent := range parents {
+		key, err := m.key(parent, rel.local)
+		if err != nil {
+			return nil, err
+		}
+		rel.set(parent, groups[key])
+	}
+
+	return children, nil
+}
+
+// loadBelongsTo load the belongsTo relations
+func (rel *relation) loadBelongsTo(qb query.Query, m *meta, parents []reflect.Value) ([]reflect.Value, error) {
+	target, err := getMeta(rel.target)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := rel.owner
+	if owner == "" {
+		owner = target.primary
+	}
+
+	keys, err := m.keys(parents, rel.foreign)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []reflect.Value{}
+	if len(keys) > 0 {
+		rows, err := qb.New().Table(target.table).
+			Select(target.columnNames()...).
+			WhereIn(owner, keys).
+			Get()
+		if err != nil {
+			return nil, err
+		}
+
+		children, err = newModels(qb, target, rows)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	groups, err := target.group(children, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, parent := range parents {
+		key, err := m.key(parent, rel.foreign)
+		if err != nil {
+			return nil, err
+		}
+		rel.set(parent, groups[key])
+	}
+
+	return children, nil
+}
+
+// loadBelongsToMany load the belongsToMany relations, the related models are joined with the pivot table.
+func (rel *relation) loadBelongsToMany(qb query.Query, m *meta, parents []reflect.Value) ([]reflect.Value, error) {
+	target, err := getMeta(rel.target)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := rel.owner
+	if owner == "" {
+		owner = target.primary
+	}
+
+	keys, err := m.keys(parents, rel.local)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []reflect.Value{}
+	groups := map[string][]reflect.Value{}
+	if len(keys) > 0 {
+		columns := append(target.qualifiedColumnNames(), fmt.Sprintf("%s.%s as %s", rel.pivot, rel.foreign, pivotKey))
+		rows, err := qb.New().Table(target.table).
+			Select(columns...).
+			Join(rel.pivot, fmt.Sprintf("%s.%s", rel.pivot, rel.related), "=", fmt.Sprintf("%s.%s", target.table, owner)).
+			WhereIn(fmt.Sprintf("%s.%s", rel.pivot, rel.foreign), keys).
+			OrderBy(fmt.Sprintf("%s.%s", target.table, target.primary)).
+			Get()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			key := fmt.Sprintf("%v", row[pivotKey])
+			row.Del(pivotKey)
+			child, err := newModel(qb, target, row)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child)
+			groups[key] = append(groups[key], child)
+		}
+	}
+
+	for _, parent := range parents {
+		key, err := m.key(parent, rel.local)
+		if err != nil {
+			return nil, err
+		}
+		rel.set(parent, groups[key])
+	}
+
+	return children, nil
+}
+
+// loadMorphTo load the morphTo relations, the related models are found by the table names in the {morph}_type column.
+func (rel *relation) loadMorphTo(qb query.Query, m *meta, parents []reflect.Value) ([]reflect.Value, error) {
+	typeColumn := fmt.Sprintf("%s_type", rel.morph)
+	idColumn := fmt.Sprintf("%s_id", rel.morph)
+
+	tables := []string{}
+	groups := map[string][]reflect.Value{}
+	for _, parent := range parents {
+		table, err := m.key(parent, typeColumn)
+		if err != nil {
+			return nil, err
+		}
+		if _, has := groups[table]; !has {
+			tables = append(tables, table)
+		}
+		groups[table] = append(groups[table], parent)
+	}
+
+	children := []reflect.Value{}
+	for _, table := range tables {
+		typ, has := morphTypes.Load(table)
+		if !has {
+			if table != "" {
+				return nil, fmt.Errorf("the model of the morph type %s is not registered", table)
+			}
+			for _, parent := range groups[table] {
+				rel.set(parent, nil)
+			}
+			continue
+		}
+
+		target, err := getMeta(typ.(reflect.Type))
+		if err != nil {
+			return nil, err
+		}
+
+		keys, err := m.keys(groups[table], idColumn)
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err := qb.New().Table(target.table).
+			Select(target.columnNames()...).
+			WhereIn(target.primary, keys).
+			Get()
+		if err != nil {
+			return nil, err
+		}
+
+		related, err := newModels(qb, target, rows)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, related...)
+
+		owners, err := target.group(related, target.primary)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, parent := range groups[table] {
+			key, err := m.key(parent, idColumn)
+			if err != nil {
+				return nil, err
+			}
+			rel.set(parent, owners[key])
+		}
+	}
+
+	return children, nil
+}
+
+// set the related models to the relation field of the parent
+func (rel *relation) set(parent reflect.Value, children []reflect.Value) {
+	field := parent.Elem().FieldByIndex(rel.index)
+	if field.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(field.Type(), 0, len(children))
+		slice = reflect.Append(slice, children...)
+		field.Set(slice)
+		return
+	}
+
+	if len(children) == 0 {
+		field.Set(reflect.Zero(field.Type()))
+		return
+	}
+	field.Set(children[0])
+}
+
+// newModels create the models with the rows
+func newModels(qb query.Query, m *meta, rows []xun.R) ([]reflect.Value, error) {
+	models := []reflect.Value{}
+	for _, row := range rows {
+		model, err := newModel(qb, m, row)
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+// newModel create a model existing in the database with the row, returns the pointer of the struct
+func newModel(qb query.Query, m *meta, row xun.R) (reflect.Value, error) {
+	value := reflect.New(m.typ)
+	model := bind(qb, m, value.Elem())
+	err := model.fill(row, false)
+	if err != nil {
+		return value, err
+	}
+
+	model.exists = true
+	return value, model.SyncOriginal()
+}
+
+// key get the value of the column of the model as the key to match the related models
+func (m *meta) key(model reflect.Value, column string) (string, error) {
+	index, has := m.columns[column]
+	if !has {
+		return "", fmt.Errorf("the column %s is not a field of the %s model", column, m.table)
+	}
+
+	value, err := dbValue(model.Elem().FieldByIndex(m.fields[index].index))
+	if err != nil || value == nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
+// keys get the unique values of the column of the models, the null values are skipped.
+func (m *meta) keys(models []reflect.Value, column string) ([]interface{}, error) {
+	index, has := m.columns[column]
+	if !has {
+		return nil, fmt.Errorf("the column %s is not a field of the %s model", column, m.table)
+	}
+
+	keys := []interface{}{}
+	unique := map[string]bool{}
+	for _, model := range models {
+		value, err := dbValue(model.Elem().FieldByIndex(m.fields[index].index))
+		if err != nil {
+			return nil, err
+		}
+		key := fmt.Sprintf("%v", value)
+		if value == nil || unique[key] {
+			continue
+		}
+		unique[key] = true
+		keys = append(keys, value)
+	}
+	return keys, nil
+}
+
+// group the models by the values of the column
+func (m *meta) group(models []reflect.Value, column string) (map[string][]reflect.Value, error) {
+	groups := map[string][]reflect.Value{}
+	for _, model := range models {
+		key, err := m.key(model, column)
+		if err != nil {
+			return nil, err
+		}
+		groups[key] = append(groups[key], model)
+	}
+	return groups, nil
+}
@@ -0,0 +1,259 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+type testRelationUser struct {
+	Model   `json:"-" xun:"table:table_test_relation_users"`
+	ID      int64                `json:"id"`
+	Name    string               `json:"name"`
+	Profile *testRelationProfile `json:"profile" xun:"hasOne,foreign:user_id"`
+	Posts   []*testRelationPost  `json:"posts" xun:"hasMany,foreign:user_id"`
+}
+
+type testRelationProfile struct {
+	Model  `json:"-" xun:"table:table_test_relation_profiles"`
+	ID     int64  `json:"id"`
+	UserID int64  `json:"user_id"`
+	Bio    string `json:"bio"`
+}
+
+type testRelationPost struct {
+	Model    `json:"-" xun:"table:table_test_relation_posts"`
+	ID       int64                  `json:"id"`
+	UserID   int64                  `json:"user_id"`
+	Title    string                 `json:"title"`
+	User     *testRelationUser      `json:"user" xun:"belongsTo,foreign:user_id"`
+	Tags     []*testRelationTag     `json:"tags" xun:"belongsToMany,pivot:table_test_relation_post_tags,foreign:post_id,related:tag_id"`
+	Comments []*testRelationComment `json:"comments" xun:"morphMany,morph:commentable"`
+}
+
+type testRelationVideo struct {
+	Model    `json:"-" xun:"table:table_test_relation_videos"`
+	ID       int64                  `json:"id"`
+	Title    string                 `json:"title"`
+	Comments []*testRelationComment `json:"comments" xun:"morphMany,morph:commentable"`
+}
+
+type testRelationComment struct {
+	Model           `json:"-" xun:"table:table_test_relation_comments"`
+	ID              int64       `json:"id"`
+	Body            string      `json:"body"`
+	CommentableType string      `json:"commentable_type"`
+	CommentableID   int64       `json:"commentable_id"`
+	Commentable     interface{} `json:"commentable" xun:"morphTo"`
+}
+
+type testRelationTag struct {
+	Model `json:"-" xun:"table:table_test_relation_tags"`
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+}
+
+func TestRelationWith(t *testing.T) {
+	NewTableForRelationTest()
+	users := []*testRelationUser{}
+	err := Query(getTestQuery().New(), &users).
+		With("posts.comments", "posts.tags", "profile").
+		Get()
+	assert.Nil(t, err, "the return error should be nil")
+	assert.Equal(t, 3, len(users), "the users should have 3 models")
+	if len(users) != 3 {
+		return
+	}
+
+	john := users[0]
+	assert.True(t, john.Exists(), "the loaded models should exist")
+	assert.Equal(t, "John", john.Name, "the name of the 1st user should be John")
+	assert.Equal(t, "Gopher", john.Profile.Bio, "the profile of John should be loaded")
+	assert.Equal(t, 2, len(john.Posts), "John should have 2 posts")
+	if len(john.Posts) == 2 {
+		assert.Equal(t, "Hello Xun", john.Posts[0].Title, "the posts should be ordered by the id")
+		assert.Equal(t, 2, len(john.Posts[0].Comments), "the 1st post of John should have 2 comments")
+		assert.Equal(t, 0, len(john.Posts[1].Comments), "the 2nd post of John should have no comments")
+		assert.Equal(t, []string{"go", "sql"}, testRelationTagNames(john.Posts[0].Tags), "the tags of the 1st post should be loaded")
+		assert.Equal(t, []string{"go"}, testRelationTagNames(john.Posts[1].Tags), "the tags of the 2nd post should be loaded")
+	}
+
+	lee := users[1]
+	assert.Equal(t, 1, len(lee.Posts), "Lee should have 1 post")
+	assert.Nil(t, lee.Profile, "Lee has no profile")
+
+	ken := users[2]
+	assert.Equal(t, []*testRelationPost{}, ken.Posts, "Ken should have no posts")
+}
+
+func TestRelationBelongsTo(t *testing.T) {
+	NewTableForRelationTest()
+	posts := []*testRelationPost{}
+	Query(getTestQuery().New().Where("title", "<>", "Hello Xun"), &posts).With("user.profile").MustGet()
+	assert.Equal(t, 2, len(posts), "the posts should have 2 models")
+	if len(posts) == 2 {
+		assert.Equal(t, "John", posts[0].User.Name, "the user of the 1st post should be John")
+		assert.Equal(t, "Gopher", posts[0].User.Profile.Bio, "the nested relation should be loaded")
+		assert.Equal(t, "Lee", posts[1].User.Name, "the user of the 2nd post should be Lee")
+		assert.Nil(t, posts[1].User.Profile, "Lee has no profile")
+	}
+}
+
+func TestRelationMorphTo(t *testing.T) {
+	NewTableForRelationTest()
+	MustRegister(&testRelationPost{}, &testRelationVideo{})
+	comments := []*testRelationComment{}
+	Query(getTestQuery().New().OrderBy("id"), &comments).With("commentable.comments").MustGet()
+	assert.Equal(t, 3, len(comments), "the comments should have 3 models")
+	if len(comments) == 3 {
+		post, ok := comments[0].Commentable.(*testRelationPost)
+		assert.True(t, ok, "the commentable of the 1st comment should be a post")
+		if ok {
+			assert.Equal(t, "Hello Xun", post.Title, "the commentable of the 1st comment should be Hello Xun")
+			assert.Equal(t, 2, len(post.Comments), "the nested relation of the post should be loaded")
+		}
+
+		video, ok := comments[2].Commentable.(*testRelationVideo)
+		assert.True(t, ok, "the commentable of the 3rd comment should be a video")
+		if ok {
+			assert.Equal(t, "Xun in 5 minutes", video.Title, "the commentable of the 3rd comment should be Xun in 5 minutes")
+			assert.Equal(t, 1, len(video.Comments), "the nested relation of the video should be loaded")
+		}
+	}
+}
+
+func TestRelationFirstAndLoad(t *testing.T) {
+	NewTableForRelationTest()
+	user := &testRelationUser{}
+	Query(getTestQuery().New().Where("name", "Lee"), user).With("posts").MustFirst()
+	assert.Equal(t, "Lee", user.Name, "the name should be Lee")
+	assert.Equal(t, 1, len(user.Posts), "the posts should be loaded")
+	assert.True(t, user.IsClean(), "the model should be clean")
+
+	found := &testRelationUser{}
+	MustBind(getTestQuery(), found)
+	found.MustFind(1)
+	assert.Nil(t, found.Posts, "the posts should not be loaded")
+	found.MustLoad("posts.user", "profile")
+	assert.Equal(t, 2, len(found.Posts), "the posts should be loaded")
+	if len(found.Posts) == 2 {
+		assert.Equal(t, "John", found.Posts[0].User.Name, "the user of the posts should be loaded")
+	}
+	assert.Equal(t, "Gopher", found.Profile.Bio, "the profile should be loaded")
+
+	err := Query(getTestQuery().New().Where("name", "Max"), &testRelationUser{}).First()
+	assert.Equal(t, "the table_test_relation_users is not found", err.Error(), "the not found error should be returned")
+}
+
+func TestRelationError(t *testing.T) {
+	NewTableForRelationTest()
+	user := &testRelationUser{}
+	MustBind(getTestQuery(), user)
+	user.MustFind(1)
+	err := user.Load("comments")
+	assert.Equal(t, "the relation comments is not defined on the table_test_relation_users model", err.Error(), "the error message should be returned")
+
+	users := []testRelationUser{}
+	err = Query(getTestQuery().New(), &users).Get()
+	assert.Equal(t, "the models should be a pointer of a slice of the model pointers, *[]model.testRelationUser given", err.Error(), "the error message should be returned")
+
+	err = Register(&struct {
+		Model
+		ID    int64
+		Posts testRelationPost `xun:"hasMany"`
+	}{})
+	assert.Equal(t, "the hasMany relation posts should be a slice of the model pointers, model.testRelationPost given", err.Error(), "the error message should be returned")
+
+	err = Register(&struct {
+		Model
+		ID   int64
+		Tags []*testRelationTag `xun:"belongsToMany"`
+	}{})
+	assert.Equal(t, "the pivot table of the belongsToMany relation tags is required", err.Error(), "the error message should be returned")
+}
+
+func testRelationTagNames(tags []*testRelationTag) []string {
+	names := []string{}
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// clean the test data
+func TestRelationClean(t *testing.T) {
+	builder := getTestSchema()
+	for _, name := range testRelationTables {
+		builder.DropTableIfExists(name)
+	}
+}
+
+var testRelationTables = []string{
+	"table_test_relation_users", "table_test_relation_profiles", "table_test_relation_posts", "table_test_relation_videos",
+	"table_test_relation_comments", "table_test_relation_tags", "table_test_relation_post_tags",
+}
+
+func NewTableForRelationTest() {
+	defer unit.Catch()
+	builder := getTestSchema()
+	for _, name := range testRelationTables {
+		builder.DropTableIfExists(name)
+	}
+
+	builder.MustCreateTable("table_test_relation_users", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("name", 80)
+	})
+	builder.MustCreateTable("table_test_relation_profiles", func(table schema.Blueprint) {
+		table.ID("id")
+		table.ForeignID("user_id").Index()
+		table.String("bio")
+	})
+	builder.MustCreateTable("table_test_relation_posts", func(table schema.Blueprint) {
+		table.ID("id")
+		table.ForeignID("user_id").Index()
+		table.String("title")
+	})
+	builder.MustCreateTable("table_test_relation_videos", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("title")
+	})
+	builder.MustCreateTable("table_test_relation_comments", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("body")
+		table.Morphs("commentable")
+	})
+	builder.MustCreateTable("table_test_relation_tags", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("name", 80)
+	})
+	builder.MustCreateTable("table_test_relation_post_tags", func(table schema.Blueprint) {
+		table.ForeignID("post_id")
+		table.ForeignID("tag_id")
+		table.AddUnique("post_id_tag_id_unique", "post_id", "tag_id")
+	})
+
+	qb := getTestQuery()
+	qb.Table("table_test_relation_users").MustInsert([]xun.R{{"name": "John"}, {"name": "Lee"}, {"name": "Ken"}})
+	qb.Table("table_test_relation_profiles").MustInsert([]xun.R{{"user_id": 1, "bio": "Gopher"}, {"user_id": 3, "bio": "DBA"}})
+	qb.Table("table_test_relation_posts").MustInsert([]xun.R{
+		{"user_id": 1, "title": "Hello Xun"},
+		{"user_id": 1, "title": "Query Builder"},
+		{"user_id": 2, "title": "Schema Builder"},
+	})
+	qb.Table("table_test_relation_videos").MustInsert([]xun.R{{"title": "Xun in 5 minutes"}})
+	qb.Table("table_test_relation_comments").MustInsert([]xun.R{
+		{"body": "Great", "commentable_type": "table_test_relation_posts", "commentable_id": 1},
+		{"body": "Thanks", "commentable_type": "table_test_relation_posts", "commentable_id": 1},
+		{"body": "Nice video", "commentable_type": "table_test_relation_videos", "commentable_id": 1},
+	})
+	qb.Table("table_test_relation_tags").MustInsert([]xun.R{{"name": "go"}, {"name": "sql"}})
+	qb.Table("table_test_relation_post_tags").MustInsert([]xun.R{
+		{"post_id": 1, "tag_id": 1},
+		{"post_id": 1, "tag_id": 2},
+		{"post_id": 2, "tag_id": 1},
+	})
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/xun/utils"
+)
+
+// AddScope Register a local scope on the table of the model to the connection of the query, e.g.
+//    model.AddScope(qb, &User{}, "active", func(qb query.Query, args ...interface{}) { qb.Where("status", "active") })
+//    model.Query(qb.New(), &users).Scope("active").Get()
+func AddScope(qb query.Query, v interface{}, name string, scope query.Scope) error {
+	m, err := metaOf(v)
+	if err != nil {
+		return err
+	}
+	qb.AddScope(m.table, name, scope)
+	return nil
+}
+
+// MustAddScope Register a local scope on the table of the model
+func MustAddScope(qb query.Query, v interface{}, name string, scope query.Scope) {
+	err := AddScope(qb, v, name, scope)
+	utils.PanicIF(err)
+}
+
+// AddGlobalScope Register a global scope on the table of the model to the connection of the query, it is applied to the queries of the model,
+// including finding, saving, deleting and eager loading the models, e.g.
+//    model.AddGlobalScope(qb, &Post{}, "published", func(qb query.Query, args ...interface{}) { qb.WhereNotNull("published_at") })
+func AddGlobalScope(qb query.Query, v interface{}, name string, scope query.Scope) error {
+	m, err := metaOf(v)
+	if err != nil {
+		return err
+	}
+	qb.AddGlobalScope(m.table, name, scope)
+	return nil
+}
+
+// MustAddGlobalScope Register a global scope on the table of the model
+func MustAddGlobalScope(qb query.Query, v interface{}, name string, scope query.Scope) {
+	err := AddGlobalScope(qb, v, name, scope)
+	utils.PanicIF(err)
+}
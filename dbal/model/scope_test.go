@@ -0,0 +1,53 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/dbal/query"
+)
+
+func TestScopeAddScope(t *testing.T) {
+	NewTableForRelationTest()
+	MustAddScope(getTestQuery(), &testRelationUser{}, "named", func(qb query.Query, args ...interface{}) {
+		qb.WhereIn("name", args)
+	})
+	defer getTestQuery().RemoveScope("table_test_relation_users", "named")
+
+	users := []*testRelationUser{}
+	Query(getTestQuery().New(), &users).Scope("named", "Lee", "Ken").MustGet()
+	assert.Equal(t, 2, len(users), "the scope should be applied")
+	if len(users) == 2 {
+		assert.Equal(t, "Lee", users[0].Name, "the name of the 1st user should be Lee")
+		assert.Equal(t, "Ken", users[1].Name, "the name of the 2nd user should be Ken")
+	}
+
+	err := Query(getTestQuery().New(), &users).Scope("undefined").Get()
+	assert.EqualError(t, err, "the scope undefined is not defined on the table_test_relation_users table", "the undefined scope should return an error")
+
+	err = AddScope(getTestQuery(), "table_test_relation_users", "named", nil)
+	assert.Equal(t, "the model should be a struct, string given", err.Error(), "the error message should be returned")
+}
+
+func TestScopeAddGlobalScope(t *testing.T) {
+	NewTableForRelationTest()
+	MustAddGlobalScope(getTestQuery(), &testRelationPost{}, "hidden", func(qb query.Query, args ...interface{}) {
+		qb.Where("title", "<>", "Query Builder")
+	})
+	defer getTestQuery().RemoveScope("table_test_relation_posts", "hidden")
+
+	user := &testRelationUser{}
+	MustBind(getTestQuery(), user)
+	user.MustFind(1)
+	user.MustLoad("posts")
+	assert.Equal(t, 1, len(user.Posts), "the global scope should be applied to the eager loading")
+
+	post := &testRelationPost{}
+	MustBind(getTestQuery(), post)
+	err := post.Find(2)
+	assert.Equal(t, "the table_test_relation_posts 2 is not found", err.Error(), "the global scope should be applied to the find")
+
+	posts := []*testRelationPost{}
+	Query(getTestQuery().New(), &posts).WithoutGlobalScope("hidden").MustGet()
+	assert.Equal(t, 3, len(posts), "the global scope should be removed")
+}
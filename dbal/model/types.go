@@ -0,0 +1,68 @@
+package model
+
+import (
+	"reflect"
+
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/query"
+)
+
+// Model the base model, embed it in a struct to bind the struct to a table, e.g.
+//    type User struct {
+//        model.Model `json:"-" xun:"table:users,primary:id"`
+//        ID          int64  `json:"id"`
+//        Name        string `json:"name"`
+//    }
+// The fields are mapped to the columns by the json tags, the same as the Get method of the query builder.
+type Model struct {
+	meta     *meta
+	query    query.Query
+	value    reflect.Value // the struct embedding the model
+	original xun.R
+	exists   bool
+}
+
+// Builder the query builder of the models, the relations are eager loaded with the models, e.g.
+//    users := []*User{}
+//    err := model.Query(qb.Where("vote", ">", 10), &users).With("posts.comments").Get()
+type Builder struct {
+	query  query.Query
+	value  interface{}
+	with   []string
+	scopes []func(qb query.Query) error // the local scopes applied after the table of the model is set
+}
+
+// meta the table binding of a model struct
+type meta struct {
+	typ       reflect.Type
+	table     string
+	primary   string
+	base      []int // the index of the embedded model
+	fields    []field
+	columns   map[string]int
+	relations map[string]*relation
+}
+
+// field the column binding of a struct field
+type field struct {
+	column string
+	index  []int
+}
+
+// relation the relation binding of a struct field, e.g.
+//    Posts    []*Post `json:"posts" xun:"hasMany,foreign:user_id"`
+//    User     *User   `json:"user" xun:"belongsTo,foreign:user_id"`
+//    Tags     []*Tag  `json:"tags" xun:"belongsToMany,pivot:post_tags,foreign:post_id,related:tag_id"`
+//    Comments []*Comment `json:"comments" xun:"morphMany,morph:commentable"`
+type relation struct {
+	name    string
+	typ     string       // hasOne, hasMany, belongsTo, belongsToMany, morphOne, morphMany, morphTo
+	index   []int        // the index of the struct field
+	target  reflect.Type // the struct type of the related model, nil for morphTo
+	foreign string       // the foreign key on the related model, on the model for belongsTo, on the pivot table for belongsToMany
+	local   string       // the key on the model referenced by the foreign key
+	owner   string       // the key on the related model referenced by the foreign key of belongsTo and the pivot table
+	pivot   string       // the pivot table of belongsToMany
+	related string       // the key on the pivot table referencing the related model
+	morph   string       // the name of the polymorphic relation, the columns are {morph}_type and {morph}_id
+}
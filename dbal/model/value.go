@@ -0,0 +1,183 @@
+package model
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/yaoapp/xun"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// assign set the value to the struct field, the value is converted to the type of the field, e.g.
+//    "12" => int, int64 => float64, "2021-06-01 12:30:00" => time.Time, `{"theme":"dark"}` => map[string]interface{}
+func assign(dest reflect.Value, value interface{}) error {
+	if bytes, ok := value.([]byte); ok {
+		value = string(bytes)
+	}
+
+	if value == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+
+	if reflect.PtrTo(dest.Type()).Implements(scannerType) {
+		return dest.Addr().Interface().(sql.Scanner).Scan(value)
+	}
+
+	if dest.Kind() == reflect.Ptr {
+		ptr := reflect.New(dest.Type().Elem())
+		err := assign(ptr.Elem(), value)
+		if err != nil {
+			return err
+		}
+		dest.Set(ptr)
+		return nil
+	}
+
+	src := reflect.ValueOf(value)
+	if src.Type().AssignableTo(dest.Type()) {
+		dest.Set(src)
+		return nil
+	}
+
+	var err error
+	switch dest.Kind() {
+	case reflect.Bool:
+		var v bool
+		if src.Kind() == reflect.String {
+			v, err = strconv.ParseBool(src.String())
+		} else if isNumber(src) {
+			v = src.Convert(reflect.TypeOf(float64(0))).Float() != 0
+		} else {
+			return assignError(dest, value)
+		}
+		dest.SetBool(v)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var v int64
+		if src.Kind() == reflect.String {
+			v, err = strconv.ParseInt(src.String(), 10, 64)
+		} else if isNumber(src) {
+			v = src.Convert(reflect.TypeOf(int64(0))).Int()
+		} else if src.Kind() == reflect.Bool && src.Bool() {
+			v = 1
+		} else if src.Kind() != reflect.Bool {
+			return assignError(dest, value)
+		}
+		dest.SetInt(v)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var v uint64
+		if src.Kind() == reflect.String {
+			v, err = strconv.ParseUint(src.String(), 10, 64)
+		} else if isNumber(src) {
+			v = src.Convert(reflect.TypeOf(uint64(0))).Uint()
+		} else {
+			return assignError(dest, value)
+		}
+		dest.SetUint(v)
+
+	case reflect.Float32, reflect.Float64:
+		var v float64
+		if src.Kind() == reflect.String {
+			v, err = strconv.ParseFloat(src.String(), 64)
+		} else if isNumber(src) {
+			v = src.Convert(reflect.TypeOf(float64(0))).Float()
+		} else {
+			return assignError(dest, value)
+		}
+		dest.SetFloat(v)
+
+	case reflect.String:
+		if !isNumber(src) && src.Kind() != reflect.Bool && src.Kind() != reflect.String {
+			return assignError(dest, value)
+		}
+		dest.SetString(fmt.Sprintf("%v", value))
+
+	case reflect.Struct:
+		if dest.Type() == timeType && src.Kind() == reflect.String {
+			var v time.Time
+			v, err = xun.MakeTime(src.String()).ToTime()
+			if err == nil {
+				dest.Set(reflect.ValueOf(v))
+			}
+			break
+		}
+		fallthrough
+
+	case reflect.Map, reflect.Slice, reflect.Array:
+		if src.Kind() != reflect.String {
+			return assignError(dest, value)
+		}
+		err = json.Unmarshal([]byte(src.String()), dest.Addr().Interface())
+
+	default:
+		return assignError(dest, value)
+	}
+
+	return err
+}
+
+// dbValue get the value of the struct field to be written to the database.
+// The values implement the driver.Valuer are converted by it, the maps, slices and structs except the time are encoded as JSON.
+func dbValue(value reflect.Value) (interface{}, error) {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil
+		}
+		if !value.Type().Implements(valuerType) {
+			return dbValue(value.Elem())
+		}
+	}
+
+	if value.Type().Implements(valuerType) {
+		return value.Interface().(driver.Valuer).Value()
+	}
+
+	if value.CanAddr() && reflect.PtrTo(value.Type()).Implements(valuerType) {
+		return value.Addr().Interface().(driver.Valuer).Value()
+	}
+
+	if value.Type() == timeType {
+		return value.Interface(), nil
+	}
+
+	switch value.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+			return value.Interface(), nil
+		}
+		if (value.Kind() == reflect.Map || value.Kind() == reflect.Slice) && value.IsNil() {
+			return nil, nil
+		}
+		bytes, err := json.Marshal(value.Interface())
+		if err != nil {
+			return nil, err
+		}
+		return string(bytes), nil
+	}
+
+	return value.Interface(), nil
+}
+
+func isNumber(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func assignError(dest reflect.Value, value interface{}) error {
+	return fmt.Errorf("the value %v (%T) could not be assigned to %s", value, value, dest.Type())
+}
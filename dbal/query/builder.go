@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun/dbal"
@@ -102,6 +103,12 @@ func newBuilder(driver string, dsn string) *Builder {
 // useBuilder create a new schema builder instance using the given connection
 func useBuilder(conn *Connection) *Builder {
 	grammar := newGrammar(conn)
+	if conn.Scopes == nil {
+		conn.Scopes = NewScopes()
+	}
+	if conn.versionLock == nil {
+		conn.versionLock = &sync.Mutex{}
+	}
 	return &Builder{
 		Mode:     "production",
 		Conn:     conn,
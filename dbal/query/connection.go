@@ -1,6 +1,12 @@
 package query
 
-import "github.com/jmoiron/sqlx"
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
 
 // DB Get the sqlx.DB pointer instance
 func (builder *Builder) DB(usewrite ...bool) *sqlx.DB {
@@ -10,6 +16,31 @@ func (builder *Builder) DB(usewrite ...bool) *sqlx.DB {
 	return builder.Conn.Read
 }
 
+// executor Get the database executor for the query, returns the transaction if the builder is in a transaction.
+func (builder *Builder) executor(usewrite ...bool) dbal.Executor {
+	if builder.Tx != nil {
+		return builder.Tx
+	}
+	return builder.DB(usewrite...)
+}
+
+// WithContext Create a new builder instance with the given context.
+// The context is passed to the database driver, it can be used to cancel the query or to set a deadline.
+func (builder *Builder) WithContext(ctx context.Context) Query {
+	new := builder.clone()
+	new.Ctx = ctx
+	new.Grammar = builder.Grammar.WithContext(ctx)
+	return new
+}
+
+// Context Get the context of the builder, returns context.Background() if the context is not set.
+func (builder *Builder) Context() context.Context {
+	if builder.Ctx != nil {
+		return builder.Ctx
+	}
+	return context.Background()
+}
+
 // UseWrite Use the write connection for query.
 func (builder *Builder) UseWrite() Query {
 	builder.Query.UseWriteConnection = true
@@ -31,3 +62,27 @@ func (builder *Builder) IsWrite() bool {
 func (builder *Builder) IsRead() bool {
 	return !builder.Query.UseWriteConnection
 }
+
+// GetVersion Get the version of the connection database, the version is cached in the connection.
+// It is safe to call it from the builders of the connection concurrently.
+func (builder *Builder) GetVersion() (*dbal.Version, error) {
+	builder.Conn.versionLock.Lock()
+	defer builder.Conn.versionLock.Unlock()
+	if builder.Conn.Version != nil {
+		return builder.Conn.Version, nil
+	}
+
+	version, err := builder.Grammar.GetVersion()
+	if err != nil {
+		return nil, err
+	}
+	builder.Conn.Version = version
+	return version, nil
+}
+
+// MustGetVersion Get the version of the connection database.
+func (builder *Builder) MustGetVersion() *dbal.Version {
+	version, err := builder.GetVersion()
+	utils.PanicIF(err)
+	return version
+}
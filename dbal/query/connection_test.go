@@ -0,0 +1,73 @@
+package query
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+)
+
+func TestConnectionWithContext(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	ctx, cancel := context.WithCancel(context.Background())
+	ctxQuery := qb.WithContext(ctx)
+	assert.Equal(t, ctx, ctxQuery.Context(), "The context of the builder should be the given context")
+	assert.Equal(t, context.Background(), qb.Context(), "The context of the original builder should not be changed")
+
+	rows, err := ctxQuery.Table("table_test_transaction").Get()
+	assert.Nil(t, err, "The return error should be nil")
+	assert.Equal(t, 2, len(rows), "The rows count should be 2")
+
+	cancel()
+	_, err = ctxQuery.Table("table_test_transaction").Get()
+	assert.Equal(t, context.Canceled, err, "The return error should be context.Canceled")
+
+	err = ctxQuery.Table("table_test_transaction").Insert(xun.R{"email": "max@yao.run", "vote": 1})
+	assert.Equal(t, context.Canceled, err, "The return error should be context.Canceled")
+
+	_, err = ctxQuery.Table("table_test_transaction").InsertGetID(xun.R{"email": "max@yao.run", "vote": 1})
+	assert.Equal(t, context.Canceled, err, "The return error should be context.Canceled")
+
+	_, err = ctxQuery.Table("table_test_transaction").Paginate(10, 1)
+	assert.Equal(t, context.Canceled, err, "The return error should be context.Canceled")
+
+	_, err = ctxQuery.Begin()
+	assert.Equal(t, context.Canceled, err, "The return error should be context.Canceled")
+
+	assert.Equal(t, int64(2), qb.Table("table_test_transaction").MustCount(), "The rows count should be 2")
+}
+
+func TestConnectionVersionCache(t *testing.T) {
+	qb := getTestBuilder()
+	version := qb.MustGetVersion()
+
+	conn := *qb.Builder().Conn
+	conn.Version = nil
+	assert.Same(t, version, Use(&conn).MustGetVersion(), "The version should be cached per connection")
+	assert.Same(t, version, conn.Version, "The version should be cached in the new connection")
+}
+
+func TestConnectionVersionParallel(t *testing.T) {
+	conn := *getTestBuilder().Builder().Conn
+	conn.Version = nil
+	qb := Use(&conn)
+
+	var wg sync.WaitGroup
+	versions := make([]*dbal.Version, 8)
+	for i := range versions {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			versions[i] = qb.New().MustGetVersion()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, version := range versions {
+		assert.Same(t, conn.Version, version, "The version should be cached once in the connection")
+	}
+}
@@ -0,0 +1,185 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/utils"
+)
+
+// Cursor Execute the query and returns a cursor to iterate the results one by one, e.g.
+//    cursor, err := qb.Table("users").Cursor()
+//    defer cursor.Close()
+//    for cursor.Next() { row, err := cursor.Row() }
+// Only one row is kept in memory at a time, the cursor must be closed to release the connection.
+func (builder *Builder) Cursor() (*Cursor, error) {
+	err := builder.checkWindows()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := builder.executor().QueryContext(builder.Context(), builder.ToSQL(), builder.GetBindings()...)
+	if err != nil {
+		defer log.With(log.F{"bindings": builder.GetBindings()}).Error(builder.ToSQL())
+		return nil, err
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	return &Cursor{
+		rows:     rows,
+		columns:  columns,
+		fieldMap: map[reflect.Type]map[string]reflect.StructField{},
+		builder:  builder,
+	}, nil
+}
+
+// MustCursor Execute the query and returns a cursor to iterate the results one by one
+func (builder *Builder) MustCursor() *Cursor {
+	cursor, err := builder.Cursor()
+	utils.PanicIF(err)
+	return cursor
+}
+
+// Each Execute the query and feed the rows into the callback one by one, the iteration stops if the callback returns an error.
+func (builder *Builder) Each(callback func(row xun.R) error) error {
+	cursor, err := builder.Cursor()
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	for cursor.Next() {
+		row, err := cursor.Row()
+		if err != nil {
+			return err
+		}
+		if err := callback(row); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// MustEach Execute the query and feed the rows into the callback one by one
+func (builder *Builder) MustEach(callback func(row xun.R) error) {
+	err := builder.Each(callback)
+	utils.PanicIF(err)
+}
+
+// EachBind Execute the query, bind the rows to the struct pointer v and feed them into the callback one by one, e.g.
+//    user := User{}
+//    qb.Table("users").EachBind(&user, func() error { fmt.Println(user.Name); return nil })
+func (builder *Builder) EachBind(v interface{}, callback func() error) error {
+	cursor, err := builder.Cursor()
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	for cursor.Next() {
+		if err := cursor.Scan(v); err != nil {
+			return err
+		}
+		if err := callback(); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// MustEachBind Execute the query, bind the rows to the struct pointer v and feed them into the callback one by one
+func (builder *Builder) MustEachBind(v interface{}, callback func() error) {
+	err := builder.EachBind(v, callback)
+	utils.PanicIF(err)
+}
+
+// Stream Execute the query and send the rows to the returned channel, the channels are closed when the iteration is done.
+// The error channel receives at most one error. Cancel the context of the builder to stop the producer if the consumer quits early.
+func (builder *Builder) Stream(buffer int) (<-chan xun.R, <-chan error) {
+	rows := make(chan xun.R, buffer)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(rows)
+		err := builder.Each(func(row xun.R) error {
+			select {
+			case rows <- row:
+				return nil
+			case <-builder.Context().Done():
+				return builder.Context().Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return rows, errs
+}
+
+// Next Prepare the next row, returns false if there are no more rows or an error occurred.
+func (cursor *Cursor) Next() bool {
+	return cursor.rows.Next()
+}
+
+// Row Get the current row
+func (cursor *Cursor) Row() (xun.R, error) {
+	values := cursor.builder.makeMapValues(len(cursor.columns))
+	if err := cursor.rows.Scan(values...); err != nil {
+		return nil, err
+	}
+
+	row := xun.R{}
+	for i, column := range cursor.columns {
+		row[column] = cursor.builder.getValue(values[i])
+	}
+	return row, nil
+}
+
+// Scan Bind the current row to the struct pointer v
+func (cursor *Cursor) Scan(v interface{}) error {
+	structType, vStruct, err := cursor.builder.getStructType(v)
+	if err != nil {
+		return err
+	}
+
+	if !vStruct || reflect.Indirect(reflect.ValueOf(v)).Kind() == reflect.Slice {
+		return fmt.Errorf("The given binding var should be a struct pointer")
+	}
+
+	fieldMap, has := cursor.fieldMap[structType]
+	if !has {
+		fieldMap, err = cursor.builder.getFieldMap(structType)
+		if err != nil {
+			return err
+		}
+		cursor.fieldMap[structType] = fieldMap
+	}
+
+	// reset the fields, so that the values of the previous row are not kept
+	dest := reflect.ValueOf(v)
+	dest.Elem().Set(reflect.Zero(structType))
+	values, err := cursor.builder.makeStructValues(dest, fieldMap, cursor.columns)
+	if err != nil {
+		return err
+	}
+	return cursor.rows.Scan(values...)
+}
+
+// Err Get the error encountered during the iteration
+func (cursor *Cursor) Err() error {
+	return cursor.rows.Err()
+}
+
+// Close Close the cursor and release the connection
+func (cursor *Cursor) Close() error {
+	return cursor.rows.Close()
+}
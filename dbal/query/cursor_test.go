@@ -0,0 +1,128 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestCursorCursor(t *testing.T) {
+	NewTableForCursorTest()
+	qb := getTestBuilder()
+	cursor := qb.Table("table_test_cursor").OrderBy("id").MustCursor()
+	defer cursor.Close()
+
+	names := []string{}
+	for cursor.Next() {
+		row, err := cursor.Row()
+		assert.Nil(t, err)
+		names = append(names, row.Get("name").(string))
+	}
+	assert.Nil(t, cursor.Err())
+	assert.Equal(t, []string{"John", "Lee", "Ken", "Ben"}, names, "the rows should be iterated in order")
+}
+
+func TestCursorEach(t *testing.T) {
+	NewTableForCursorTest()
+	qb := getTestBuilder()
+	votes := []int64{}
+	qb.Table("table_test_cursor").
+		Where("vote", "<", 100).
+		OrderByDesc("vote").
+		MustEach(func(row xun.R) error {
+			votes = append(votes, row.Get("vote").(int64))
+			return nil
+		})
+	assert.Equal(t, []int64{10, 6, 5}, votes, "the votes should be []int64{10,6,5}")
+
+	count := 0
+	err := qb.Table("table_test_cursor").OrderBy("id").Each(func(row xun.R) error {
+		count++
+		if count == 2 {
+			return fmt.Errorf("stop")
+		}
+		return nil
+	})
+	assert.Equal(t, "stop", err.Error(), "the error of the callback should be returned")
+	assert.Equal(t, 2, count, "the iteration should be stopped")
+}
+
+func TestCursorEachBind(t *testing.T) {
+	NewTableForCursorTest()
+	qb := getTestBuilder()
+
+	type Item struct {
+		ID    int64
+		Name  string
+		Email string
+		Vote  int
+	}
+
+	item := Item{}
+	items := []Item{}
+	qb.Table("table_test_cursor").
+		Select("id", "name", "email", "vote").
+		OrderBy("id").
+		MustEachBind(&item, func() error {
+			items = append(items, item)
+			return nil
+		})
+	assert.Equal(t, 4, len(items), "the items count should be 4")
+	if len(items) == 4 {
+		assert.Equal(t, Item{ID: 1, Name: "John", Email: "john@yao.run", Vote: 10}, items[0])
+		assert.Equal(t, Item{ID: 4, Name: "Ben", Email: "ben@yao.run", Vote: 6}, items[3])
+	}
+
+	err := qb.Table("table_test_cursor").EachBind(&[]Item{}, func() error { return nil })
+	assert.NotNil(t, err, "the binding var should be a struct pointer")
+}
+
+func TestCursorStream(t *testing.T) {
+	NewTableForCursorTest()
+	qb := getTestBuilder()
+	rows, errs := qb.Table("table_test_cursor").OrderBy("id").Stream(2)
+	names := []string{}
+	for row := range rows {
+		names = append(names, row.Get("name").(string))
+	}
+	assert.Nil(t, <-errs)
+	assert.Equal(t, []string{"John", "Lee", "Ken", "Ben"}, names, "the rows should be sent in order")
+
+	// stop the producer by canceling the context
+	ctx, cancel := context.WithCancel(context.Background())
+	rows, errs = qb.Table("table_test_cursor").OrderBy("id").WithContext(ctx).Stream(0)
+	<-rows
+	cancel()
+	assert.NotNil(t, <-errs, "the context error should be returned")
+}
+
+// clean the test data
+func TestCursorClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_cursor")
+}
+
+func NewTableForCursorTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_cursor")
+	builder.MustCreateTable("table_test_cursor", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.String("name").Index()
+		table.Integer("vote")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_cursor").Insert([]xun.R{
+		{"email": "john@yao.run", "name": "John", "vote": 10},
+		{"email": "lee@yao.run", "name": "Lee", "vote": 5},
+		{"email": "ken@yao.run", "name": "Ken", "vote": 125},
+		{"email": "ben@yao.run", "name": "Ben", "vote": 6},
+	})
+}
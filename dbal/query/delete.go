@@ -5,12 +5,21 @@ import (
 	"github.com/yaoapp/xun/utils"
 )
 
-// Delete Delete records from the database.
+// Delete Delete records from the database, the records are trashed instead if the soft deletes is enabled.
 func (builder *Builder) Delete() (int64, error) {
-	sql, bindings := builder.Grammar.CompileDelete(builder.Query)
+	if builder.Query.SoftDeletes != "" {
+		return builder.softDelete()
+	}
+	return builder.forceDelete()
+}
+
+// forceDelete Delete records from the database permanently.
+func (builder *Builder) forceDelete() (int64, error) {
+	sql, bindings := builder.Grammar.CompileDelete(builder.withScopes().Query)
 	defer log.With(log.F{"bindings": bindings}).Debug(sql)
 
-	res, err := builder.UseWrite().DB().Exec(sql, bindings...)
+	builder.UseWrite()
+	res, err := builder.executor().ExecContext(builder.Context(), sql, bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -30,7 +39,8 @@ func (builder *Builder) Truncate() error {
 	sqls, bindings := builder.Grammar.CompileTruncate(builder.Query)
 	for i, sql := range sqls {
 		defer log.With(log.F{"bindings": bindings}).Debug(sql)
-		_, err := builder.UseWrite().DB().Exec(sql, bindings[i]...)
+		builder.UseWrite()
+		_, err := builder.executor().ExecContext(builder.Context(), sql, bindings[i]...)
 		if err != nil {
 			return err
 		}
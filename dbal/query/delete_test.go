@@ -45,6 +45,33 @@ func TestDeleteMustDeleteWithJoin(t *testing.T) {
 	assert.Equal(t, int64(2), affected, "The affected rows should be 2")
 }
 
+func TestDeleteMustDeleteWithJoinTable(t *testing.T) {
+	NewTableForDeleteJoinTest()
+	qb := getTestBuilder()
+	affected := qb.From("table_test_delete").
+		Join("table_test_delete_users", "table_test_delete_users.email", "=", "table_test_delete.email").
+		Where("table_test_delete_users.banned", true).
+		MustDelete()
+	assert.Equal(t, int64(2), affected, "The affected rows should be 2")
+
+	names := []interface{}{}
+	for _, row := range qb.Table("table_test_delete").OrderBy("name").MustGet() {
+		names = append(names, row["name"])
+	}
+	assert.Equal(t, []interface{}{"Ben", "John"}, names, "the rows of the banned users should be deleted")
+}
+
+func TestDeleteMustDeleteWithLeftJoin(t *testing.T) {
+	NewTableForDeleteJoinTest()
+	qb := getTestBuilder()
+	affected := qb.From("table_test_delete as d").
+		LeftJoin("table_test_delete_users as u", "u.email", "=", "d.email").
+		WhereNull("u.id").
+		MustDelete()
+	assert.Equal(t, int64(1), affected, "The affected rows should be 1")
+	assert.Equal(t, int64(3), qb.Table("table_test_delete").MustCount(), "the rows count should be 3, after delete")
+}
+
 func TestDeleteMustTruncate(t *testing.T) {
 	NewTableForDeleteTest()
 	qb := getTestBuilder()
@@ -70,6 +97,7 @@ func TestDeleteMustTruncateError(t *testing.T) {
 func TestDeleteClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
 	builder.DropTableIfExists("table_test_delete")
+	builder.DropTableIfExists("table_test_delete_users")
 }
 
 func NewTableForDeleteTest() {
@@ -96,3 +124,22 @@ func NewTableForDeleteTest() {
 		{"email": "ben@yao.run", "name": "Ben", "vote": 6, "score": 48.12, "score_grade": 99.27, "status": "DONE", "created_at": "2021-03-25 18:15:29"},
 	})
 }
+
+func NewTableForDeleteJoinTest() {
+	NewTableForDeleteTest()
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_delete_users")
+	builder.MustCreateTable("table_test_delete_users", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.Boolean("banned").SetDefault(false)
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_delete_users").Insert([]xun.R{
+		{"email": "john@yao.run", "banned": false},
+		{"email": "lee@yao.run", "banned": true},
+		{"email": "ken@yao.run", "banned": true},
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
 )
 
 // From set the table which the query is targeting.
@@ -15,6 +16,12 @@ func (builder *Builder) From(from string) Query {
 		Name:   name,
 		Offset: 0,
 	}
+	if utils.StringHave(builder.Conn.Option.SoftDeletes, name.Name) {
+		builder.Query.SoftDeletes = "deleted_at"
+	}
+	if timestamps := builder.timestampsOf(name.Name); timestamps != "" {
+		builder.Query.Timestamps = timestamps
+	}
 	return builder
 }
 
@@ -3,23 +3,35 @@ package query
 import (
 	"fmt"
 
+	"github.com/blang/semver/v4"
 	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/utils"
 )
 
+// the maximum number of the parameters in one statement of the drivers.
+// SQLite before 3.32.0 only allows 999 parameters.
+var parameterLimits = map[string]int{
+	"mysql":    65535,
+	"postgres": 65535,
+	"sqlite3":  32766,
+}
+
 // Insert Insert new records into the database.
+// If the rows have more parameters than the driver allows in one statement, they are inserted in batches within a transaction.
 func (builder *Builder) Insert(v interface{}, columns ...interface{}) error {
 	columns, values := builder.prepareInsertValues(v, columns...)
-	sql, bindings := builder.Grammar.CompileInsert(builder.Query, columns, values)
-	defer log.With(log.F{"bindings": bindings}).Debug(sql)
-
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
+	limit, err := builder.parameterLimit()
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec(bindings...)
+	if len(values)*len(columns) > limit {
+		_, err = builder.insertBatch(columns, values, 0)
+		return err
+	}
+
+	_, err = builder.insertValues(columns, values)
 	return err
 }
 
@@ -29,19 +41,52 @@ func (builder *Builder) MustInsert(v interface{}, columns ...interface{}) {
 	utils.PanicIF(err)
 }
 
+// InsertBatch Insert new records into the database in batches of batchSize rows, and get the number of the inserted rows.
+// The batches are inserted within a transaction, and are split by the parameter limit of the driver, e.g.
+//    inserted, err := qb.Table("users").InsertBatch(rows, 500)
+// If the batchSize is 0, the batches are as large as the driver allows.
+// MySQL also limits the size of the statement by the max_allowed_packet variable, set a smaller batchSize for the large rows.
+func (builder *Builder) InsertBatch(v interface{}, batchSize int, columns ...interface{}) (int64, error) {
+	columns, values := builder.prepareInsertValues(v, columns...)
+	return builder.insertBatch(columns, values, batchSize)
+}
+
+// MustInsertBatch Insert new records into the database in batches of batchSize rows, and get the number of the inserted rows.
+func (builder *Builder) MustInsertBatch(v interface{}, batchSize int, columns ...interface{}) int64 {
+	inserted, err := builder.InsertBatch(v, batchSize, columns...)
+	utils.PanicIF(err)
+	return inserted
+}
+
+// CopyFrom Import a large number of rows into the table, and get the number of the imported rows, e.g.
+//    copied, err := qb.Table("events").CopyFrom([]string{"name", "created_at"}, dbal.CopyFromRows(rows))
+// Postgres uses the COPY ... FROM STDIN statement, MySQL uses the LOAD DATA LOCAL INFILE statement (the local_infile variable of the server should be ON),
+// and the other drivers use the batched prepared insert statements. The rows are imported within a transaction.
+func (builder *Builder) CopyFrom(columns []string, rows dbal.CopyFromSource) (int64, error) {
+	return builder.Grammar.ProcessCopyFrom(builder.Query, columns, rows)
+}
+
+// MustCopyFrom Import a large number of rows into the table, and get the number of the imported rows.
+func (builder *Builder) MustCopyFrom(columns []string, rows dbal.CopyFromSource) int64 {
+	copied, err := builder.CopyFrom(columns, rows)
+	utils.PanicIF(err)
+	return copied
+}
+
 // InsertOrIgnore Insert new records into the database while ignoring errors.
 func (builder *Builder) InsertOrIgnore(v interface{}, columns ...interface{}) (int64, error) {
 	columns, values := builder.prepareInsertValues(v, columns...)
 	sql, bindings := builder.Grammar.CompileInsertOrIgnore(builder.Query, columns, values)
 	defer log.With(log.F{"bindings": bindings}).Debug(sql)
 
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
+	builder.UseWrite()
+	stmt, err := builder.executor().PrepareContext(builder.Context(), sql)
 	if err != nil {
 		return 0, err
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(bindings...)
+	res, err := stmt.ExecContext(builder.Context(), bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -87,13 +132,14 @@ func (builder *Builder) InsertUsing(qb interface{}, columns ...interface{}) (int
 	sql := builder.parseSub(sub)
 	sql = builder.Grammar.CompileInsertUsing(builder.Query, columns, sql)
 
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
+	builder.UseWrite()
+	stmt, err := builder.executor().PrepareContext(builder.Context(), sql)
 	if err != nil {
 		return 0, err
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(bindings...)
+	res, err := stmt.ExecContext(builder.Context(), bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -107,3 +153,80 @@ func (builder *Builder) MustInsertUsing(qb interface{}, columns ...interface{})
 	utils.PanicIF(err)
 	return affected
 }
+
+// insertBatch Insert the values in batches within a transaction
+func (builder *Builder) insertBatch(columns []interface{}, values [][]interface{}, batchSize int) (int64, error) {
+	limit, err := builder.parameterLimit()
+	if err != nil {
+		return 0, err
+	}
+
+	size := batchSize
+	if len(columns) > 0 && (size <= 0 || size*len(columns) > limit) {
+		size = limit / len(columns)
+	}
+
+	if size <= 0 || len(values) <= size {
+		return builder.insertValues(columns, values)
+	}
+
+	var inserted int64 = 0
+	err = builder.Transaction(func(tx Query) error {
+		qb := tx.(*Builder)
+		qb.Query.From = builder.Query.From
+		for start := 0; start < len(values); start += size {
+			end := start + size
+			if end > len(values) {
+				end = len(values)
+			}
+			affected, err := qb.insertValues(columns, values[start:end])
+			if err != nil {
+				return err
+			}
+			inserted += affected
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
+// insertValues Insert the values in one statement
+func (builder *Builder) insertValues(columns []interface{}, values [][]interface{}) (int64, error) {
+	sql, bindings := builder.Grammar.CompileInsert(builder.Query, columns, values)
+	defer log.With(log.F{"bindings": bindings}).Debug(sql)
+
+	builder.UseWrite()
+	stmt, err := builder.executor().PrepareContext(builder.Context(), sql)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(builder.Context(), bindings...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// parameterLimit Get the maximum number of the parameters in one statement
+func (builder *Builder) parameterLimit() (int, error) {
+	version, err := builder.GetVersion()
+	if err != nil {
+		return 0, err
+	}
+
+	if version.Driver == "sqlite3" && version.LT(semver.MustParse("3.32.0")) {
+		return 999, nil
+	}
+
+	limit, has := parameterLimits[version.Driver]
+	if !has {
+		return 999, nil
+	}
+	return limit, nil
+}
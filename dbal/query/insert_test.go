@@ -1,7 +1,9 @@
 package query
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/yaoapp/xun"
@@ -313,10 +315,105 @@ func TestInsertMustInsertUsingWithArray(t *testing.T) {
 
 }
 
+func TestInsertMustInsertBatch(t *testing.T) {
+	NewTableForInsertTest()
+	qb := getTestBuilder()
+	rows := []xun.R{}
+	for i := 0; i < 25; i++ {
+		rows = append(rows, xun.R{"email": fmt.Sprintf("user%d@example.com", i), "vote": i})
+	}
+
+	inserted := qb.Table("table_test_insert").MustInsertBatch(rows, 10)
+	assert.Equal(t, int64(25), inserted, "The inserted rows should be 25")
+	assert.Equal(t, int64(25), qb.Table("table_test_insert").MustCount(), "The rows count should be 25")
+	assert.Equal(t, int64(300), qb.Table("table_test_insert").MustSum("vote").MustInt64(), "The sum of the votes should be 300")
+}
+
+func TestInsertMustInsertBatchRollback(t *testing.T) {
+	NewTableForInsertTest()
+	qb := getTestBuilder()
+	rows := []xun.R{}
+	for i := 0; i < 25; i++ {
+		rows = append(rows, xun.R{"email": fmt.Sprintf("user%d@example.com", i), "vote": i})
+	}
+	rows[22]["email"] = "user0@example.com"
+
+	_, err := qb.Table("table_test_insert").InsertBatch(rows, 10)
+	assert.NotNil(t, err, "The return error should not be nil")
+	assert.Equal(t, int64(0), qb.Table("table_test_insert").MustCount(), "The batches should be rolled back")
+}
+
+func TestInsertMustInsertSplitByParameterLimit(t *testing.T) {
+	NewTableForInsertTest()
+	qb := getTestBuilder()
+	rows := [][]interface{}{}
+	for i := 0; i < 40000; i++ {
+		rows = append(rows, []interface{}{fmt.Sprintf("user%d@example.com", i), i % 10})
+	}
+
+	// 80000 parameters exceed the limits of all of the drivers
+	qb.Table("table_test_insert").MustInsert(rows, "email", "vote")
+	assert.Equal(t, int64(40000), qb.Table("table_test_insert").MustCount(), "The rows count should be 40000")
+
+	inserted := qb.Table("table_test_insert").MustInsertBatch([][]interface{}{{"more@example.com", 1}}, 0, "email", "vote")
+	assert.Equal(t, int64(1), inserted, "The inserted rows should be 1")
+}
+
+func TestInsertMustCopyFrom(t *testing.T) {
+	NewTableForInsertTest()
+	qb := getTestBuilder()
+	rows := [][]interface{}{}
+	for i := 0; i < 5000; i++ {
+		rows = append(rows, []interface{}{fmt.Sprintf("user%d@example.com", i), i % 10})
+	}
+
+	copied := qb.Table("table_test_insert").MustCopyFrom([]string{"email", "vote"}, dbal.CopyFromRows(rows))
+	assert.Equal(t, int64(5000), copied, "The copied rows should be 5000")
+	assert.Equal(t, int64(5000), qb.Table("table_test_insert").MustCount(), "The rows count should be 5000")
+	assert.Equal(t, int64(22500), qb.Table("table_test_insert").MustSum("vote").MustInt64(), "The sum of the votes should be 22500")
+}
+
+func TestInsertCopyFromError(t *testing.T) {
+	NewTableForInsertTest()
+	qb := getTestBuilder()
+	_, err := qb.Table("table_test_insert").CopyFrom([]string{"email", "vote"}, dbal.CopyFromRows([][]interface{}{
+		{"picard@example.com", 1},
+		{"janeway@example.com"},
+	}))
+	assert.Equal(t, "the row has 1 values, but there are 2 columns", err.Error(), "The error should be returned")
+	assert.Equal(t, int64(0), qb.Table("table_test_insert").MustCount(), "The copied rows should be rolled back")
+
+	_, err = qb.Table("table_test_insert").CopyFrom([]string{"email", "vote"}, &testCopyFromSource{})
+	assert.Equal(t, "something wrong", err.Error(), "The error of the source should be returned")
+	assert.Equal(t, int64(0), qb.Table("table_test_insert").MustCount(), "The copied rows should be rolled back")
+}
+
+func TestInsertCopyFromTime(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_insert_time")
+	builder.MustCreateTable("table_test_insert_time", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("name", 20)
+		table.DateTime("created_at")
+	})
+
+	qb := getTestBuilder()
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.FixedZone("UTC+8", 8*3600))
+	qb.Table("table_test_insert_time").MustInsert(xun.R{"name": "insert", "created_at": createdAt})
+	qb.Table("table_test_insert_time").MustCopyFrom([]string{"name", "created_at"}, dbal.CopyFromRows([][]interface{}{{"copy", createdAt}}))
+
+	rows := qb.Table("table_test_insert_time").OrderBy("id").MustGet()
+	assert.Equal(t, 2, len(rows), "The return rows should be 2")
+	if len(rows) == 2 {
+		assert.Equal(t, rows[0]["created_at"], rows[1]["created_at"], "The copied time should be the same as the inserted one")
+	}
+}
+
 // clean the test data
 func TestInsertClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
 	builder.DropTableIfExists("table_test_insert")
+	builder.DropTableIfExists("table_test_insert_time")
 }
 
 func NewTableForInsertTest() {
@@ -340,3 +437,19 @@ func checkInsertWithColumns(t *testing.T, qb Query) {
 		assert.Equal(t, int64(2), users[1]["vote"].(int64), "The vote of the second row should be 2")
 	}
 }
+
+// testCopyFromSource the rows source fails after the 3rd row
+type testCopyFromSource struct{ index int }
+
+func (source *testCopyFromSource) Next() bool {
+	source.index++
+	return source.index <= 3
+}
+
+func (source *testCopyFromSource) Values() ([]interface{}, error) {
+	return []interface{}{fmt.Sprintf("user%d@example.com", source.index), source.index}, nil
+}
+
+func (source *testCopyFromSource) Err() error {
+	return fmt.Errorf("something wrong")
+}
@@ -1,8 +1,11 @@
 package query
 
 import (
+	"context"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
 )
 
 // Query The database Query interface
@@ -34,8 +37,23 @@ type Query interface {
 	// defined in the paginate.go file
 	Paginate(perpage int, page int, v ...interface{}) (xun.P, error)
 	MustPaginate(perpage int, page int, v ...interface{}) xun.P
+	CursorPaginate(perpage int, cursor string, v ...interface{}) (xun.C, error)
+	MustCursorPaginate(perpage int, cursor string, v ...interface{}) xun.C
 	Chunk(size int, callback func(items []interface{}, page int) error, v ...interface{}) error
 	MustChunk(size int, callback func(items []interface{}, page int) error, v ...interface{})
+	ChunkByID(size int, callback func(items []interface{}, page int) error, column string, alias string, v ...interface{}) error
+	MustChunkByID(size int, callback func(items []interface{}, page int) error, column string, alias string, v ...interface{})
+	LazyByID(size int, callback func(item interface{}) error, column string, alias string, v ...interface{}) error
+	MustLazyByID(size int, callback func(item interface{}) error, column string, alias string, v ...interface{})
+
+	// defined in the cursor.go file
+	Cursor() (*Cursor, error)
+	MustCursor() *Cursor
+	Each(callback func(row xun.R) error) error
+	MustEach(callback func(row xun.R) error)
+	EachBind(v interface{}, callback func() error) error
+	MustEachBind(v interface{}, callback func() error)
+	Stream(buffer int) (<-chan xun.R, <-chan error)
 
 	// defined in the connection.go file
 	DB(usewrite ...bool) *sqlx.DB
@@ -43,6 +61,22 @@ type Query interface {
 	UseRead() Query
 	UseWrite() Query
 	IsWrite() bool
+	WithContext(ctx context.Context) Query
+	Context() context.Context
+	GetVersion() (*dbal.Version, error)
+	MustGetVersion() *dbal.Version
+
+	// defined in the transaction.go file
+	Begin() (Query, error)
+	MustBegin() Query
+	WithTx(tx *sqlx.Tx) Query
+	Commit() error
+	MustCommit()
+	Rollback() error
+	MustRollback()
+	Transaction(callback func(tx Query) error) error
+	MustTransaction(callback func(tx Query) error)
+	InTransaction() bool
 
 	// defined in the aggregate.go file
 	Count(columns ...interface{}) (int64, error)
@@ -62,8 +96,19 @@ type Query interface {
 	SelectAppend(columns ...interface{}) Query
 	SelectRaw(expression string, bindings ...interface{}) Query
 	SelectSub(qb interface{}, alias string) Query
+	SelectWindow(function string, args []interface{}, over interface{}, alias string) Query
+	SelectRowNumber(over interface{}, alias string) Query
+	SelectRank(over interface{}, alias string) Query
+	SelectDenseRank(over interface{}, alias string) Query
+	SelectLag(column interface{}, offset int, over interface{}, alias string) Query
+	SelectLead(column interface{}, offset int, over interface{}, alias string) Query
+	Window(name string, window dbal.Window) Query
 	Distinct(args ...interface{}) Query
 
+	// defined in the with.go file
+	With(name string, qb interface{}) Query
+	WithRecursive(name string, columns []string, qb interface{}) Query
+
 	// defined in the from.go file
 	From(name string) Query
 	FromRaw(sql string, bindings ...interface{}) Query
@@ -119,9 +164,28 @@ type Query interface {
 	OrWhereMonth(column interface{}, args ...interface{}) Query
 	WhereDay(column interface{}, args ...interface{}) Query
 	OrWhereDay(column interface{}, args ...interface{}) Query
+	WhereJSONContains(column interface{}, value interface{}) Query
+	OrWhereJSONContains(column interface{}, value interface{}) Query
+	WhereJSONDoesntContain(column interface{}, value interface{}) Query
+	OrWhereJSONDoesntContain(column interface{}, value interface{}) Query
+	WhereJSONLength(column interface{}, args ...interface{}) Query
+	OrWhereJSONLength(column interface{}, args ...interface{}) Query
+	WhereJSONContainsKey(column interface{}) Query
+	OrWhereJSONContainsKey(column interface{}) Query
+	WhereJSONDoesntContainKey(column interface{}) Query
+	OrWhereJSONDoesntContainKey(column interface{}) Query
 	When(value bool, callback func(qb Query, value bool), defaults ...func(qb Query, value bool)) Query
 	Unless(value bool, callback func(qb Query, value bool), defaults ...func(qb Query, value bool)) Query
 
+	// defined in the scope.go file
+	AddScope(table string, name string, scope Scope)
+	AddGlobalScope(table string, name string, scope Scope)
+	RemoveScope(table string, name string)
+	Scope(name string, args ...interface{}) (Query, error)
+	MustScope(name string, args ...interface{}) Query
+	WithoutGlobalScope(name string) Query
+	WithoutGlobalScopes(names ...string) Query
+
 	// defined in the group.go file
 	GroupBy(groups ...interface{}) Query
 	GroupByRaw(expression string, bindings ...interface{}) Query
@@ -150,6 +214,10 @@ type Query interface {
 	// defined in the insert.go file
 	Insert(v interface{}, columns ...interface{}) error
 	MustInsert(v interface{}, columns ...interface{})
+	InsertBatch(v interface{}, batchSize int, columns ...interface{}) (int64, error)
+	MustInsertBatch(v interface{}, batchSize int, columns ...interface{}) int64
+	CopyFrom(columns []string, rows dbal.CopyFromSource) (int64, error)
+	MustCopyFrom(columns []string, rows dbal.CopyFromSource) int64
 	InsertOrIgnore(v interface{}, columns ...interface{}) (int64, error)
 	MustInsertOrIgnore(v interface{}, columns ...interface{}) int64
 	InsertGetID(v interface{}, args ...interface{}) (int64, error)
@@ -160,6 +228,9 @@ type Query interface {
 	// defined in the update.go file
 	Upsert(values interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) (int64, error)
 	MustUpsert(values interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) int64
+	UpsertConstraint(name string) Query
+	UpsertWhere(column interface{}, args ...interface{}) Query
+	Excluded(column string) dbal.Expression
 	UpdateOrInsert(attributes interface{}, values ...interface{}) (bool, error)
 	MustUpdateOrInsert(attributes interface{}, values ...interface{}) bool
 	Update(v interface{}) (int64, error)
@@ -175,6 +246,31 @@ type Query interface {
 	Truncate() error
 	MustTruncate()
 
+	// defined in the softdelete.go file
+	SoftDeletes(column ...string) Query
+	WithTrashed() Query
+	OnlyTrashed() Query
+	Restore() (int64, error)
+	MustRestore() int64
+	ForceDelete() (int64, error)
+	MustForceDelete() int64
+
+	// defined in the timestamps.go file
+	Timestamps() Query
+	TimestampsTz() Query
+	WithoutTimestamps() Query
+
+	// defined in the returning.go file
+	Returning(columns ...interface{}) Query
+	InsertReturning(v interface{}, columns ...interface{}) ([]xun.R, error)
+	MustInsertReturning(v interface{}, columns ...interface{}) []xun.R
+	UpdateReturning(v interface{}) ([]xun.R, error)
+	MustUpdateReturning(v interface{}) []xun.R
+	UpsertReturning(v interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) ([]xun.R, error)
+	MustUpsertReturning(v interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) []xun.R
+	DeleteReturning() ([]xun.R, error)
+	MustDeleteReturning() []xun.R
+
 	// defined in the debug.go file
 	DD()
 	Dump()
@@ -256,10 +352,12 @@ type Query interface {
 // 		})
 // JSON Where Clauses:
 //		table("users").where(`preferences->dining->meal`, `salad`)
+//		table("users").where(`preferences->dining->>meal`, `salad`) // use ->> to compare the text value on PostgreSQL
 // 		table("users").whereJsonContains(`options->languages`, `en`)
 // 		table("users").whereJsonContains(`options->languages`, [`en`, `de`])
 // 		table("users").whereJsonLength(`options->languages`, 0)
 // 		table("users").whereJsonLength(`options->languages`, `>`, 1)
+// 		table("users").whereJsonContainsKey(`options->languages[0]`)
 // Additional Where Clauses:
 // 		whereBetween / orWhereBetween
 // 		whereNotBetween / orWhereNotBetween
@@ -333,6 +431,12 @@ type Query interface {
 //		return qb.orderBy("name")
 // })
 
+// Scopes
+// qb.addScope(`users`, `active`, func(qb, args...){ qb.where(`status`, `active`) })
+// table(`users`).mustScope(`active`).get()
+// qb.addGlobalScope(`posts`, `published`, func(qb, args...){ qb.whereNotNull(`published_at`) })
+// table(`posts`).withoutGlobalScope(`published`).get()
+
 // Insert Statements
 // table(`users`).insert([ `email` : `kayla@example.com`,`votes` : 0])
 // table(`users`).insert(
@@ -366,10 +470,15 @@ type Query interface {
 // 		[`email` : `john@example.com`, `name` : `John`],
 // 		[`votes` : `2`],
 // )
+// table(`users`).timestamps().insert([`name` : `John`]) // insert into users (name, created_at, updated_at) values (?, now(), now())
+// table(`users`).timestamps().where("id", 1).update([`name` : `Lee`]) // update users set name = ?, updated_at = now() where id = 1
 
 // Delete Statements
 // table(`users`).where("id", 1).delete()
 // table(`users`).delete()
+// table(`users`).softDeletes().where("id", 1).delete() // update users set deleted_at = now() where id = 1 and deleted_at is null
+// table(`users`).softDeletes().onlyTrashed().restore()
+// table(`users`).softDeletes().withTrashed().forceDelete()
 // table(`users`).truncate() // When truncating a PostgreSQL database, the CASCADE behavior will be applied. This means that all foreign key related records in other tables will be deleted as well.
 
 // Pessimistic Locking
@@ -1,8 +1,12 @@
 package query
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
@@ -80,11 +84,114 @@ func (builder *Builder) MustChunk(size int, callback func(items []interface{}, p
 	utils.PanicIF(err)
 }
 
-// ChunkByID chunk the results of a query by comparing IDs.
-func (builder *Builder) ChunkByID() {}
+// ChunkByID chunk the results of a query by comparing IDs, the rows could be updated in the callback safely.
+// The column is "id" by default, the alias is the key of the column in the results, it is the column name by default.
+func (builder *Builder) ChunkByID(size int, callback func(items []interface{}, page int) error, column string, alias string, v ...interface{}) error {
+
+	if column == "" {
+		column = "id"
+	}
+
+	if alias == "" {
+		alias = column
+		if pos := strings.LastIndex(column, "."); pos >= 0 {
+			alias = column[pos+1:]
+		}
+	}
+
+	if size < 1 {
+		size = 50
+	}
+
+	var lastID interface{} = nil
+	page := 1
+	for {
+
+		var results []interface{} = nil
+		var countResults int
+
+		// We'll execute the query for the given page and get the results. The query is
+		// constrained by the last ID of the previous chunk instead of the offset, so
+		// the rows updated in the callback are neither skipped nor duplicated.
+		if len(v) > 0 {
+
+			reflectValuesPtr := reflect.ValueOf(v[0])
+			reflectValues := reflect.Indirect(reflectValuesPtr)
+			if reflectValues.Kind() != reflect.Slice {
+				return fmt.Errorf("The given binding var shoule be a slice pointer")
+			}
+
+			reflectValuesType := reflectValues.Type()
+			reflectValuesPtr.Elem().Set(reflect.New(reflectValuesType).Elem())
+
+			_, err := builder.forPageAfterID(size, lastID, column).Get(v...)
+			if err != nil {
+				return err
+			}
+
+			countResults = reflectValues.Len()
+			for i := 0; i < countResults; i++ {
+				results = append(results, reflectValues.Index(i).Interface())
+			}
+		} else {
+			rows, err := builder.forPageAfterID(size, lastID, column).Get()
+			if err != nil {
+				return err
+			}
+
+			countResults = len(rows)
+			for _, row := range rows {
+				results = append(results, row)
+			}
+		}
+
+		if countResults == 0 {
+			break
+		}
+
+		if err := callback(results, page); err != nil {
+			return err
+		}
+
+		values, err := builder.getCursorValues(results[countResults-1], []string{alias})
+		if err != nil || values[0] == nil {
+			return fmt.Errorf("The ChunkByID operation was aborted because the %s column is not present in the query result", alias)
+		}
+		lastID = values[0]
+
+		if countResults != size {
+			break
+		}
+
+		page++
+	}
+
+	return nil
+}
 
 // MustChunkByID chunk the results of a query by comparing IDs.
-func (builder *Builder) MustChunkByID() {}
+func (builder *Builder) MustChunkByID(size int, callback func(items []interface{}, page int) error, column string, alias string, v ...interface{}) {
+	err := builder.ChunkByID(size, callback, column, alias, v...)
+	utils.PanicIF(err)
+}
+
+// LazyByID iterate the results of a query one by one, the results are queried in chunks by comparing IDs.
+func (builder *Builder) LazyByID(size int, callback func(item interface{}) error, column string, alias string, v ...interface{}) error {
+	return builder.ChunkByID(size, func(items []interface{}, page int) error {
+		for _, item := range items {
+			if err := callback(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, column, alias, v...)
+}
+
+// MustLazyByID iterate the results of a query one by one, the results are queried in chunks by comparing IDs.
+func (builder *Builder) MustLazyByID(size int, callback func(item interface{}) error, column string, alias string, v ...interface{}) {
+	err := builder.LazyByID(size, callback, column, alias, v...)
+	utils.PanicIF(err)
+}
 
 // Paginate paginate the given query into a simple paginator.
 func (builder *Builder) Paginate(pageSize int, page int, v ...interface{}) (xun.P, error) {
@@ -132,28 +239,279 @@ func (builder *Builder) MustPaginate(pageSize int, page int, v ...interface{}) x
 	return res
 }
 
+// CursorPaginate paginate the given query using the values of the ordering columns instead of the offset, e.g.
+//    page := qb.Table("audits").OrderBy("created_at", "desc").OrderBy("id").MustCursorPaginate(15, "")
+//    next := qb.MustCursorPaginate(15, page.NextCursor)
+// The ordering columns should be selected and not null, the last one should be unique to keep the order stable.
+func (builder *Builder) CursorPaginate(pageSize int, cursor string, v ...interface{}) (xun.C, error) {
+	builder.enforceOrderBy()
+	if pageSize < 1 {
+		pageSize = 15
+	}
+
+	paginator := xun.C{Items: []interface{}{}, PageSize: pageSize}
+	columns, keys, err := builder.getCursorColumns()
+	if err != nil {
+		return paginator, err
+	}
+
+	current, err := decodeCursor(cursor, len(columns))
+	if err != nil {
+		return paginator, err
+	}
+
+	// Fetch one more row to determine if there are more results in the direction
+	rows, err := builder.forPageAfterCursor(pageSize+1, columns, current).Get(v...)
+	if err != nil {
+		return paginator, err
+	}
+
+	items := []interface{}{}
+	var reflectRows reflect.Value
+	if rows != nil {
+		for _, row := range rows {
+			items = append(items, row)
+		}
+	} else if len(v) > 0 && reflect.TypeOf(v[0]).Kind() == reflect.Ptr {
+		reflectRows = reflect.Indirect(reflect.ValueOf(v[0]))
+		if reflectRows.Kind() != reflect.Slice {
+			return paginator, fmt.Errorf("The given binding var shoule be a slice pointer")
+		}
+		for i := 0; i < reflectRows.Len(); i++ {
+			items = append(items, reflectRows.Index(i).Interface())
+		}
+	}
+
+	more := len(items) > pageSize
+	if more {
+		items = items[:pageSize]
+	}
+
+	next := current == nil || current.Next
+	if !next {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	if reflectRows.IsValid() {
+		bound := reflect.MakeSlice(reflectRows.Type(), 0, len(items))
+		for _, item := range items {
+			bound = reflect.Append(bound, reflect.ValueOf(item))
+		}
+		reflectRows.Set(bound)
+	}
+
+	paginator.Items = items
+	if len(items) == 0 {
+		return paginator, nil
+	}
+
+	if (next && more) || !next {
+		values, err := builder.getCursorValues(items[len(items)-1], keys)
+		if err != nil {
+			return paginator, err
+		}
+		paginator.NextCursor, err = encodeCursor(true, values)
+		if err != nil {
+			return paginator, err
+		}
+	}
+
+	if (!next && more) || (next && current != nil) {
+		values, err := builder.getCursorValues(items[0], keys)
+		if err != nil {
+			return paginator, err
+		}
+		paginator.PreviousCursor, err = encodeCursor(false, values)
+		if err != nil {
+			return paginator, err
+		}
+	}
+
+	return paginator, nil
+}
+
+// MustCursorPaginate paginate the given query using the values of the ordering columns instead of the offset.
+func (builder *Builder) MustCursorPaginate(pageSize int, cursor string, v ...interface{}) xun.C {
+	res, err := builder.CursorPaginate(pageSize, cursor, v...)
+	utils.PanicIF(err)
+	return res
+}
+
 // Set the limit and offset for a given page.
 func (builder *Builder) forPage(page int, pageSize int) Query {
 	return builder.Offset((page - 1) * pageSize).Limit(pageSize)
 }
 
-// @todo: forPageBeforeID  Constrain the query to the previous "page" of results before a given ID.
-// func (builder *Builder) forPageBeforeID(pageSize int, lastID int, column string) Query {
-// 	builder.Query.Orders = builder.removeExistingOrdersFor(column)
-// 	if lastID != 0 {
-// 		builder.Where(column, "<", lastID)
-// 	}
-// 	return builder.OrderBy(column, "desc").Limit(pageSize)
-// }
-
-// @todo: forPageAfterID  Constrain the query to the next "page" of results after a given ID.
-// func (builder *Builder) forPageAfterID(pageSize int, lastID int, column string) Query {
-// 	builder.Query.Orders = builder.removeExistingOrdersFor(column)
-// 	if lastID != 0 {
-// 		builder.Where(column, ">", lastID)
-// 	}
-// 	return builder.OrderBy(column, "asc").Limit(pageSize)
-// }
+// forPageAfterID Constrain the query to the next "page" of results after a given ID.
+func (builder *Builder) forPageAfterID(pageSize int, lastID interface{}, column string) *Builder {
+	new := builder.clone()
+	new.Query.Orders = new.removeExistingOrdersFor(column)
+	if lastID != nil {
+		new.Where(column, ">", lastID)
+	}
+	new.OrderBy(column, "asc").Limit(pageSize)
+	return new
+}
+
+// forPageAfterCursor Constrain the query to the next "page" of results after the given cursor.
+// The previous "page" is selected by reversing the orders, the results should be reversed back.
+func (builder *Builder) forPageAfterCursor(pageSize int, columns []string, current *pageCursor) *Builder {
+	new := builder.clone()
+	new.Query.Offset = -1
+	new.Query.Limit = pageSize
+	if current == nil {
+		return new
+	}
+
+	if !current.Next {
+		for i := range new.Query.Orders {
+			new.Query.Orders[i].Direction = utils.GetIF(new.Query.Orders[i].Direction == "desc", "asc", "desc").(string)
+		}
+	}
+
+	// (a > ?) or (a = ? and b > ?) or (a = ? and b = ? and c > ?)
+	new.Where(func(qb Query) {
+		for i := range columns {
+			i := i
+			qb.OrWhere(func(qb Query) {
+				for j := 0; j < i; j++ {
+					qb.Where(columns[j], "=", current.Values[j])
+				}
+				operator := utils.GetIF(new.Query.Orders[i].Direction == "desc", "<", ">").(string)
+				qb.Where(columns[i], operator, current.Values[i])
+			})
+		}
+	})
+	return new
+}
+
+// getCursorColumns get the ordering columns and the keys of them in the results
+func (builder *Builder) getCursorColumns() ([]string, []string, error) {
+	if len(builder.Query.Unions) > 0 {
+		return nil, nil, fmt.Errorf("the cursor pagination does not support the union queries")
+	}
+
+	columns := []string{}
+	keys := []string{}
+	for _, order := range builder.Query.Orders {
+		column, ok := order.Column.(string)
+		if order.Type != "basic" || !ok {
+			return nil, nil, fmt.Errorf("the cursor pagination only supports ordering by the column names")
+		}
+
+		key := column
+		if segments := strings.Split(strings.ToLower(column), " as "); len(segments) == 2 {
+			column = strings.TrimSpace(column[:len(segments[0])])
+			key = strings.TrimSpace(key[len(key)-len(segments[1]):])
+		} else if pos := strings.LastIndex(column, "."); pos >= 0 {
+			key = column[pos+1:]
+		}
+		columns = append(columns, column)
+		keys = append(keys, key)
+	}
+	return columns, keys, nil
+}
+
+// getCursorValues get the values of the ordering columns from the given row or struct
+func (builder *Builder) getCursorValues(item interface{}, keys []string) ([]interface{}, error) {
+	values := []interface{}{}
+	if row, ok := item.(xun.R); ok {
+		for _, key := range keys {
+			if !row.Has(key) {
+				return nil, fmt.Errorf("the ordering column %s should be selected", key)
+			}
+			values = append(values, row.Get(key))
+		}
+		return values, nil
+	}
+
+	reflectValue := reflect.Indirect(reflect.ValueOf(item))
+	fieldMap, err := builder.getFieldMap(reflectValue.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		field, has := fieldMap[key]
+		if !has {
+			return nil, fmt.Errorf("the ordering column %s should be bound to a field", key)
+		}
+
+		value := reflectValue.FieldByIndex(field.Index).Interface()
+		switch v := value.(type) {
+		case xun.T:
+			value = v.Time
+		case xun.N:
+			value = v.Number
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// encodeCursor encode the cursor as an opaque string
+func encodeCursor(next bool, values []interface{}) (string, error) {
+	current := pageCursor{Next: next, Values: []interface{}{}}
+	for i, value := range values {
+		if t, ok := value.(time.Time); ok {
+			current.Times = append(current.Times, i)
+			value = t.Format(time.RFC3339Nano)
+		}
+		current.Values = append(current.Values, value)
+	}
+
+	data, err := json.Marshal(current)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor decode the opaque string as a cursor, returns nil if the string is empty
+func decodeCursor(data string, size int) (*pageCursor, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	invalid := fmt.Errorf("the cursor %s is invalid", data)
+	bytes, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return nil, invalid
+	}
+
+	current := &pageCursor{}
+	decoder := json.NewDecoder(strings.NewReader(string(bytes)))
+	decoder.UseNumber()
+	err = decoder.Decode(current)
+	if err != nil || len(current.Values) != size {
+		return nil, invalid
+	}
+
+	for i, value := range current.Values {
+		if number, ok := value.(json.Number); ok {
+			if v, err := number.Int64(); err == nil {
+				current.Values[i] = v
+			} else if v, err := number.Float64(); err == nil {
+				current.Values[i] = v
+			}
+		}
+	}
+
+	for _, i := range current.Times {
+		if i < 0 || i >= size {
+			return nil, invalid
+		}
+		t, err := time.Parse(time.RFC3339Nano, fmt.Sprintf("%v", current.Values[i]))
+		if err != nil {
+			return nil, invalid
+		}
+		current.Values[i] = t
+	}
+
+	return current, nil
+}
 
 // getCountForPagination  Get the count of the total records for the paginator.
 func (builder *Builder) getCountForPagination(columns []interface{}) (int, error) {
@@ -171,10 +529,11 @@ func (builder *Builder) getCountForPagination(columns []interface{}) (int, error
 			}
 		}
 
+		scoped := clone.withScopes()
 		_, err := builder.new().
-			mergeBindings(clone).
+			mergeBindings(scoped).
 			setAggregate("count", builder.withoutSelectAliases(columns)).
-			FromRaw(fmt.Sprintf("(%s) as %s", clone.ToSQL(), builder.Grammar.Wrap("aggregate_table"))).
+			FromRaw(fmt.Sprintf("(%s) as %s", scoped.ToSQL(), builder.Grammar.Wrap("aggregate_table"))).
 			Value("aggregate", &aggregate)
 
 		return aggregate, err
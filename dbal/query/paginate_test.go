@@ -293,6 +293,153 @@ func TestPaginateChunkWithBindError(t *testing.T) {
 	})
 }
 
+func TestPaginateChunkByID(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Where("vote", "<", 100).
+		Select("id", "name", "vote")
+
+	// updating the rows being chunked should not skip any rows
+	IDs := []int64{}
+	pages := []int{}
+	qb.MustChunkByID(2, func(items []interface{}, page int) error {
+		pages = append(pages, page)
+		for _, item := range items {
+			id := item.(xun.R).Get("id").(int64)
+			IDs = append(IDs, id)
+			qb.New().Table("table_test_paginate").Where("id", id).MustUpdate(xun.R{"vote": 200})
+		}
+		return nil
+	}, "id", "")
+	assert.Equal(t, []int64{1, 2, 4}, IDs, "The chunk ids should be []int64{1,2,4}")
+	assert.Equal(t, []int{1, 2}, pages, "The pages should be []int{1,2}")
+}
+
+func TestPaginateChunkByIDWithBind(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+
+	type Item struct {
+		ID   int64
+		Name string
+	}
+
+	IDs := []int64{}
+	qb.Table("table_test_paginate as t").
+		Select("t.id", "t.name").
+		OrderByDesc("t.id").
+		MustChunkByID(3, func(items []interface{}, page int) error {
+			for _, item := range items {
+				IDs = append(IDs, item.(Item).ID)
+			}
+			return nil
+		}, "t.id", "", &[]Item{})
+	assert.Equal(t, []int64{1, 2, 3, 4}, IDs, "The chunk ids should be []int64{1,2,3,4}")
+
+	err := qb.Table("table_test_paginate").Select("name").ChunkByID(2, func(items []interface{}, page int) error { return nil }, "id", "")
+	assert.NotNil(t, err, "The ID column should be present in the results")
+}
+
+func TestPaginateLazyByID(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	names := []string{}
+	qb.Table("table_test_paginate").
+		Select("id", "name").
+		MustLazyByID(3, func(item interface{}) error {
+			names = append(names, item.(xun.R).Get("name").(string))
+			return nil
+		}, "", "")
+	assert.Equal(t, []string{"John", "Lee", "Ken", "Ben"}, names, "The names should be in the order of id")
+}
+
+func TestPaginateCursorPaginate(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Select("id", "email", "status").
+		OrderBy("status").
+		OrderBy("id", "desc")
+
+	// the first page
+	paginator := qb.MustCursorPaginate(2, "")
+	assert.Equal(t, 2, paginator.PageSize, "The page size should be 2")
+	assert.Equal(t, 2, len(paginator.Items), "The items count should be 2")
+	assert.NotEqual(t, "", paginator.NextCursor, "The next cursor should be returned")
+	assert.Equal(t, "", paginator.PreviousCursor, "The previous cursor should be empty")
+	if len(paginator.Items) == 2 {
+		assert.Equal(t, int64(4), paginator.Items[0].(xun.R).Get("id"), "The first row id should be 4")
+		assert.Equal(t, int64(3), paginator.Items[1].(xun.R).Get("id"), "The second row id should be 3")
+	}
+
+	// the next page
+	paginator = qb.MustCursorPaginate(2, paginator.NextCursor)
+	assert.Equal(t, 2, len(paginator.Items), "The items count should be 2")
+	assert.Equal(t, "", paginator.NextCursor, "The next cursor should be empty")
+	assert.NotEqual(t, "", paginator.PreviousCursor, "The previous cursor should be returned")
+	if len(paginator.Items) == 2 {
+		assert.Equal(t, int64(2), paginator.Items[0].(xun.R).Get("id"), "The first row id should be 2")
+		assert.Equal(t, int64(1), paginator.Items[1].(xun.R).Get("id"), "The second row id should be 1")
+	}
+
+	// back to the first page
+	paginator = qb.MustCursorPaginate(2, paginator.PreviousCursor)
+	assert.Equal(t, 2, len(paginator.Items), "The items count should be 2")
+	assert.NotEqual(t, "", paginator.NextCursor, "The next cursor should be returned")
+	assert.Equal(t, "", paginator.PreviousCursor, "The previous cursor should be empty")
+	if len(paginator.Items) == 2 {
+		assert.Equal(t, int64(4), paginator.Items[0].(xun.R).Get("id"), "The first row id should be 4")
+		assert.Equal(t, int64(3), paginator.Items[1].(xun.R).Get("id"), "The second row id should be 3")
+	}
+}
+
+func TestPaginateCursorPaginateBind(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+
+	type Item struct {
+		ID    int64
+		Email string
+		Vote  int
+	}
+
+	items := []Item{}
+	paginator := qb.Table("table_test_paginate").
+		Select("id", "email", "vote").
+		OrderBy("vote", "desc").
+		OrderBy("id").
+		MustCursorPaginate(3, "", &items)
+
+	assert.Equal(t, 3, len(items), "The bound items count should be 3")
+	assert.Equal(t, 3, len(paginator.Items), "The items count should be 3")
+	if len(items) == 3 {
+		assert.Equal(t, int64(3), items[0].ID, "The first row id should be 3")
+		assert.Equal(t, int64(4), items[2].ID, "The third row id should be 4")
+	}
+
+	items = []Item{}
+	paginator = qb.MustCursorPaginate(3, paginator.NextCursor, &items)
+	assert.Equal(t, 1, len(items), "The bound items count should be 1")
+	assert.Equal(t, "", paginator.NextCursor, "The next cursor should be empty")
+	if len(items) == 1 {
+		assert.Equal(t, int64(2), items[0].ID, "The last row id should be 2")
+	}
+}
+
+func TestPaginateCursorPaginateError(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	_, err := qb.Table("table_test_paginate").OrderBy("id").CursorPaginate(2, "invalid")
+	assert.NotNil(t, err, "The invalid cursor should return an error")
+
+	_, err = qb.Table("table_test_paginate").OrderByRaw("id desc").CursorPaginate(2, "")
+	assert.NotNil(t, err, "The raw orders should return an error")
+
+	_, err = qb.Table("table_test_paginate").Select("email").OrderBy("id").CursorPaginate(2, "")
+	assert.NotNil(t, err, "The ordering columns should be selected")
+}
+
 // clean the test data
 func TestPaginateClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
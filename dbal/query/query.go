@@ -19,16 +19,22 @@ func (builder *Builder) Table(name string) Query {
 
 // Get Execute the query as a "select" statement.
 func (builder *Builder) Get(v ...interface{}) ([]xun.R, error) {
-	db := builder.DB()
-	stmt, err := db.Prepare(builder.ToSQL())
+	err := builder.checkWindows()
 	if err != nil {
-		defer log.With(log.F{"bindings": builder.GetBindings()}).Error(builder.ToSQL())
+		return nil, err
+	}
+
+	qb := builder.withScopes()
+	db := builder.executor()
+	stmt, err := db.PrepareContext(builder.Context(), qb.ToSQL())
+	if err != nil {
+		defer log.With(log.F{"bindings": qb.GetBindings()}).Error(qb.ToSQL())
 		return nil, err
 	}
 
 	defer stmt.Close()
 
-	rows, err := stmt.Query(builder.GetBindings()...)
+	rows, err := stmt.QueryContext(builder.Context(), qb.GetBindings()...)
 	if err != nil {
 		return nil, err
 	}
@@ -75,20 +81,26 @@ func (builder *Builder) MustFirst(v ...interface{}) xun.R {
 
 // ToSQL Get the SQL representation of the query.
 func (builder *Builder) ToSQL() string {
-	return builder.Grammar.CompileSelect(builder.Query)
+	return builder.Grammar.CompileSelect(builder.withScopes().Query)
 }
 
-// GetBindings Get the current query value bindings in a flattened array.
+// GetBindings Get the current query value bindings in a flattened array, the bindings of the global scopes are included.
 func (builder *Builder) GetBindings() []interface{} {
-	return builder.Query.GetBindings()
+	return builder.withScopes().Query.GetBindings()
 }
 
 // Exists Determine if any rows exist for the current query.
 func (builder *Builder) Exists() (bool, error) {
-	sql := builder.Grammar.CompileExists(builder.Query)
+	err := builder.checkWindows()
+	if err != nil {
+		return false, err
+	}
+
+	qb := builder.withScopes()
+	sql := builder.Grammar.CompileExists(qb.Query)
 
-	db := builder.DB()
-	rows, err := db.Query(sql, builder.GetBindings()...)
+	db := builder.executor()
+	rows, err := db.QueryContext(builder.Context(), sql, qb.Query.GetBindings()...)
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,112 @@
+package query
+
+import (
+	"time"
+
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/utils"
+)
+
+// the minimum versions of the databases supporting the returning clauses.
+// The SQLite bundled with go-sqlite3 v1.14.6 is 3.34.0, the returning clauses require go-sqlite3 v1.14.7 or later,
+// or building with the libsqlite3 tag against SQLite 3.35.0 or later.
+// MySQL does not support them, select the rows after writing instead, e.g.
+//    qb.Table("users").Where("id", 1).MustUpdate(xun.R{"name": "Ken"})
+//    rows := qb.Table("users").Where("id", 1).MustGet()
+var returningVersions = map[string]string{
+	"mysql":   "",
+	"sqlite3": "3.35.0",
+}
+
+// Returning Set the columns to be returned by the InsertReturning, UpdateReturning, UpsertReturning and DeleteReturning methods.
+// All of the columns are returned if the columns are not set.
+func (builder *Builder) Returning(columns ...interface{}) Query {
+	builder.Query.Returning = builder.prepareColumns(columns...)
+	return builder
+}
+
+// InsertReturning Insert new records into the database and get the inserted rows, including the server-generated values.
+func (builder *Builder) InsertReturning(v interface{}, columns ...interface{}) ([]xun.R, error) {
+	columns, values := builder.prepareInsertValues(v, columns...)
+	sql, bindings := builder.Grammar.CompileInsert(builder.Query, columns, values)
+	return builder.returning(sql, bindings)
+}
+
+// MustInsertReturning Insert new records into the database and get the inserted rows.
+func (builder *Builder) MustInsertReturning(v interface{}, columns ...interface{}) []xun.R {
+	rows, err := builder.InsertReturning(v, columns...)
+	utils.PanicIF(err)
+	return rows
+}
+
+// UpdateReturning Update records in the database and get the updated rows.
+func (builder *Builder) UpdateReturning(v interface{}) ([]xun.R, error) {
+	values := builder.updateTimestamps(xun.MakeR(v).ToMap())
+	sql, bindings := builder.Grammar.CompileUpdate(builder.withScopes().Query, values)
+	return builder.returning(sql, bindings)
+}
+
+// MustUpdateReturning Update records in the database and get the updated rows.
+func (builder *Builder) MustUpdateReturning(v interface{}) []xun.R {
+	rows, err := builder.UpdateReturning(v)
+	utils.PanicIF(err)
+	return rows
+}
+
+// UpsertReturning Upsert new records or update the existing ones, and get the inserted or updated rows.
+func (builder *Builder) UpsertReturning(v interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) ([]xun.R, error) {
+	columns, values := builder.prepareInsertValues(v, columns...)
+	sql, bindings := builder.Grammar.CompileUpsert(builder.Query, columns, values, builder.prepareUniqueBy(uniqueBy), builder.upsertTimestamps(update))
+	return builder.returning(sql, bindings)
+}
+
+// MustUpsertReturning Upsert new records or update the existing ones, and get the inserted or updated rows.
+func (builder *Builder) MustUpsertReturning(v interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) []xun.R {
+	rows, err := builder.UpsertReturning(v, uniqueBy, update, columns...)
+	utils.PanicIF(err)
+	return rows
+}
+
+// DeleteReturning Delete records from the database and get the deleted rows, the records are trashed instead if the soft deletes is enabled.
+func (builder *Builder) DeleteReturning() ([]xun.R, error) {
+	if builder.Query.SoftDeletes != "" {
+		return builder.UpdateReturning(xun.R{builder.Query.SoftDeletes: time.Now()})
+	}
+	sql, bindings := builder.Grammar.CompileDelete(builder.withScopes().Query)
+	return builder.returning(sql, bindings)
+}
+
+// MustDeleteReturning Delete records from the database and get the deleted rows.
+func (builder *Builder) MustDeleteReturning() []xun.R {
+	rows, err := builder.DeleteReturning()
+	utils.PanicIF(err)
+	return rows
+}
+
+// returning append the returning clause to the statement, execute it and scan the returned rows.
+func (builder *Builder) returning(sql string, bindings []interface{}) ([]xun.R, error) {
+	err := builder.supported("the returning clauses", returningVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := builder.Query.Returning
+	if len(columns) == 0 {
+		columns = []interface{}{"*"}
+	}
+	returning, err := builder.Grammar.CompileReturning(builder.Query, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	sql = sql + " " + returning
+	defer log.With(log.F{"bindings": bindings}).Debug(sql)
+
+	builder.UseWrite()
+	rows, err := builder.executor().QueryContext(builder.Context(), sql, bindings...)
+	if err != nil {
+		return nil, err
+	}
+	return builder.mapScan(rows)
+}
@@ -0,0 +1,165 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestReturningInsertReturning(t *testing.T) {
+	NewTableForReturningTest()
+	qb := getTestBuilder()
+	rows, err := qb.Table("table_test_returning").
+		Returning("id", "email", "status", "created_at").
+		InsertReturning([]xun.R{
+			{"email": "max@yao.run", "name": "Max"},
+			{"email": "ada@yao.run", "name": "Ada"},
+		})
+
+	if !supportedReturningForTest(t, err) {
+		return
+	}
+
+	assert.Equal(t, 2, len(rows), "the return value should have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, 3, rows[0].GetInt("id"), "the id of the 1st row should be 3")
+		assert.Equal(t, "max@yao.run", rows[0].GetString("email"), "the email of the 1st row should be max@yao.run")
+		assert.Equal(t, "enabled", rows[0].GetString("status"), "the status of the 1st row should be the default value")
+		assert.NotNil(t, rows[0]["created_at"], "the created_at of the 1st row should be generated by the server")
+		assert.Equal(t, 4, rows[1].GetInt("id"), "the id of the 2nd row should be 4")
+		assert.Equal(t, "ada@yao.run", rows[1].GetString("email"), "the email of the 2nd row should be ada@yao.run")
+		assert.Nil(t, rows[1]["name"], "the name should not be returned")
+	}
+}
+
+func TestReturningUpdateReturning(t *testing.T) {
+	NewTableForReturningTest()
+	qb := getTestBuilder()
+	rows, err := qb.Table("table_test_returning").
+		Where("id", 2).
+		UpdateReturning(xun.R{"status": "disabled"})
+
+	if !supportedReturningForTest(t, err) {
+		return
+	}
+
+	assert.Equal(t, 1, len(rows), "the return value should have 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, 2, rows[0].GetInt("id"), "the id of the row should be 2")
+		assert.Equal(t, "Lee", rows[0].GetString("name"), "all of the columns should be returned")
+		assert.Equal(t, "disabled", rows[0].GetString("status"), "the status of the row should be disabled")
+	}
+}
+
+func TestReturningUpsertReturning(t *testing.T) {
+	NewTableForReturningTest()
+	qb := getTestBuilder()
+	rows, err := qb.Table("table_test_returning").
+		Returning("id", "name").
+		UpsertReturning([]xun.R{
+			{"email": "john@yao.run", "name": "John Lee"},
+			{"email": "max@yao.run", "name": "Max"},
+		}, "email", []string{"name"})
+
+	if !supportedReturningForTest(t, err) {
+		return
+	}
+
+	assert.Equal(t, 2, len(rows), "the return value should have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, 1, rows[0].GetInt("id"), "the id of the updated row should be 1")
+		assert.Equal(t, "John Lee", rows[0].GetString("name"), "the name of the updated row should be John Lee")
+		assert.Equal(t, "Max", rows[1].GetString("name"), "the name of the inserted row should be Max")
+		assert.Greater(t, rows[1].GetInt("id"), 2, "the id of the inserted row should be generated")
+	}
+}
+
+func TestReturningDeleteReturning(t *testing.T) {
+	NewTableForReturningTest()
+	qb := getTestBuilder()
+	rows, err := qb.Table("table_test_returning").
+		Where("id", ">", 1).
+		Returning("id", "email").
+		DeleteReturning()
+
+	if !supportedReturningForTest(t, err) {
+		return
+	}
+
+	assert.Equal(t, 1, len(rows), "the return value should have 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, "lee@yao.run", rows[0].GetString("email"), "the email of the deleted row should be lee@yao.run")
+	}
+	assert.Equal(t, int64(1), qb.Table("table_test_returning").MustCount(), "the rows count should be 1, after delete")
+}
+
+func TestReturningMustDeleteReturningError(t *testing.T) {
+	NewTableForReturningTest()
+	assert.Panics(t, func() {
+		newQuery := New(unit.Driver(), unit.DSN())
+		newQuery.DB().Close()
+		newQuery.From("table_test_returning").MustDeleteReturning()
+	})
+}
+
+func TestReturningUnsupported(t *testing.T) {
+	NewTableForReturningTest()
+	qb := getTestBuilder()
+	version := qb.MustGetVersion()
+	if unit.DriverIs("postgres") || (unit.DriverIs("sqlite3") && version.Minor >= 35) {
+		return
+	}
+
+	_, err := qb.Table("table_test_returning").InsertReturning(xun.R{"email": "max@yao.run", "name": "Max"})
+	assert.False(t, supportedReturningForTest(t, err), "the returning clauses should not be supported")
+	assert.Equal(t, int64(2), qb.Table("table_test_returning").MustCount(), "the statement should not be executed")
+
+	_, err = qb.Table("table_test_returning").Where("email", "john@yao.run").DeleteReturning()
+	assert.False(t, supportedReturningForTest(t, err), "the returning clauses should not be supported")
+	assert.Equal(t, int64(2), qb.Table("table_test_returning").MustCount(), "the statement should not be executed")
+}
+
+// clean the test data
+func TestReturningClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_returning")
+}
+
+func NewTableForReturningTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_returning")
+	builder.MustCreateTable("table_test_returning", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.String("name").Null()
+		table.Enum("status", []string{"enabled", "disabled"}).SetDefault("enabled")
+		table.Timestamp("created_at").SetDefaultRaw("NOW()")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_returning").Insert([]xun.R{
+		{"email": "john@yao.run", "name": "John"},
+		{"email": "lee@yao.run", "name": "Lee"},
+	})
+}
+
+// supportedReturningForTest check the error of the returning clauses, returns false if the database does not support them.
+func supportedReturningForTest(t *testing.T, err error) bool {
+	if unit.DriverIs("mysql") {
+		assert.Equal(t, "the returning clauses are not supported by mysql", err.Error(), "the unsupported error should be returned")
+		return false
+	}
+
+	version := getTestBuilder().MustGetVersion()
+	if unit.DriverIs("sqlite3") && version.Minor < 35 {
+		assert.Equal(t, "the returning clauses are not supported by sqlite3 "+version.String()+", 3.35.0 or later is required", err.Error(), "the unsupported error should be returned")
+		return false
+	}
+
+	assert.Nil(t, err, "the return error should be nil")
+	return err == nil
+}
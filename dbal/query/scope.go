@@ -0,0 +1,193 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// NewScopes Create an empty registry of the scopes, the scopes registered on the "*" table are available to all the tables.
+func NewScopes() *Scopes {
+	return &Scopes{
+		local:  map[string]map[string]Scope{},
+		global: map[string][]namedScope{},
+	}
+}
+
+// AddScope Register a local scope on the table of the connection, the scope is applied to the query by the Scope method, e.g.
+//    qb.AddScope("users", "active", func(qb query.Query, args ...interface{}) { qb.Where("status", "active") })
+//    qb.AddScope("users", "vote", func(qb query.Query, args ...interface{}) { qb.Where("vote", ">", args[0]) })
+//    qb.Table("users").MustScope("active").MustScope("vote", 10).Get()
+func (builder *Builder) AddScope(table string, name string, scope Scope) {
+	scopes := builder.scopes()
+	table = strings.ToLower(table)
+	scopes.Lock()
+	defer scopes.Unlock()
+	if _, has := scopes.local[table]; !has {
+		scopes.local[table] = map[string]Scope{}
+	}
+	scopes.local[table][name] = scope
+}
+
+// AddGlobalScope Register a global scope on the table of the connection, the global scopes are applied to the select, update and delete statements
+// of the table when they are compiled, the scope registered with the same name is replaced, e.g.
+//    qb.AddGlobalScope("posts", "published", func(qb query.Query, args ...interface{}) { qb.WhereNotNull("published_at") })
+//    qb.Table("posts").Get()                                  // select * from "posts" where "published_at" is not null
+//    qb.Table("posts").WithoutGlobalScope("published").Get()  // select * from "posts"
+func (builder *Builder) AddGlobalScope(table string, name string, scope Scope) {
+	scopes := builder.scopes()
+	table = strings.ToLower(table)
+	scopes.Lock()
+	defer scopes.Unlock()
+	for i, global := range scopes.global[table] {
+		if global.name == name {
+			scopes.global[table][i].scope = scope
+			return
+		}
+	}
+	scopes.global[table] = append(scopes.global[table], namedScope{name: name, scope: scope})
+}
+
+// RemoveScope Remove the local or the global scope registered on the table of the connection
+func (builder *Builder) RemoveScope(table string, name string) {
+	scopes := builder.scopes()
+	table = strings.ToLower(table)
+	scopes.Lock()
+	defer scopes.Unlock()
+	delete(scopes.local[table], name)
+	globals := []namedScope{}
+	for _, global := range scopes.global[table] {
+		if global.name != name {
+			globals = append(globals, global)
+		}
+	}
+	scopes.global[table] = globals
+}
+
+// Scope Apply the local scope registered on the table of the query, the where clauses added by the scope are wrapped in parentheses if needed.
+// An error is returned if the scope is not registered.
+func (builder *Builder) Scope(name string, args ...interface{}) (Query, error) {
+	scopes := builder.scopes()
+	table := builder.scopeTable()
+	scopes.RLock()
+	scope, has := scopes.local[table][name]
+	if !has {
+		scope, has = scopes.local["*"][name]
+	}
+	scopes.RUnlock()
+
+	if !has {
+		return builder, fmt.Errorf("the scope %s is not defined on the %s table", name, table)
+	}
+
+	builder.applyScope(scope, args...)
+	return builder, nil
+}
+
+// MustScope Apply the local scope registered on the table of the query, it panics if the scope is not registered.
+func (builder *Builder) MustScope(name string, args ...interface{}) Query {
+	qb, err := builder.Scope(name, args...)
+	utils.PanicIF(err)
+	return qb
+}
+
+// WithoutGlobalScope Remove the global scope from the query
+func (builder *Builder) WithoutGlobalScope(name string) Query {
+	builder.Query.WithoutScopes = append(builder.Query.WithoutScopes, name)
+	return builder
+}
+
+// WithoutGlobalScopes Remove the given global scopes from the query, all of them are removed if no name is given.
+func (builder *Builder) WithoutGlobalScopes(names ...string) Query {
+	if len(names) == 0 {
+		names = []string{"*"}
+	}
+	builder.Query.WithoutScopes = append(builder.Query.WithoutScopes, names...)
+	return builder
+}
+
+// withScopes apply the global scopes and the soft deletes to a clone of the builder before compiling the statements,
+// the builder itself is returned if there is nothing to apply.
+func (builder *Builder) withScopes() *Builder {
+	globals := builder.globalScopes()
+	trashed := builder.trashedScope()
+	if len(globals) == 0 && trashed == nil {
+		return builder
+	}
+
+	qb := builder.clone()
+	qb.Query.SoftDeletes = ""
+	qb.Query.WithoutScopes = []string{"*"}
+	for _, global := range globals {
+		qb.applyScope(global.scope)
+	}
+	if trashed != nil {
+		qb.applyScope(trashed)
+	}
+	return qb
+}
+
+// globalScopes get the global scopes of the table which are not removed from the query
+func (builder *Builder) globalScopes() []namedScope {
+	without := builder.Query.WithoutScopes
+	if builder.Query.From.Type != "basic" || utils.StringHave(without, "*") {
+		return nil
+	}
+
+	scopes := builder.scopes()
+	table := builder.scopeTable()
+	scopes.RLock()
+	defer scopes.RUnlock()
+	globals := []namedScope{}
+	for _, registered := range [][]namedScope{scopes.global["*"], scopes.global[table]} {
+		for _, global := range registered {
+			if !utils.StringHave(without, global.name) {
+				globals = append(globals, global)
+			}
+		}
+	}
+	return globals
+}
+
+// applyScope apply the scope to the query, the where clauses before and after applying are wrapped in parentheses separately
+// if they have the or clauses, e.g.
+//    where a = ? or b = ?  + the scope of c = ?  => where (a = ? or b = ?) and c = ?
+func (builder *Builder) applyScope(scope Scope, args ...interface{}) {
+	offset := len(builder.Query.Wheres)
+	scope(builder, args...)
+	wheres := builder.Query.Wheres
+	builder.Query.Wheres = []dbal.Where{}
+	builder.Query.Wheres = append(builder.Query.Wheres, builder.nestWheres(wheres[:offset])...)
+	builder.Query.Wheres = append(builder.Query.Wheres, builder.nestWheres(wheres[offset:])...)
+}
+
+// nestWheres wrap the where clauses in a nested where clause if they have the or clauses, the bindings are in the same order.
+func (builder *Builder) nestWheres(wheres []dbal.Where) []dbal.Where {
+	for _, where := range wheres {
+		if where.Boolean == "or" {
+			nested := dbal.NewQuery()
+			nested.From = builder.Query.From
+			nested.Wheres = append(nested.Wheres, wheres...)
+			return []dbal.Where{{Type: "nested", Query: nested, Boolean: "and"}}
+		}
+	}
+	return wheres
+}
+
+// scopes get the scopes registered on the connection
+func (builder *Builder) scopes() *Scopes {
+	if builder.Conn.Scopes == nil {
+		builder.Conn.Scopes = NewScopes()
+	}
+	return builder.Conn.Scopes
+}
+
+// scopeTable get the table name which the scopes are registered on
+func (builder *Builder) scopeTable() string {
+	if name, ok := builder.Query.From.Name.(dbal.Name); ok {
+		return name.Name
+	}
+	return ""
+}
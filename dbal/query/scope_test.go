@@ -0,0 +1,330 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestScopeScope(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	qb.AddScope("table_test_scope", "active", func(qb Query, args ...interface{}) {
+		qb.Where("status", "active")
+	})
+	qb.AddScope("table_test_scope", "vote", func(qb Query, args ...interface{}) {
+		qb.Where("vote", ">", args[0])
+	})
+	defer qb.RemoveScope("table_test_scope", "active")
+	defer qb.RemoveScope("table_test_scope", "vote")
+
+	rows := qb.Table("table_test_scope").MustScope("active").MustScope("vote", 10).OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"Ken", "Max"}, testScopeNames(rows), "the scopes should be applied")
+
+	rows = qb.New().Table("table_test_scope").MustScope("active").OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"John", "Ken", "Max"}, testScopeNames(rows), "the scopes should be shared by the builders of the connection")
+
+	_, err := qb.Table("table_test_scope").Scope("published")
+	assert.EqualError(t, err, "the scope published is not defined on the table_test_scope table", "the error should be returned")
+	assert.Panics(t, func() {
+		qb.Table("table_test_scope").MustScope("published")
+	}, "the undefined scope should panic")
+}
+
+func TestScopeConnection(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	qb.AddScope("table_test_scope", "active", func(qb Query, args ...interface{}) {
+		qb.Where("status", "active")
+	})
+	defer qb.RemoveScope("table_test_scope", "active")
+
+	conn := *qb.Builder().Conn
+	conn.Scopes = nil
+	other := Use(&conn)
+	_, err := other.Table("table_test_scope").Scope("active")
+	assert.EqualError(t, err, "the scope active is not defined on the table_test_scope table", "the scopes should not be shared by the other connections")
+}
+
+func TestScopeScopeOrWhere(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	qb.AddScope("table_test_scope", "popular", func(qb Query, args ...interface{}) {
+		qb.Where("vote", ">", 20).OrWhere("name", "John")
+	})
+	defer qb.RemoveScope("table_test_scope", "popular")
+
+	qb.Table("table_test_scope").
+		Where("tenant_id", 1).
+		OrWhere("status", "inactive").
+		MustScope("popular")
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_scope" where ("tenant_id" = $1 or "status" = $2) and ("vote" > $3 or "name" = $4)`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_scope` where (`tenant_id` = ? or `status` = ?) and (`vote` > ? or `name` = ?)", sql, "the query sql not equal")
+	}
+	assert.Equal(t, []interface{}{1, "inactive", 20, "John"}, qb.GetBindings(), "the bindings should be in order")
+
+	rows := qb.OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"John", "Max", "Ben"}, testScopeNames(rows), "the scope should be wrapped in parentheses")
+}
+
+func TestScopeGlobalScope(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	qb.AddGlobalScope("table_test_scope", "tenant", func(qb Query, args ...interface{}) {
+		qb.Where("tenant_id", 1)
+	})
+	defer qb.RemoveScope("table_test_scope", "tenant")
+
+	qb.Table("table_test_scope").Where("vote", ">", 10)
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_scope" where "vote" > $1 and "tenant_id" = $2`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_scope` where `vote` > ? and `tenant_id` = ?", sql, "the query sql not equal")
+	}
+	assert.Equal(t, []interface{}{10, 1}, qb.GetBindings(), "the bindings of the global scopes should be included")
+	assert.Equal(t, 1, len(qb.Builder().Query.Wheres), "the global scopes should not change the query")
+
+	rows := qb.OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"Lee", "Max"}, testScopeNames(rows), "the global scope should be applied")
+
+	count := qb.Table("table_test_scope").MustCount()
+	assert.Equal(t, int64(3), count, "the global scope should be applied to the count")
+
+	paginator := qb.Table("table_test_scope").OrderBy("id").MustPaginate(2, 1)
+	assert.Equal(t, 3, paginator.Total, "the global scope should be applied to the paginator")
+
+	row := qb.Table("table_test_scope").Where("name", "Ken").MustFirst()
+	assert.True(t, row.IsEmpty(), "the global scope should be applied to the first")
+
+	count = qb.Table("table_test_scope").WithoutGlobalScope("tenant").MustCount()
+	assert.Equal(t, int64(5), count, "the global scope should be removed")
+
+	count = qb.Table("table_test_scope").WithoutGlobalScopes().MustCount()
+	assert.Equal(t, int64(5), count, "all the global scopes should be removed")
+}
+
+func TestScopeGlobalScopeGroupPaginate(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	qb.AddGlobalScope("table_test_scope", "tenant", func(qb Query, args ...interface{}) {
+		qb.Where("tenant_id", 1)
+	})
+	defer qb.RemoveScope("table_test_scope", "tenant")
+
+	paginator := qb.Table("table_test_scope").
+		Select("status").
+		GroupBy("status").
+		OrderBy("status").
+		MustPaginate(1, 1)
+	assert.Equal(t, 2, paginator.Total, "the global scope should be applied to the grouped count")
+	assert.Equal(t, 1, len(paginator.Items), "the global scope should be applied to the grouped items")
+
+	paginator = qb.Table("table_test_scope").
+		Select("status").
+		GroupBy("status").
+		Having("status", "active").
+		MustPaginate(1, 1)
+	assert.Equal(t, 1, paginator.Total, "the bindings of the global scope should be merged before the having bindings")
+}
+
+func TestScopeGlobalScopeUpdateAndDelete(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	qb.AddGlobalScope("table_test_scope", "tenant", func(qb Query, args ...interface{}) {
+		qb.Where("tenant_id", 1)
+	})
+	defer qb.RemoveScope("table_test_scope", "tenant")
+
+	affected := qb.Table("table_test_scope").Where("status", "active").MustUpdate(xun.R{"vote": 0})
+	assert.Equal(t, int64(2), affected, "the global scope should be applied to the update")
+
+	affected = qb.Table("table_test_scope").Where("status", "active").MustIncrement("vote", 1)
+	assert.Equal(t, int64(2), affected, "the global scope should be applied to the increment")
+
+	affected = qb.Table("table_test_scope").Where("status", "inactive").MustDelete()
+	assert.Equal(t, int64(1), affected, "the global scope should be applied to the delete")
+
+	rows := qb.Table("table_test_scope").WithoutGlobalScope("tenant").Where("vote", 1).OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"John", "Max"}, testScopeNames(rows), "the rows of the other tenants should not be updated")
+
+	count := qb.Table("table_test_scope").WithoutGlobalScope("tenant").MustCount()
+	assert.Equal(t, int64(4), count, "the rows of the other tenants should not be deleted")
+}
+
+func TestScopeGlobalScopeAllTables(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	qb.AddGlobalScope("*", "tenant", func(qb Query, args ...interface{}) {
+		qb.Where("tenant_id", 2)
+	})
+	qb.AddGlobalScope("table_test_scope", "active", func(qb Query, args ...interface{}) {
+		qb.Where("status", "active")
+	})
+	defer qb.RemoveScope("*", "tenant")
+	defer qb.RemoveScope("table_test_scope", "active")
+
+	rows := qb.Table("table_test_scope").SoftDeletes().MustGet()
+	assert.Equal(t, []interface{}{"Ken"}, testScopeNames(rows), "the global scopes of all the tables should be applied")
+
+	rows = qb.Table("table_test_scope").WithoutGlobalScope("active").OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"Ken", "Ben"}, testScopeNames(rows), "the removed global scope should not be applied")
+}
+
+func TestScopeSubQuery(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	qb.AddGlobalScope("table_test_scope", "tenant", func(qb Query, args ...interface{}) {
+		qb.Where("tenant_id", 1)
+	})
+	defer qb.RemoveScope("table_test_scope", "tenant")
+
+	rows := qb.Table("table_test_scope").WithoutGlobalScopes().
+		WhereIn("id", func(sub Query) {
+			sub.Select("id").From("table_test_scope")
+		}).
+		OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"John", "Lee", "Max"}, testScopeNames(rows), "the global scope should be applied to the where in subquery")
+
+	sub := qb.New().Table("table_test_scope").Select("id")
+	rows = qb.Table("table_test_scope").WithoutGlobalScopes().WhereIn("id", sub).OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"John", "Lee", "Max"}, testScopeNames(rows), "the global scope should be applied to the subquery builder")
+	assert.Equal(t, 0, len(sub.Builder().Query.Wheres), "the global scope should not change the subquery builder")
+
+	rows = qb.Table("table_test_scope").WithoutGlobalScopes().
+		Where("vote", ">", func(sub Query) {
+			sub.SelectRaw("avg(vote)").From("table_test_scope")
+		}).
+		OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"Ken", "Max", "Ben"}, testScopeNames(rows), "the global scope should be applied to the where subquery")
+
+	count := qb.Reset().FromSub(func(sub Query) {
+		sub.From("table_test_scope")
+	}, "t").MustCount()
+	assert.Equal(t, int64(3), count, "the global scope should be applied to the from subquery")
+}
+
+func TestScopeWhereExists(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	qb.AddGlobalScope("table_test_scope", "tenant", func(qb Query, args ...interface{}) {
+		qb.Where("tenant_id", 1)
+	})
+	defer qb.RemoveScope("table_test_scope", "tenant")
+
+	rows := qb.Table("table_test_scope as t1").WithoutGlobalScopes().
+		WhereExists(func(sub Query) {
+			sub.SelectRaw("1").
+				From("table_test_scope as t2").
+				WhereColumn("t2.vote", ">", "t1.vote")
+		}).
+		OrderBy("t1.id").MustGet()
+	assert.Equal(t, []interface{}{"John", "Lee", "Ken"}, testScopeNames(rows), "the global scope should be applied to the exists subquery")
+}
+
+func TestScopeJoinSub(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	qb.AddGlobalScope("table_test_scope", "tenant", func(qb Query, args ...interface{}) {
+		qb.Where("tenant_id", 1)
+	})
+	defer qb.RemoveScope("table_test_scope", "tenant")
+
+	rows := qb.Table("table_test_scope").WithoutGlobalScopes().
+		JoinSub(func(sub Query) {
+			sub.Select("id").From("table_test_scope")
+		}, "t", "t.id", "=", "table_test_scope.id").
+		Select("table_test_scope.name").
+		OrderBy("table_test_scope.id").MustGet()
+	assert.Equal(t, []interface{}{"John", "Lee", "Max"}, testScopeNames(rows), "the global scope should be applied to the join subquery")
+}
+
+func TestScopeUnion(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	qb.AddGlobalScope("table_test_scope", "tenant", func(qb Query, args ...interface{}) {
+		qb.Where("tenant_id", 1)
+	})
+	defer qb.RemoveScope("table_test_scope", "tenant")
+
+	rows := qb.Table("table_test_scope").WithoutGlobalScopes().
+		Select("name").
+		Where("name", "Ben").
+		UnionAll(func(sub Query) {
+			sub.Select("name").From("table_test_scope")
+		}).
+		MustGet()
+	assert.Equal(t, 4, len(rows), "the global scope should be applied to the union query")
+
+	union := qb.New().Table("table_test_scope").Select("name")
+	rows = qb.Table("table_test_scope").WithoutGlobalScopes().
+		Select("name").
+		Where("name", "Ben").
+		UnionAll(union).
+		MustGet()
+	assert.Equal(t, 4, len(rows), "the global scope should be applied to the union builder")
+}
+
+func TestScopeSubQuerySoftDeletes(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_scope").Where("name", "Lee").MustUpdate(xun.R{"deleted_at": "2021-01-01 00:00:00"})
+
+	rows := qb.Table("table_test_scope").
+		WhereIn("id", func(sub Query) {
+			sub.Select("id").From("table_test_scope").SoftDeletes().Where("vote", "<", 20)
+		}).
+		OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"John"}, testScopeNames(rows), "the soft deleted rows should be excluded from the subquery")
+
+	rows = qb.Table("table_test_scope").
+		WhereExists(func(sub Query) {
+			sub.SelectRaw("1").From("table_test_scope as t2").SoftDeletes().WhereColumn("t2.id", "table_test_scope.id")
+		}).
+		MustGet()
+	assert.Equal(t, 4, len(rows), "the soft deleted rows should be excluded from the exists subquery")
+}
+
+func testScopeNames(rows []xun.R) []interface{} {
+	names := []interface{}{}
+	for _, row := range rows {
+		names = append(names, row["name"])
+	}
+	return names
+}
+
+// clean the test data
+func TestScopeClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_scope")
+}
+
+func NewTableForScopeTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_scope")
+	builder.MustCreateTable("table_test_scope", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("name", 80)
+		table.String("status", 20)
+		table.Integer("tenant_id")
+		table.Integer("vote")
+		table.SoftDeletes()
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_scope").MustInsert([]xun.R{
+		{"name": "John", "status": "active", "tenant_id": 1, "vote": 5},
+		{"name": "Lee", "status": "inactive", "tenant_id": 1, "vote": 15},
+		{"name": "Ken", "status": "active", "tenant_id": 2, "vote": 20},
+		{"name": "Max", "status": "active", "tenant_id": 1, "vote": 25},
+		{"name": "Ben", "status": "inactive", "tenant_id": 2, "vote": 30},
+	})
+}
@@ -7,6 +7,12 @@ import (
 	"github.com/yaoapp/xun/dbal"
 )
 
+// the minimum versions of the databases supporting the window functions
+var windowVersions = map[string]string{
+	"mysql":   "8.0.0",
+	"sqlite3": "3.25.0",
+}
+
 // SQL Add a new "raw" sql STMT to the query.
 func (builder *Builder) SQL(stmt string, bindings ...interface{}) Query {
 	builder.Query.SQL = stmt
@@ -66,6 +72,65 @@ func (builder *Builder) SelectSub(qb interface{}, as string) Query {
 	return builder
 }
 
+// SelectWindow Add a window function expression to the query. The over argument could be the name of
+// a window defined by the Window method, or a dbal.Window specification, e.g.
+//    SelectWindow("sum", []interface{}{"salary"}, dbal.Window{PartitionBy: []interface{}{"department"}}, "total")
+//    SelectWindow("row_number", nil, "w", "rn")
+// The window and the support of the window functions are checked when the query is executed.
+func (builder *Builder) SelectWindow(function string, args []interface{}, over interface{}, alias string) Query {
+	var window *dbal.Window
+	switch value := over.(type) {
+	case string:
+		window = &dbal.Window{Name: value}
+	case dbal.Window:
+		window = &value
+	case *dbal.Window:
+		window = value
+	}
+
+	builder.addSelect(dbal.Select{
+		Type:   "window",
+		Name:   function,
+		Args:   args,
+		Window: window,
+		Alias:  alias,
+	})
+	return builder
+}
+
+// SelectRowNumber Add a row_number() window function to the query.
+func (builder *Builder) SelectRowNumber(over interface{}, alias string) Query {
+	return builder.SelectWindow("row_number", nil, over, alias)
+}
+
+// SelectRank Add a rank() window function to the query.
+func (builder *Builder) SelectRank(over interface{}, alias string) Query {
+	return builder.SelectWindow("rank", nil, over, alias)
+}
+
+// SelectDenseRank Add a dense_rank() window function to the query.
+func (builder *Builder) SelectDenseRank(over interface{}, alias string) Query {
+	return builder.SelectWindow("dense_rank", nil, over, alias)
+}
+
+// SelectLag Add a lag(column, offset) window function to the query.
+func (builder *Builder) SelectLag(column interface{}, offset int, over interface{}, alias string) Query {
+	return builder.SelectWindow("lag", []interface{}{column, offset}, over, alias)
+}
+
+// SelectLead Add a lead(column, offset) window function to the query.
+func (builder *Builder) SelectLead(column interface{}, offset int, over interface{}, alias string) Query {
+	return builder.SelectWindow("lead", []interface{}{column, offset}, over, alias)
+}
+
+// Window Add a named window to the query, e.g.
+//    Window("w", dbal.Window{PartitionBy: []interface{}{"department"}, OrderBy: []interface{}{"salary desc"}})
+func (builder *Builder) Window(name string, window dbal.Window) Query {
+	window.Name = name
+	builder.Query.Windows = append(builder.Query.Windows, window)
+	return builder
+}
+
 // Distinct Force the query to only return distinct results.
 func (builder *Builder) Distinct(args ...interface{}) Query {
 	if len(args) > 0 {
@@ -107,3 +172,24 @@ func (builder *Builder) addSelect(column interface{}) {
 		builder.Query.Columns = append(builder.Query.Columns, column)
 	}
 }
+
+// checkWindows check the windows of the window functions, returns an error if the window is not a name or a dbal.Window
+// or the database does not support the window functions.
+func (builder *Builder) checkWindows() error {
+	used := len(builder.Query.Windows) > 0
+	for _, col := range builder.Query.Columns {
+		column, ok := col.(dbal.Select)
+		if !ok || column.Type != "window" {
+			continue
+		}
+		if column.Window == nil {
+			return fmt.Errorf("the window of %v should be a name or a dbal.Window", column.Name)
+		}
+		used = true
+	}
+
+	if !used {
+		return nil
+	}
+	return builder.supported("the window functions", windowVersions)
+}
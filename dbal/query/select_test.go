@@ -256,6 +256,147 @@ func TestSelectDistinctColumnsStyle3(t *testing.T) {
 	checktestSelectDistinctColumns(t, qb)
 }
 
+func TestSelectSelectWindowTopN(t *testing.T) {
+	NewTableFoSelectTest()
+	qb := getTestBuilder()
+	if unit.Is("mysql5.7") || unit.Is("mysql5.6") {
+		_, err := qb.From("table_test_select").SelectRowNumber("w", "rn").Window("w", dbal.Window{OrderBy: []interface{}{"id"}}).Get()
+		assert.EqualError(t, err, "the window functions are not supported by mysql "+qb.MustGetVersion().String()+", 8.0.0 or later is required")
+		return
+	}
+
+	qb.FromSub(func(qb Query) {
+		qb.From("table_test_select").
+			Select("id", "name", "cate").
+			SelectRowNumber(dbal.Window{PartitionBy: []interface{}{"cate"}, OrderBy: []interface{}{"score desc"}}, "rn")
+	}, "ranked").
+		Where("rn", "<=", 1).
+		OrderBy("id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from (select "id", "name", "cate", row_number() over (partition by "cate" order by "score" desc) as "rn" from "table_test_select") as "ranked" where "rn" <= $1 order by "id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from (select `id`, `name`, `cate`, row_number() over (partition by `cate` order by `score` desc) as `rn` from `table_test_select`) as `ranked` where `rn` <= ? order by `id` asc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, "John", rows[0]["name"].(string), "the name of first row should be John")
+		assert.Equal(t, "Ken", rows[1]["name"].(string), "the name of second row should be Ken")
+	}
+}
+
+func TestSelectSelectWindowNamed(t *testing.T) {
+	NewTableFoSelectTest()
+	qb := getTestBuilder()
+	if unit.Is("mysql5.7") || unit.Is("mysql5.6") {
+		return
+	}
+	qb.From("table_test_select").
+		Select("id", "name").
+		SelectRank("w", "rnk").
+		SelectDenseRank("w", "dense_rnk").
+		Window("w", dbal.Window{OrderBy: []interface{}{"vote desc"}}).
+		OrderBy("id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id", "name", rank() over "w" as "rnk", dense_rank() over "w" as "dense_rnk" from "table_test_select" window "w" as (order by "vote" desc) order by "id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id`, `name`, rank() over `w` as `rnk`, dense_rank() over `w` as `dense_rnk` from `table_test_select` window `w` as (order by `vote` desc) order by `id` asc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 4, len(rows), "the return value should be have 4 rows")
+	if len(rows) == 4 {
+		assert.Equal(t, []int{1, 3, 3, 2}, []int{rows[0].GetInt("rnk"), rows[1].GetInt("rnk"), rows[2].GetInt("rnk"), rows[3].GetInt("rnk")}, "the ranks should be 1, 3, 3, 2")
+		assert.Equal(t, []int{1, 3, 3, 2}, []int{rows[0].GetInt("dense_rnk"), rows[1].GetInt("dense_rnk"), rows[2].GetInt("dense_rnk"), rows[3].GetInt("dense_rnk")}, "the dense ranks should be 1, 3, 3, 2")
+	}
+}
+
+func TestSelectSelectWindowLagLead(t *testing.T) {
+	NewTableFoSelectTest()
+	qb := getTestBuilder()
+	if unit.Is("mysql5.7") || unit.Is("mysql5.6") {
+		return
+	}
+	over := dbal.Window{OrderBy: []interface{}{"id"}}
+	qb.From("table_test_select").
+		Select("id").
+		SelectLag("name", 1, over, "prev").
+		SelectLead("name", 1, over, "next").
+		OrderBy("id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id", lag("name", 1) over (order by "id" asc) as "prev", lead("name", 1) over (order by "id" asc) as "next" from "table_test_select" order by "id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id`, lag(`name`, 1) over (order by `id` asc) as `prev`, lead(`name`, 1) over (order by `id` asc) as `next` from `table_test_select` order by `id` asc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 4, len(rows), "the return value should be have 4 rows")
+	if len(rows) == 4 {
+		assert.Nil(t, rows[0]["prev"], "the prev of first row should be nil")
+		assert.Equal(t, "Lee", rows[0]["next"].(string), "the next of first row should be Lee")
+		assert.Equal(t, "Ken", rows[3]["prev"].(string), "the prev of last row should be Ken")
+		assert.Nil(t, rows[3]["next"], "the next of last row should be nil")
+	}
+}
+
+func TestSelectSelectWindowSum(t *testing.T) {
+	NewTableFoSelectTest()
+	qb := getTestBuilder()
+	if unit.Is("mysql5.7") || unit.Is("mysql5.6") {
+		return
+	}
+	qb.From("table_test_select").
+		Select("id", "cate").
+		SelectWindow("sum", []interface{}{"vote"}, dbal.Window{PartitionBy: []interface{}{"cate"}}, "total").
+		SelectWindow("sum", []interface{}{"vote"}, &dbal.Window{
+			PartitionBy: []interface{}{"cate"},
+			OrderBy:     []interface{}{"id"},
+			Frame:       "rows between unbounded preceding and current row",
+		}, "running").
+		OrderBy("id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id", "cate", sum("vote") over (partition by "cate") as "total", sum("vote") over (partition by "cate" order by "id" asc rows between unbounded preceding and current row) as "running" from "table_test_select" order by "id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id`, `cate`, sum(`vote`) over (partition by `cate`) as `total`, sum(`vote`) over (partition by `cate` order by `id` asc rows between unbounded preceding and current row) as `running` from `table_test_select` order by `id` asc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 4, len(rows), "the return value should be have 4 rows")
+	if len(rows) == 4 {
+		assert.Equal(t, []int{14, 10, 10, 14}, []int{rows[0].GetInt("total"), rows[1].GetInt("total"), rows[2].GetInt("total"), rows[3].GetInt("total")}, "the totals should be 14, 10, 10, 14")
+		assert.Equal(t, []int{8, 5, 10, 14}, []int{rows[0].GetInt("running"), rows[1].GetInt("running"), rows[2].GetInt("running"), rows[3].GetInt("running")}, "the running totals should be 8, 5, 10, 14")
+	}
+}
+
+func TestSelectSelectWindowErrorType(t *testing.T) {
+	NewTableFoSelectTest()
+	qb := getTestBuilder()
+	assert.NotPanics(t, func() {
+		qb.From("table_test_select").SelectRowNumber(1, "rn")
+	})
+	_, err := qb.Get()
+	assert.EqualError(t, err, "the window of row_number should be a name or a dbal.Window")
+	_, err = qb.Exists()
+	assert.EqualError(t, err, "the window of row_number should be a name or a dbal.Window")
+}
+
 // clean the test data
 func TestSelectClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
@@ -0,0 +1,103 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// SoftDeletes Enable the soft deletes of the query, the column defaults to deleted_at.
+// The trashed records are excluded from the select and update statements, and the Delete method sets the column instead of deleting the records, e.g.
+//    qb.Table("users").SoftDeletes().Where("vote", ">", 10).Get()  // ... where "vote" > ? and "users"."deleted_at" is null
+//    qb.Table("users").SoftDeletes().Where("id", 1).Delete()       // update "users" set "deleted_at" = ? where ...
+// The tables listed in the SoftDeletes of the connection option are enabled by default.
+func (builder *Builder) SoftDeletes(column ...string) Query {
+	builder.Query.SoftDeletes = "deleted_at"
+	if len(column) > 0 && column[0] != "" {
+		builder.Query.SoftDeletes = column[0]
+	}
+	return builder
+}
+
+// WithTrashed Include the trashed records in the query
+func (builder *Builder) WithTrashed() Query {
+	builder.Query.Trashed = "with"
+	return builder
+}
+
+// OnlyTrashed Query the trashed records only
+func (builder *Builder) OnlyTrashed() Query {
+	builder.Query.Trashed = "only"
+	return builder
+}
+
+// Restore Restore the trashed records matching the query, returns the number of the restored records.
+func (builder *Builder) Restore() (int64, error) {
+	if builder.Query.SoftDeletes == "" {
+		return 0, fmt.Errorf("the soft deletes of the query is not enabled")
+	}
+	qb := builder.clone()
+	qb.Query.Trashed = "only"
+	return qb.Update(xun.R{qb.Query.SoftDeletes: nil})
+}
+
+// MustRestore Restore the trashed records matching the query, returns the number of the restored records.
+func (builder *Builder) MustRestore() int64 {
+	affected, err := builder.Restore()
+	utils.PanicIF(err)
+	return affected
+}
+
+// ForceDelete Delete the records from the database permanently whether the soft deletes is enabled or not.
+// The trashed records are excluded unless the WithTrashed or OnlyTrashed is called, e.g.
+//    qb.Table("users").SoftDeletes().OnlyTrashed().ForceDelete() // purge the trashed users
+func (builder *Builder) ForceDelete() (int64, error) {
+	return builder.forceDelete()
+}
+
+// MustForceDelete Delete the records from the database permanently whether the soft deletes is enabled or not.
+func (builder *Builder) MustForceDelete() int64 {
+	affected, err := builder.ForceDelete()
+	utils.PanicIF(err)
+	return affected
+}
+
+// softDelete set the soft delete column of the records which are not trashed to the current time
+func (builder *Builder) softDelete() (int64, error) {
+	return builder.Update(xun.R{builder.Query.SoftDeletes: time.Now()})
+}
+
+// trashedScope get the scope constraining the query by the soft delete column depending on the trashed records to query,
+// nil is returned if the soft deletes is not enabled or the trashed records are included.
+func (builder *Builder) trashedScope() Scope {
+	if builder.Query.SoftDeletes == "" || builder.Query.Trashed == "with" || builder.Query.From.Type != "basic" {
+		return nil
+	}
+
+	column := builder.softDeleteColumn()
+	only := builder.Query.Trashed == "only"
+	return func(qb Query, args ...interface{}) {
+		qb.WhereNull(column, "and", only)
+	}
+}
+
+// softDeleteColumn get the soft delete column qualified by the alias or the name of the table
+func (builder *Builder) softDeleteColumn() string {
+	column := builder.Query.SoftDeletes
+	if strings.Contains(column, ".") {
+		return column
+	}
+
+	if builder.Query.From.Alias != "" {
+		return fmt.Sprintf("%s.%s", builder.Query.From.Alias, column)
+	}
+
+	if name, ok := builder.Query.From.Name.(dbal.Name); ok {
+		return fmt.Sprintf("%s.%s", name.Fullname(), column)
+	}
+	return column
+}
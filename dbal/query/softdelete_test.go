@@ -0,0 +1,172 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestSoftDeleteGet(t *testing.T) {
+	NewTableForSoftDeleteTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_softdelete").SoftDeletes().OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"John", "Ken", "Ben"}, testSoftDeleteNames(rows), "the trashed rows should be excluded")
+
+	row := qb.Table("table_test_softdelete").SoftDeletes().Where("name", "Lee").MustFirst()
+	assert.True(t, row.IsEmpty(), "the trashed row should not be found")
+
+	count := qb.Table("table_test_softdelete").SoftDeletes().MustCount()
+	assert.Equal(t, int64(3), count, "the count should be 3")
+
+	exists := qb.Table("table_test_softdelete").SoftDeletes().Where("name", "Lee").MustExists()
+	assert.False(t, exists, "the trashed row should not exist")
+
+	paginator := qb.Table("table_test_softdelete").SoftDeletes().OrderBy("id").MustPaginate(2, 1)
+	assert.Equal(t, 3, paginator.Total, "the total should be 3")
+	assert.Equal(t, 2, len(paginator.Items), "the page should have 2 items")
+
+	count = qb.Table("table_test_softdelete").MustCount()
+	assert.Equal(t, int64(5), count, "the soft deletes should be opt-in")
+}
+
+func TestSoftDeleteWithTrashed(t *testing.T) {
+	NewTableForSoftDeleteTest()
+	qb := getTestBuilder()
+	count := qb.Table("table_test_softdelete").SoftDeletes().WithTrashed().MustCount()
+	assert.Equal(t, int64(5), count, "the trashed rows should be included")
+
+	rows := qb.Table("table_test_softdelete").SoftDeletes().OnlyTrashed().OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"Lee", "Max"}, testSoftDeleteNames(rows), "the trashed rows only should be returned")
+}
+
+func TestSoftDeleteOrWhere(t *testing.T) {
+	NewTableForSoftDeleteTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_softdelete").SoftDeletes().
+		Where("vote", ">", 10).
+		OrWhere("name", "Max")
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_softdelete" where ("vote" > $1 or "name" = $2) and "table_test_softdelete"."deleted_at" is null`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_softdelete` where (`vote` > ? or `name` = ?) and `table_test_softdelete`.`deleted_at` is null", sql, "the query sql not equal")
+	}
+
+	rows := qb.OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"Ken", "Ben"}, testSoftDeleteNames(rows), "the trashed rows should be excluded from the or clauses")
+}
+
+func TestSoftDeleteWithJoin(t *testing.T) {
+	NewTableForSoftDeleteTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_softdelete as t1").SoftDeletes().
+		Join("table_test_softdelete as t2", "t2.id", "=", "t1.id").
+		Select("t1.name").
+		OrderBy("t1.id").
+		MustGet()
+	assert.Equal(t, []interface{}{"John", "Ken", "Ben"}, testSoftDeleteNames(rows), "the soft delete column should be qualified by the alias")
+}
+
+func TestSoftDeleteDelete(t *testing.T) {
+	NewTableForSoftDeleteTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_softdelete").SoftDeletes().Where("vote", ">", 10).MustDelete()
+	assert.Equal(t, int64(2), affected, "the affected rows should be 2")
+
+	count := qb.Table("table_test_softdelete").MustCount()
+	assert.Equal(t, int64(5), count, "the rows should not be deleted")
+
+	rows := qb.Table("table_test_softdelete").SoftDeletes().MustGet()
+	assert.Equal(t, []interface{}{"John"}, testSoftDeleteNames(rows), "the deleted rows should be trashed")
+
+	row := qb.Table("table_test_softdelete").Where("name", "Ken").MustFirst()
+	assert.NotNil(t, row.Get("deleted_at"), "the deleted_at should be set")
+
+	count = qb.Table("table_test_softdelete").Where("deleted_at", "2021-01-01 00:00:00").MustCount()
+	assert.Equal(t, int64(2), count, "the deleted_at of the trashed rows should not be changed")
+}
+
+func TestSoftDeleteRestore(t *testing.T) {
+	NewTableForSoftDeleteTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_softdelete").SoftDeletes().WhereIn("name", []string{"John", "Lee"}).MustRestore()
+	assert.Equal(t, int64(1), affected, "the affected rows should be 1")
+
+	count := qb.Table("table_test_softdelete").SoftDeletes().MustCount()
+	assert.Equal(t, int64(4), count, "the count should be 4 after restoring")
+
+	_, err := qb.Table("table_test_softdelete").Restore()
+	assert.Equal(t, "the soft deletes of the query is not enabled", err.Error(), "the error message should be returned")
+}
+
+func TestSoftDeleteForceDelete(t *testing.T) {
+	NewTableForSoftDeleteTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_softdelete").SoftDeletes().OnlyTrashed().MustForceDelete()
+	assert.Equal(t, int64(2), affected, "the affected rows should be 2")
+
+	count := qb.Table("table_test_softdelete").MustCount()
+	assert.Equal(t, int64(3), count, "the trashed rows should be deleted")
+
+	affected = qb.Table("table_test_softdelete").SoftDeletes().Where("name", "John").MustForceDelete()
+	assert.Equal(t, int64(1), affected, "the affected rows should be 1")
+
+	count = qb.Table("table_test_softdelete").MustCount()
+	assert.Equal(t, int64(2), count, "the row should be deleted")
+}
+
+func TestSoftDeleteOption(t *testing.T) {
+	NewTableForSoftDeleteTest()
+	conn := *getTestBuilder().Builder().Conn
+	conn.Option = &dbal.Option{SoftDeletes: []string{"table_test_softdelete"}}
+	qb := Use(&conn)
+
+	count := qb.Table("table_test_softdelete").MustCount()
+	assert.Equal(t, int64(3), count, "the tables of the option should use soft deletes")
+
+	affected := qb.Table("table_test_softdelete").Where("name", "John").MustDelete()
+	assert.Equal(t, int64(1), affected, "the affected rows should be 1")
+
+	count = qb.Table("table_test_softdelete").WithTrashed().MustCount()
+	assert.Equal(t, int64(5), count, "the rows should be trashed")
+}
+
+func testSoftDeleteNames(rows []xun.R) []interface{} {
+	names := []interface{}{}
+	for _, row := range rows {
+		names = append(names, row["name"])
+	}
+	return names
+}
+
+// clean the test data
+func TestSoftDeleteClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_softdelete")
+}
+
+func NewTableForSoftDeleteTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_softdelete")
+	builder.MustCreateTable("table_test_softdelete", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("name", 80)
+		table.Integer("vote")
+		table.SoftDeletes()
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_softdelete").MustInsert([]xun.R{
+		{"name": "John", "vote": 5, "deleted_at": nil},
+		{"name": "Lee", "vote": 15, "deleted_at": "2021-01-01 00:00:00"},
+		{"name": "Ken", "vote": 20, "deleted_at": nil},
+		{"name": "Max", "vote": 25, "deleted_at": "2021-01-01 00:00:00"},
+		{"name": "Ben", "vote": 30, "deleted_at": nil},
+	})
+}
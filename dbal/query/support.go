@@ -7,6 +7,7 @@ import (
 	"strings"
 	"unsafe"
 
+	"github.com/blang/semver/v4"
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/utils"
@@ -52,7 +53,7 @@ func (builder *Builder) prepareInsertValues(v interface{}, columns ...interface{
 
 	if _, ok := v.([][]interface{}); len(columns) > 0 && ok {
 		columns = builder.prepareColumns(columns...)
-		return columns, v.([][]interface{})
+		return builder.insertTimestamps(columns, v.([][]interface{}))
 	}
 
 	values := xun.MakeRows(v)
@@ -65,7 +66,15 @@ func (builder *Builder) prepareInsertValues(v interface{}, columns ...interface{
 		}
 		insertValues = append(insertValues, insertValue)
 	}
-	return columns, insertValues
+	return builder.insertTimestamps(columns, insertValues)
+}
+
+// prepareUniqueBy prepare the conflict target columns of the upsert statements, nil means no columns
+func (builder *Builder) prepareUniqueBy(uniqueBy interface{}) []interface{} {
+	if uniqueBy == nil {
+		return []interface{}{}
+	}
+	return utils.Flatten(uniqueBy)
 }
 
 // prepareColumns parepare the select columns
@@ -392,3 +401,26 @@ func (builder *Builder) makeStructValues(dest reflect.Value, fieldMap map[string
 
 	return values, nil
 }
+
+// supported check if the feature is supported by the database.
+// The versions are the minimum versions required by the drivers, an empty version means the driver does not support the feature at all.
+func (builder *Builder) supported(feature string, versions map[string]string) error {
+	version, err := builder.GetVersion()
+	if err != nil {
+		return err
+	}
+
+	min, has := versions[version.Driver]
+	if !has {
+		return nil
+	}
+
+	if min == "" {
+		return fmt.Errorf("%s are not supported by %s", feature, version.Driver)
+	}
+
+	if version.LT(semver.MustParse(min)) {
+		return fmt.Errorf("%s are not supported by %s %s, %s or later is required", feature, version.Driver, version.String(), min)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/yaoapp/xun/utils"
+)
+
+// Timestamps Maintain the created_at and updated_at timestamps of the table, the columns created by the Timestamps of the schema blueprint.
+// The insert statements fill both of them, the update, increment, decrement and upsert statements bump the updated_at,
+// the values given explicitly are not overwritten, e.g.
+//    qb.Table("users").Timestamps().Insert(xun.R{"name": "Ken"})  // insert into "users" ("name", "created_at", "updated_at") values (?, ?, ?)
+//    qb.Table("users").Timestamps().Where("id", 1).Update(xun.R{"name": "Lee"})  // update "users" set "name"=?, "updated_at"=? where "id" = ?
+// The tables listed in the Timestamps of the connection option are enabled by default, "*" enables all the tables.
+func (builder *Builder) Timestamps() Query {
+	builder.Query.Timestamps = "timestamp"
+	return builder
+}
+
+// TimestampsTz Maintain the created_at and updated_at timestamps of the table, the columns created by the TimestampsTz of the schema blueprint.
+// The timestamps are time.Time values with the time zone, instead of the date and time strings in the location of the connection for the Timestamps.
+// The tables listed in the TimestampsTz of the connection option are enabled by default, "*" enables all the tables.
+func (builder *Builder) TimestampsTz() Query {
+	builder.Query.Timestamps = "timestampTz"
+	return builder
+}
+
+// WithoutTimestamps Do not maintain the timestamps in the query, even if they are enabled by the connection option.
+func (builder *Builder) WithoutTimestamps() Query {
+	builder.Query.Timestamps = ""
+	return builder
+}
+
+// freshTimestamp get the current time in the type of the timestamps.
+// The columns without the time zone take the date and time in the location of the connection, the loc of the DSN on MySQL,
+// and the columns with the time zone take the time.Time value, the drivers convert it to the time zone of the columns.
+func (builder *Builder) freshTimestamp() interface{} {
+	now := time.Now()
+	if builder.Query.Timestamps == "timestampTz" {
+		return now
+	}
+	return now.In(builder.Grammar.GetLocation()).Format("2006-01-02 15:04:05")
+}
+
+// insertTimestamps append the created_at and updated_at columns to the insert values if they are not given
+func (builder *Builder) insertTimestamps(columns []interface{}, values [][]interface{}) ([]interface{}, [][]interface{}) {
+	if builder.Query.Timestamps == "" {
+		return columns, values
+	}
+
+	appends := []interface{}{}
+	now := builder.freshTimestamp()
+	for _, column := range []string{"created_at", "updated_at"} {
+		if !builder.hasColumn(columns, column) {
+			appends = append(appends, column)
+		}
+	}
+	if len(appends) == 0 {
+		return columns, values
+	}
+
+	// copy the values, the given rows should not be changed
+	rows := [][]interface{}{}
+	for _, value := range values {
+		row := append([]interface{}{}, value...)
+		for range appends {
+			row = append(row, now)
+		}
+		rows = append(rows, row)
+	}
+	return append(append([]interface{}{}, columns...), appends...), rows
+}
+
+// updateTimestamps set the updated_at column of the update values if it is not given
+func (builder *Builder) updateTimestamps(values map[string]interface{}) map[string]interface{} {
+	if builder.Query.Timestamps == "" {
+		return values
+	}
+	if _, has := values["updated_at"]; has {
+		return values
+	}
+
+	// copy the values, the given map should not be changed
+	res := map[string]interface{}{"updated_at": builder.freshTimestamp()}
+	for key, value := range values {
+		res[key] = value
+	}
+	return res
+}
+
+// upsertTimestamps add the updated_at column to the update of the upsert statements, the conflicting rows skipped are not bumped.
+// The columns updated with the incoming rows get the updated_at of the incoming rows.
+func (builder *Builder) upsertTimestamps(update interface{}) interface{} {
+	if builder.Query.Timestamps == "" || update == nil {
+		return update
+	}
+
+	value := reflect.ValueOf(update)
+	switch value.Kind() {
+	case reflect.Array, reflect.Slice:
+		if value.Len() == 0 {
+			return update
+		}
+		columns := []interface{}{}
+		for i := 0; i < value.Len(); i++ {
+			columns = append(columns, value.Index(i).Interface())
+		}
+		if !builder.hasColumn(columns, "updated_at") {
+			columns = append(columns, "updated_at")
+		}
+		return columns
+
+	case reflect.Map:
+		if value.Len() == 0 {
+			return update
+		}
+		values := map[string]interface{}{}
+		for _, key := range value.MapKeys() {
+			values[fmt.Sprintf("%v", key)] = value.MapIndex(key).Interface()
+		}
+		return builder.updateTimestamps(values)
+	}
+	return update
+}
+
+// hasColumn determine if the columns have the given one
+func (builder *Builder) hasColumn(columns []interface{}, column string) bool {
+	for _, col := range columns {
+		if fmt.Sprintf("%v", col) == column {
+			return true
+		}
+	}
+	return false
+}
+
+// timestampsOf get the type of the timestamps of the table by the connection option, the tables listed take precedence over "*".
+func (builder *Builder) timestampsOf(table string) string {
+	option := builder.Conn.Option
+	switch {
+	case utils.StringHave(option.TimestampsTz, table):
+		return "timestampTz"
+	case utils.StringHave(option.Timestamps, table):
+		return "timestamp"
+	case utils.StringHave(option.TimestampsTz, "*"):
+		return "timestampTz"
+	case utils.StringHave(option.Timestamps, "*"):
+		return "timestamp"
+	}
+	return ""
+}
@@ -0,0 +1,187 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestTimestampsInsert(t *testing.T) {
+	NewTableForTimestampsTest()
+	qb := getTestBuilder()
+	rows := []xun.R{
+		{"email": "ann@yao.run", "name": "Ann", "vote": 20},
+		{"email": "max@yao.run", "name": "Max", "vote": 25},
+	}
+	qb.Table("table_test_timestamps").Timestamps().MustInsert(rows)
+	_, has := rows[0]["created_at"]
+	assert.False(t, has, "the given rows should not be changed")
+
+	count := qb.Table("table_test_timestamps").WhereIn("name", []string{"Ann", "Max"}).WhereNotNull("created_at").WhereNotNull("updated_at").MustCount()
+	assert.Equal(t, int64(2), count, "the timestamps should be filled")
+
+	qb.Table("table_test_timestamps").Timestamps().MustInsert([][]interface{}{
+		{"ben@yao.run", "Ben", 30, "2021-01-01 00:00:00"},
+	}, []string{"email", "name", "vote", "created_at"})
+	count = qb.Table("table_test_timestamps").Where("name", "Ben").Where("created_at", "2021-01-01 00:00:00").WhereNotNull("updated_at").MustCount()
+	assert.Equal(t, int64(1), count, "the timestamps given should not be overwritten")
+
+	id := qb.Table("table_test_timestamps").Timestamps().MustInsertGetID(xun.R{"email": "tom@yao.run", "name": "Tom", "vote": 35})
+	count = qb.Table("table_test_timestamps").Where("id", id).WhereNotNull("updated_at").MustCount()
+	assert.Equal(t, int64(1), count, "the timestamps should be filled by the insert get id")
+
+	qb.Table("table_test_timestamps").MustInsert(xun.R{"email": "joe@yao.run", "name": "Joe", "vote": 40})
+	count = qb.Table("table_test_timestamps").Where("name", "Joe").WhereNull("updated_at").MustCount()
+	assert.Equal(t, int64(1), count, "the timestamps should not be filled if they are not enabled")
+}
+
+func TestTimestampsUpdate(t *testing.T) {
+	NewTableForTimestampsTest()
+	qb := getTestBuilder()
+	values := xun.R{"vote": 6}
+	affected := qb.Table("table_test_timestamps").Timestamps().Where("name", "John").MustUpdate(values)
+	assert.Equal(t, int64(1), affected, "the affected rows should be 1")
+	_, has := values["updated_at"]
+	assert.False(t, has, "the given values should not be changed")
+
+	affected = qb.Table("table_test_timestamps").Timestamps().Where("name", "Lee").MustIncrement("vote", 1)
+	assert.Equal(t, int64(1), affected, "the affected rows should be 1")
+
+	rows := qb.Table("table_test_timestamps").Where("updated_at", "2021-01-01 00:00:00").OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"Ken"}, testTimestampsNames(rows), "the updated_at should be bumped")
+
+	count := qb.Table("table_test_timestamps").Where("created_at", "2021-01-01 00:00:00").MustCount()
+	assert.Equal(t, int64(3), count, "the created_at should not be changed")
+
+	count = qb.Table("table_test_timestamps").WhereIn("name", []string{"John", "Lee"}).Where("updated_at", ">", "2021-01-02 00:00:00").MustCount()
+	assert.Equal(t, int64(2), count, "the updated_at should be the current time")
+
+	qb.Table("table_test_timestamps").Timestamps().Where("name", "Ken").MustUpdate(xun.R{"vote": 21, "updated_at": "2021-02-01 00:00:00"})
+	count = qb.Table("table_test_timestamps").Where("name", "Ken").Where("updated_at", "2021-02-01 00:00:00").MustCount()
+	assert.Equal(t, int64(1), count, "the updated_at given should not be overwritten")
+}
+
+func TestTimestampsUpsert(t *testing.T) {
+	NewTableForTimestampsTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_timestamps").Timestamps().MustUpsert([]xun.R{
+		{"email": "john@yao.run", "name": "John", "vote": 6},
+		{"email": "ben@yao.run", "name": "Ben", "vote": 30},
+	}, []string{"email"}, []string{"vote"})
+
+	qb.Table("table_test_timestamps").Timestamps().MustUpsert([]xun.R{
+		{"email": "lee@yao.run", "name": "Lee", "vote": 16},
+	}, []string{"email"}, map[string]interface{}{"vote": 16})
+
+	rows := qb.Table("table_test_timestamps").Where("updated_at", "2021-01-01 00:00:00").OrderBy("id").MustGet()
+	assert.Equal(t, []interface{}{"Ken"}, testTimestampsNames(rows), "the updated_at of the conflicting rows should be bumped")
+
+	count := qb.Table("table_test_timestamps").Where("created_at", "2021-01-01 00:00:00").MustCount()
+	assert.Equal(t, int64(3), count, "the created_at of the conflicting rows should not be changed")
+
+	count = qb.Table("table_test_timestamps").Where("name", "Ben").WhereNotNull("updated_at").MustCount()
+	assert.Equal(t, int64(1), count, "the timestamps of the inserted rows should be filled")
+}
+
+func TestTimestampsTz(t *testing.T) {
+	NewTableForTimestampsTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_timestamps_tz").TimestampsTz().MustInsert(xun.R{"name": "Ken"})
+	count := qb.Table("table_test_timestamps_tz").Where("name", "Ken").WhereNotNull("created_at").WhereNotNull("updated_at").MustCount()
+	assert.Equal(t, int64(1), count, "the timestamps should be filled")
+
+	affected := qb.Table("table_test_timestamps_tz").TimestampsTz().Where("name", "John").MustUpdate(xun.R{"name": "Lee"})
+	assert.Equal(t, int64(1), affected, "the affected rows should be 1")
+	count = qb.Table("table_test_timestamps_tz").Where("name", "Lee").WhereNotNull("updated_at").MustCount()
+	assert.Equal(t, int64(1), count, "the updated_at should be bumped")
+}
+
+func TestTimestampsFresh(t *testing.T) {
+	qb := getTestBuilder()
+	builder := qb.Table("table_test_timestamps").Timestamps().Builder()
+	now, ok := builder.freshTimestamp().(string)
+	assert.True(t, ok, "the timestamps without the time zone should be the date and time strings")
+	_, err := time.ParseInLocation("2006-01-02 15:04:05", now, builder.Grammar.GetLocation())
+	assert.Nil(t, err, "the timestamps without the time zone should be in the location of the connection")
+
+	builder = qb.Table("table_test_timestamps_tz").TimestampsTz().Builder()
+	_, ok = builder.freshTimestamp().(time.Time)
+	assert.True(t, ok, "the timestamps with the time zone should be the time values")
+
+	if unit.DriverIs("sqlite3") {
+		NewTableForTimestampsTest()
+		qb.Table("table_test_timestamps").Timestamps().MustInsert(xun.R{"email": "ben@yao.run", "name": "Ben", "vote": 30})
+		qb.Table("table_test_timestamps_tz").TimestampsTz().MustInsert(xun.R{"name": "Ben"})
+		row := qb.Table("table_test_timestamps").Where("name", "Ben").SelectRaw("cast(created_at as text) as created_at").MustFirst()
+		assert.Regexp(t, `^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}$`, row["created_at"], "the local date and time should be written")
+		row = qb.Table("table_test_timestamps_tz").Where("name", "Ben").SelectRaw("cast(created_at as text) as created_at").MustFirst()
+		assert.Regexp(t, `[+-]\d{2}:\d{2}$|Z$`, row["created_at"], "the time zone should be written")
+	}
+}
+
+func TestTimestampsOption(t *testing.T) {
+	NewTableForTimestampsTest()
+	conn := *getTestBuilder().Builder().Conn
+	conn.Option = &dbal.Option{Timestamps: []string{"*"}, TimestampsTz: []string{"table_test_timestamps_tz"}}
+	qb := Use(&conn)
+
+	assert.Equal(t, "timestamp", qb.Table("table_test_timestamps").Builder().Query.Timestamps, "the timestamps of all the tables should be enabled")
+	assert.Equal(t, "timestampTz", qb.Table("table_test_timestamps_tz").Builder().Query.Timestamps, "the tables listed should take precedence")
+
+	qb.Table("table_test_timestamps").MustInsert(xun.R{"email": "ben@yao.run", "name": "Ben", "vote": 30})
+	count := qb.Table("table_test_timestamps").Where("name", "Ben").WhereNotNull("updated_at").MustCount()
+	assert.Equal(t, int64(1), count, "the timestamps should be filled")
+
+	qb.Table("table_test_timestamps").WithoutTimestamps().Where("name", "John").MustUpdate(xun.R{"vote": 6})
+	count = qb.Table("table_test_timestamps").Where("name", "John").Where("updated_at", "2021-01-01 00:00:00").MustCount()
+	assert.Equal(t, int64(1), count, "the updated_at should not be bumped without timestamps")
+}
+
+func testTimestampsNames(rows []xun.R) []interface{} {
+	names := []interface{}{}
+	for _, row := range rows {
+		names = append(names, row["name"])
+	}
+	return names
+}
+
+// clean the test data
+func TestTimestampsClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_timestamps")
+	builder.DropTableIfExists("table_test_timestamps_tz")
+}
+
+func NewTableForTimestampsTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_timestamps")
+	builder.DropTableIfExists("table_test_timestamps_tz")
+	builder.MustCreateTable("table_test_timestamps", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.String("name", 80)
+		table.Integer("vote")
+		table.Timestamps()
+	})
+	builder.MustCreateTable("table_test_timestamps_tz", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("name", 80)
+		table.TimestampsTz()
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_timestamps").MustInsert([]xun.R{
+		{"email": "john@yao.run", "name": "John", "vote": 5, "created_at": "2021-01-01 00:00:00", "updated_at": "2021-01-01 00:00:00"},
+		{"email": "lee@yao.run", "name": "Lee", "vote": 15, "created_at": "2021-01-01 00:00:00", "updated_at": "2021-01-01 00:00:00"},
+		{"email": "ken@yao.run", "name": "Ken", "vote": 20, "created_at": "2021-01-01 00:00:00", "updated_at": "2021-01-01 00:00:00"},
+	})
+	qb.Table("table_test_timestamps_tz").MustInsert([]xun.R{
+		{"name": "John"},
+	})
+}
@@ -0,0 +1,147 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun/utils"
+)
+
+// Begin Start a new database transaction, returns a new builder bound to the transaction.
+// The builders cloned from the returned builder share the same transaction.
+// If the builder is already in a transaction, a savepoint is created instead,
+// so that rolling back the nested transaction only undoes the nested work.
+func (builder *Builder) Begin() (Query, error) {
+	if builder.Tx != nil {
+		return builder.beginSavepoint()
+	}
+
+	if builder.Conn.Write == nil {
+		return nil, fmt.Errorf("the write connection is nil")
+	}
+
+	tx, err := builder.Conn.Write.BeginTxx(builder.Context(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return builder.WithTx(tx), nil
+}
+
+// WithTx Get a new builder bound to the given transaction, e.g. the transaction started by the schema builder,
+// so that the queries and the schema changes are committed or rolled back together.
+// The transaction should be committed or rolled back by the one who started it.
+func (builder *Builder) WithTx(tx *sqlx.Tx) Query {
+	new := builder.new()
+	new.Tx = tx
+	new.TxLevel = 1
+	new.Grammar = builder.Grammar.WithTx(tx)
+	new.Query.UseWriteConnection = true
+	return new
+}
+
+// MustBegin Start a new database transaction, returns a new builder bound to the transaction.
+func (builder *Builder) MustBegin() Query {
+	tx, err := builder.Begin()
+	utils.PanicIF(err)
+	return tx
+}
+
+// Commit Commit the active database transaction, or release the savepoint of the nested transaction.
+func (builder *Builder) Commit() error {
+	if builder.Tx == nil {
+		return fmt.Errorf("the builder is not in a transaction")
+	}
+
+	if builder.TxLevel > 1 {
+		return builder.execSavepoint(builder.Grammar.CompileReleaseSavepoint(builder.savepoint()))
+	}
+	return builder.Tx.Commit()
+}
+
+// MustCommit Commit the active database transaction, or release the savepoint of the nested transaction.
+func (builder *Builder) MustCommit() {
+	err := builder.Commit()
+	utils.PanicIF(err)
+}
+
+// Rollback Rollback the active database transaction, or rollback to the savepoint of the nested transaction.
+func (builder *Builder) Rollback() error {
+	if builder.Tx == nil {
+		return fmt.Errorf("the builder is not in a transaction")
+	}
+
+	if builder.TxLevel > 1 {
+		return builder.execSavepoint(builder.Grammar.CompileRollbackToSavepoint(builder.savepoint()))
+	}
+	return builder.Tx.Rollback()
+}
+
+// MustRollback Rollback the active database transaction, or rollback to the savepoint of the nested transaction.
+func (builder *Builder) MustRollback() {
+	err := builder.Rollback()
+	utils.PanicIF(err)
+}
+
+// Transaction Execute a Closure within a transaction.
+// Calling Transaction on a transactional builder creates a nested transaction using savepoint.
+// The transaction is committed if the callback returns nil, otherwise it is rolled back.
+// If the callback panics, the transaction is rolled back and the panic is re-raised.
+func (builder *Builder) Transaction(callback func(tx Query) error) error {
+	tx, err := builder.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	err = callback(tx)
+	if err != nil {
+		if errRollback := tx.Rollback(); errRollback != nil {
+			return fmt.Errorf("%s (rollback failed: %s)", err, errRollback)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MustTransaction Execute a Closure within a transaction.
+func (builder *Builder) MustTransaction(callback func(tx Query) error) {
+	err := builder.Transaction(callback)
+	utils.PanicIF(err)
+}
+
+// InTransaction Determine if the builder is in a transaction.
+func (builder *Builder) InTransaction() bool {
+	return builder.Tx != nil
+}
+
+// beginSavepoint Create a savepoint within the active transaction, returns a new builder bound to the savepoint.
+func (builder *Builder) beginSavepoint() (Query, error) {
+	new := builder.new()
+	new.TxLevel = builder.TxLevel + 1
+	new.Query.UseWriteConnection = true
+	err := new.execSavepoint(new.Grammar.CompileSavepoint(new.savepoint()))
+	if err != nil {
+		return nil, err
+	}
+	return new, nil
+}
+
+// execSavepoint Execute the savepoint statement on the active transaction.
+func (builder *Builder) execSavepoint(sql string) error {
+	defer log.Debug(sql)
+	_, err := builder.Tx.ExecContext(builder.Context(), sql)
+	return err
+}
+
+// savepoint Get the savepoint name of the current transaction level.
+func (builder *Builder) savepoint() string {
+	return fmt.Sprintf("trans%d", builder.TxLevel)
+}
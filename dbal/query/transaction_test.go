@@ -0,0 +1,144 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestTransactionMustTransactionCommit(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	qb.MustTransaction(func(tx Query) error {
+		assert.True(t, tx.InTransaction(), "The builder should be in a transaction")
+		id := tx.Table("table_test_transaction").MustInsertGetID(xun.R{"email": "max@yao.run", "vote": 1})
+		tx.Table("table_test_transaction").Where("id", id).MustUpdate(xun.R{"vote": 10})
+		tx.Table("table_test_transaction").Where("id", id).MustDecrement("vote", 2)
+		assert.Equal(t, int64(3), tx.Table("table_test_transaction").MustCount(), "The rows count should be 3 in the transaction")
+		return nil
+	})
+
+	assert.False(t, qb.InTransaction(), "The builder should not be in a transaction")
+	assert.Equal(t, int64(3), qb.Table("table_test_transaction").MustCount(), "The rows count should be 3 after commit")
+	row := qb.Table("table_test_transaction").Where("email", "max@yao.run").MustFirst()
+	assert.Equal(t, int64(8), row.Get("vote"), "The vote should be 8 after commit")
+}
+
+func TestTransactionMustTransactionRollback(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	err := qb.Transaction(func(tx Query) error {
+		tx.Table("table_test_transaction").MustInsert(xun.R{"email": "max@yao.run", "vote": 1})
+		tx.Clone().Table("table_test_transaction").Where("email", "john@yao.run").MustDelete()
+		return fmt.Errorf("something wrong")
+	})
+	assert.Equal(t, "something wrong", err.Error(), "The error should be returned")
+	assert.Equal(t, int64(2), qb.Table("table_test_transaction").MustCount(), "The rows count should be 2 after rollback")
+}
+
+func TestTransactionMustTransactionPanic(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	assert.Panics(t, func() {
+		qb.MustTransaction(func(tx Query) error {
+			tx.Table("table_test_transaction").MustInsert(xun.R{"email": "max@yao.run", "vote": 1})
+			tx.Table("table_test_transaction_not_exists").MustInsert(xun.R{"email": "max@yao.run", "vote": 1})
+			return nil
+		})
+	})
+	assert.Equal(t, int64(2), qb.Table("table_test_transaction").MustCount(), "The rows count should be 2 after rollback")
+}
+
+func TestTransactionMustBeginCommit(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	tx := qb.MustBegin()
+	tx.Table("table_test_transaction").MustInsert(xun.R{"email": "max@yao.run", "vote": 1})
+	tx.MustCommit()
+	assert.Equal(t, int64(3), qb.Table("table_test_transaction").MustCount(), "The rows count should be 3 after commit")
+	assert.Panics(t, func() { tx.MustRollback() })
+}
+
+func TestTransactionMustBeginRollback(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	tx := qb.MustBegin()
+	tx.Table("table_test_transaction").MustInsert(xun.R{"email": "max@yao.run", "vote": 1})
+	tx.MustRollback()
+	assert.Equal(t, int64(2), qb.Table("table_test_transaction").MustCount(), "The rows count should be 2 after rollback")
+}
+
+func TestTransactionMustTransactionNested(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	qb.MustTransaction(func(tx Query) error {
+		tx.Table("table_test_transaction").MustInsert(xun.R{"email": "max@yao.run", "vote": 1})
+
+		err := tx.Transaction(func(inner Query) error {
+			inner.Table("table_test_transaction").MustInsert(xun.R{"email": "ken@yao.run", "vote": 2})
+			assert.Equal(t, int64(4), inner.Table("table_test_transaction").MustCount(), "The rows count should be 4 in the nested transaction")
+			return fmt.Errorf("inner failure")
+		})
+		assert.Equal(t, "inner failure", err.Error(), "The error of the nested transaction should be returned")
+		assert.Equal(t, int64(3), tx.Table("table_test_transaction").MustCount(), "The rows count should be 3 after the nested transaction rollback")
+
+		tx.MustTransaction(func(inner Query) error {
+			inner.Table("table_test_transaction").MustInsert(xun.R{"email": "ben@yao.run", "vote": 3})
+			return nil
+		})
+		return nil
+	})
+
+	emails := []string{}
+	for _, row := range qb.Table("table_test_transaction").OrderBy("id").MustGet() {
+		emails = append(emails, row.Get("email").(string))
+	}
+	assert.Equal(t, []string{"john@yao.run", "lee@yao.run", "max@yao.run", "ben@yao.run"}, emails, "The nested transaction should be rolled back only")
+}
+
+func TestTransactionMustTransactionNestedRollback(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	err := qb.Transaction(func(tx Query) error {
+		tx.MustTransaction(func(inner Query) error {
+			inner.Table("table_test_transaction").MustInsert(xun.R{"email": "ben@yao.run", "vote": 3})
+			return nil
+		})
+		return fmt.Errorf("outer failure")
+	})
+	assert.Equal(t, "outer failure", err.Error(), "The error should be returned")
+	assert.Equal(t, int64(2), qb.Table("table_test_transaction").MustCount(), "The rows count should be 2 after the outer transaction rollback")
+}
+
+func TestTransactionCommitError(t *testing.T) {
+	qb := getTestBuilder()
+	assert.Panics(t, func() { qb.MustCommit() })
+	assert.Panics(t, func() { qb.MustRollback() })
+}
+
+// clean the test data
+func TestTransactionClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_transaction")
+}
+
+func NewTableForTransactionTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_transaction")
+	builder.MustCreateTable("table_test_transaction", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.Integer("vote")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_transaction").Insert([]xun.R{
+		{"email": "john@yao.run", "vote": 10},
+		{"email": "lee@yao.run", "vote": 5},
+	})
+}
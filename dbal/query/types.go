@@ -1,6 +1,11 @@
 package query
 
 import (
+	"context"
+	"database/sql"
+	"reflect"
+	"sync"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun/dbal"
 )
@@ -13,6 +18,9 @@ type Builder struct {
 	Database string
 	Schema   string
 	Grammar  dbal.Grammar
+	Tx       *sqlx.Tx
+	TxLevel  int
+	Ctx      context.Context
 }
 
 // Connection DB Connection
@@ -22,4 +30,39 @@ type Connection struct {
 	Read        *sqlx.DB
 	ReadConfig  *dbal.Config
 	Option      *dbal.Option
+	Version     *dbal.Version
+	Scopes      *Scopes // The scopes registered on the tables, shared by the builders of the connection
+	versionLock *sync.Mutex
+}
+
+// Scope the query scope, a named closure constraining the query, e.g.
+//    func(qb query.Query, args ...interface{}) { qb.Where("status", "active") }
+type Scope func(qb Query, args ...interface{})
+
+// Scopes the registered scopes of the tables, the scopes registered on the "*" table are available to all the tables.
+type Scopes struct {
+	sync.RWMutex
+	local  map[string]map[string]Scope
+	global map[string][]namedScope
+}
+
+// namedScope the global scope registered on a table
+type namedScope struct {
+	name  string
+	scope Scope
+}
+
+// Cursor the iterator of the query results, it holds the rows open until it is closed
+type Cursor struct {
+	rows     *sql.Rows
+	columns  []string
+	fieldMap map[reflect.Type]map[string]reflect.StructField
+	builder  *Builder
+}
+
+// pageCursor the position of the cursor paginator, it is encoded as an opaque string
+type pageCursor struct {
+	Next   bool          `json:"next"`
+	Values []interface{} `json:"values"`
+	Times  []int         `json:"times,omitempty"`
 }
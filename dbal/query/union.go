@@ -24,11 +24,12 @@ func (builder *Builder) Union(query interface{}, all ...bool) Query {
 	}
 
 	if qb != nil {
+		qb = qb.withScopes()
 		builder.Query.Unions = append(builder.Query.Unions, dbal.Union{
 			Query: qb.Query,
 			All:   isUnionAll,
 		})
-		builder.Query.AddBinding("union", qb.GetBindings())
+		builder.Query.AddBinding("union", qb.Query.GetBindings())
 	}
 	return builder
 
@@ -12,17 +12,18 @@ import (
 // Update Update records in the database.
 func (builder *Builder) Update(v interface{}) (int64, error) {
 
-	values := xun.MakeR(v).ToMap()
-	sql, bindings := builder.Grammar.CompileUpdate(builder.Query, values)
+	values := builder.updateTimestamps(xun.MakeR(v).ToMap())
+	sql, bindings := builder.Grammar.CompileUpdate(builder.withScopes().Query, values)
 	defer log.With(log.F{"bindings": bindings}).Debug(sql)
 
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
+	builder.UseWrite()
+	stmt, err := builder.executor().PrepareContext(builder.Context(), sql)
 	if err != nil {
 		return 0, err
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(bindings...)
+	res, err := stmt.ExecContext(builder.Context(), bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -76,19 +77,26 @@ func (builder *Builder) MustUpdateOrInsert(attributes interface{}, values ...int
 }
 
 // Upsert new records or update the existing ones.
+// The update could be the columns updated with the values of the incoming rows, or the map of the columns and the values,
+// use the Excluded method to reference the incoming rows in the values. If the update is nil or empty, the conflicting rows are skipped, e.g.
+//    qb.Table("users").Upsert(rows, "email", []string{"name", "version"})
+//    qb.Table("users").Upsert(rows, "email", map[string]interface{}{"vote": dbal.Raw("vote + 1")})
+//    qb.Table("users").Upsert(rows, "email", nil)
+// MySQL counts the skipped rows as affected if the clientFoundRows parameter of the DSN is true.
 func (builder *Builder) Upsert(v interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) (int64, error) {
 
 	columns, values := builder.prepareInsertValues(v, columns...)
-	sql, bindings := builder.Grammar.CompileUpsert(builder.Query, columns, values, utils.Flatten(uniqueBy), update)
+	sql, bindings := builder.Grammar.CompileUpsert(builder.Query, columns, values, builder.prepareUniqueBy(uniqueBy), builder.upsertTimestamps(update))
 	defer log.With(log.F{"bindings": bindings}).Debug(sql)
 
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
+	builder.UseWrite()
+	stmt, err := builder.executor().PrepareContext(builder.Context(), sql)
 	if err != nil {
 		return 0, err
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(bindings...)
+	res, err := stmt.ExecContext(builder.Context(), bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -103,6 +111,34 @@ func (builder *Builder) MustUpsert(values interface{}, uniqueBy interface{}, upd
 	return affected
 }
 
+// UpsertConstraint Set the constraint name as the conflict target of the upsert statements, the uniqueBy columns are ignored (Postgres only).
+// MySQL takes any unique key as the conflict target, SQLite does not support it.
+func (builder *Builder) UpsertConstraint(name string) Query {
+	builder.Query.Conflict.Constraint = name
+	return builder
+}
+
+// UpsertWhere Add a constraint to the update branch of the upsert statements, the conflicting rows not matching the constraints are not updated, e.g.
+//    qb.Table("users").
+//        UpsertWhere("users.version", "<", qb.Excluded("version")).
+//        Upsert(rows, "email", []string{"name", "version"})
+// The arguments are the same as the Where method, qualify the columns with the table name, Postgres takes the unqualified columns as ambiguous.
+func (builder *Builder) UpsertWhere(column interface{}, args ...interface{}) Query {
+	where := builder.forNestedWhere()
+	if builder.Query.Conflict.Where != nil {
+		where.Query = builder.Query.Conflict.Where
+	}
+	where.Where(column, args...)
+	builder.Query.Conflict.Where = where.Query
+	return builder
+}
+
+// Excluded Get the expression referencing the column of the incoming row in the upsert statements, e.g.
+// excluded."version" (Postgres and SQLite), values(`version`) (MySQL)
+func (builder *Builder) Excluded(column string) dbal.Expression {
+	return dbal.Raw(builder.Grammar.CompileExcluded(column))
+}
+
 // Increment Increment a column's value by a given amount.
 func (builder *Builder) Increment(column interface{}, amount interface{}, extra ...interface{}) (int64, error) {
 	if !utils.IsNumeric(amount) {
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -89,6 +90,105 @@ func TestUpdateMustUpsertUpdateRaw(t *testing.T) {
 	}
 }
 
+func TestUpdateMustUpsertDoNothing(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_update").MustUpsert([]xun.R{
+		{"email": "max@yao.run", "name": "Max", "vote": 19, "score": 86.32, "score_grade": 99.27, "status": "DONE"},
+		{"email": "john@yao.run", "name": "John Lee", "vote": 20, "score": 96.32, "score_grade": 99.27, "status": "DONE"},
+	}, "email", nil)
+
+	rows := qb.Table("table_test_update").OrderBy("id").MustGet()
+	assert.Equal(t, 5, len(rows), "The rows count should be 5")
+	if len(rows) == 5 {
+		assert.Equal(t, "John", rows[0].GetString("name"), "The name of the conflicting row should not be updated")
+		assert.Equal(t, 10, rows[0].GetInt("vote"), "The vote of the conflicting row should not be updated")
+		assert.Equal(t, "Max", rows[4].GetString("name"), "The new row should be inserted")
+	}
+}
+
+func TestUpdateMustUpsertWhere(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+
+	// last-writer-wins, the vote is the version of the row
+	qb.Table("table_test_update").
+		UpsertWhere("table_test_update.vote", "<", qb.Excluded("vote")).
+		MustUpsert([]xun.R{
+			{"email": "john@yao.run", "name": "John Lee", "vote": 5, "score": 96.32, "score_grade": 99.27, "status": "DONE"},
+			{"email": "ken@yao.run", "name": "Ken Lee", "vote": 200, "score": 99.27, "score_grade": 99.27, "status": "DONE"},
+			{"email": "max@yao.run", "name": "Max", "vote": 19, "score": 86.32, "score_grade": 99.27, "status": "DONE"},
+		}, []string{"email"}, []string{"name", "vote"})
+
+	rows := qb.Table("table_test_update").OrderBy("id").MustGet()
+	assert.Equal(t, 5, len(rows), "The rows count should be 5")
+	if len(rows) == 5 {
+		assert.Equal(t, "John", rows[0].GetString("name"), "The name of the newer row should not be updated")
+		assert.Equal(t, 10, rows[0].GetInt("vote"), "The vote of the newer row should not be updated")
+		assert.Equal(t, "Ken Lee", rows[2].GetString("name"), "The name of the older row should be updated")
+		assert.Equal(t, 200, rows[2].GetInt("vote"), "The vote of the older row should be updated")
+		assert.Equal(t, "Max", rows[4].GetString("name"), "The new row should be inserted")
+	}
+}
+
+func TestUpdateMustUpsertWhereBindings(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_update").
+		UpsertWhere("table_test_update.status", "<>", "DONE").
+		UpsertWhere(func(qb Query) {
+			qb.Where("table_test_update.vote", ">", 6).OrWhere("table_test_update.name", "Lee")
+		}).
+		MustUpsert([]xun.R{
+			{"email": "john@yao.run", "name": "John", "vote": 1, "score": 96.32, "score_grade": 99.27, "status": "DONE"},
+			{"email": "lee@yao.run", "name": "Lee", "vote": 2, "score": 64.56, "score_grade": 99.27, "status": "DONE"},
+			{"email": "ken@yao.run", "name": "Ken", "vote": 3, "score": 99.27, "score_grade": 99.27, "status": "DONE"},
+		}, "email", map[string]interface{}{"score": 0, "status": "PENDING"})
+
+	rows := qb.Table("table_test_update").OrderBy("id").MustGet()
+	assert.Equal(t, 4, len(rows), "The rows count should be 4")
+	if len(rows) == 4 {
+		assert.Equal(t, 0.0, rows[0].GetFloat("score", 2), "The score of John should be updated")
+		assert.Equal(t, "PENDING", rows[0].GetString("status"), "The status of John should be updated")
+		assert.Equal(t, 0.0, rows[1].GetFloat("score", 2), "The score of Lee should be updated")
+		assert.Equal(t, 99.27, rows[2].GetFloat("score", 2), "The score of Ken should not be updated")
+		assert.Equal(t, "DONE", rows[2].GetString("status"), "The status of Ken should not be updated")
+	}
+}
+
+func TestUpdateMustUpsertExcluded(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	vote := "`table_test_update`.`vote`"
+	if unit.DriverIs("postgres") {
+		vote = `"table_test_update"."vote"`
+	}
+	qb.Table("table_test_update").MustUpsert([]xun.R{
+		{"email": "john@yao.run", "name": "John", "vote": 5, "score": 96.32, "score_grade": 99.27, "status": "DONE"},
+	}, "email", map[string]interface{}{
+		"vote": dbal.Raw(fmt.Sprintf("%s + %s", vote, qb.Excluded("vote").GetValue())),
+	})
+
+	row := qb.Table("table_test_update").Where("email", "john@yao.run").MustFirst()
+	assert.Equal(t, 15, row.GetInt("vote"), "The vote should be the sum of the existing and the incoming ones")
+}
+
+func TestUpdateMustUpsertConstraint(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	rows := []xun.R{{"id": 1, "email": "john@yao.run", "name": "John Lee", "vote": 5, "score": 96.32, "score_grade": 99.27, "status": "DONE"}}
+	if unit.DriverIs("sqlite3") {
+		assert.PanicsWithError(t, "This database engine does not support the conflict target by constraint name", func() {
+			qb.Table("table_test_update").UpsertConstraint("table_test_update_pkey").MustUpsert(rows, nil, []string{"name"})
+		})
+		return
+	}
+
+	qb.Table("table_test_update").UpsertConstraint("table_test_update_pkey").MustUpsert(rows, nil, []string{"name"})
+	row := qb.Table("table_test_update").Where("id", 1).MustFirst()
+	assert.Equal(t, "John Lee", row.GetString("name"), "The name of the conflicting row should be updated")
+}
+
 func TestUpdateMustUpdate(t *testing.T) {
 	NewTableForUpdateTest()
 	qb := getTestBuilder()
@@ -135,6 +235,118 @@ func TestUpdateMustUpdateWithJoin(t *testing.T) {
 	assert.Equal(t, int64(2), affected, "The affected rows should be 2")
 }
 
+func TestUpdateMustUpdateWithJoinTable(t *testing.T) {
+	NewTableForUpdateJoinTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_update").
+		Join("table_test_update_users", "table_test_update_users.email", "=", "table_test_update.email").
+		Where("table_test_update_users.banned", true).
+		MustUpdate(xun.R{"vote": 0})
+	assert.Equal(t, int64(2), affected, "The affected rows should be 2")
+
+	names := []interface{}{}
+	for _, row := range qb.Table("table_test_update").Where("vote", 0).OrderBy("name").MustGet() {
+		names = append(names, row["name"])
+	}
+	assert.Equal(t, []interface{}{"Ken", "Lee"}, names, "the votes of the banned users should be 0")
+}
+
+func TestUpdateMustUpdateWithJoinColumn(t *testing.T) {
+	if unit.DriverIs("sqlite3") {
+		return // the update values could not refer to the columns of the joined tables
+	}
+
+	NewTableForUpdateJoinTest()
+	qb := getTestBuilder()
+	affected := qb.From("table_test_update as o").
+		Join("table_test_update_users as u", "u.email", "=", "o.email").
+		Where("u.banned", false).
+		MustUpdate(xun.R{"vote": dbal.Raw("u.credits")})
+	assert.Equal(t, int64(1), affected, "The affected rows should be 1")
+
+	row := qb.Table("table_test_update").Where("email", "john@yao.run").MustFirst()
+	assert.Equal(t, int64(300), row.Get("vote"), "the vote should be the credits of the joined user")
+}
+
+func TestUpdateMustUpdateWithLeftJoin(t *testing.T) {
+	NewTableForUpdateJoinTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_update").
+		LeftJoin("table_test_update_users", "table_test_update_users.email", "=", "table_test_update.email").
+		WhereNull("table_test_update_users.id").
+		MustUpdate(xun.R{"vote": 0})
+	assert.Equal(t, int64(1), affected, "The affected rows should be 1")
+
+	names := []interface{}{}
+	for _, row := range qb.Table("table_test_update").Where("vote", 0).MustGet() {
+		names = append(names, row["name"])
+	}
+	assert.Equal(t, []interface{}{"Ben"}, names, "the vote of the row without user should be 0")
+}
+
+func TestUpdateMustUpdateJSON(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	affected := qb.From("table_test_update").
+		Where("id", 1).
+		MustUpdate(xun.R{
+			"vote":                  20,
+			"options->enabled":      true,
+			"options->theme":        "dark",
+			"options->languages[0]": "fr",
+		})
+	assert.Equal(t, int64(1), affected, "The affected rows should be 1")
+
+	row := qb.Table("table_test_update").Where("id", 1).MustFirst()
+	options := map[string]interface{}{}
+	err := json.Unmarshal([]byte(fmt.Sprintf("%s", row["options"])), &options)
+	assert.Nil(t, err, "the options should be a JSON document")
+	assert.Equal(t, true, options["enabled"], "the enabled option should be true")
+	assert.Equal(t, "dark", options["theme"], "the theme option should be dark")
+	assert.Equal(t, []interface{}{"fr"}, options["languages"], "the languages option should be [fr]")
+	assert.Equal(t, int64(20), row.Get("vote"), "the vote should be 20")
+
+	row = qb.Table("table_test_update").Where("id", 2).Select("options->>theme as theme").MustFirst()
+	assert.Equal(t, "light", fmt.Sprintf("%s", row["theme"]), "the other rows should not be updated")
+}
+
+func TestUpdateMustUpdateJSONNull(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	qb.From("table_test_update").Where("id", 1).MustUpdate(xun.R{"options": dbal.Raw("NULL")})
+
+	affected := qb.From("table_test_update").
+		Where("id", 1).
+		MustUpdate(xun.R{"options->enabled": true, "options->theme": "dark"})
+	assert.Equal(t, int64(1), affected, "The affected rows should be 1")
+
+	row := qb.Table("table_test_update").Where("id", 1).MustFirst()
+	assert.NotNil(t, row["options"], "the NULL options should be updated as an empty object")
+	options := map[string]interface{}{}
+	err := json.Unmarshal([]byte(fmt.Sprintf("%s", row["options"])), &options)
+	assert.Nil(t, err, "the options should be a JSON document")
+	assert.Equal(t, map[string]interface{}{"enabled": true, "theme": "dark"}, options, "the keys should be set on the empty object")
+}
+
+func TestUpdateMustUpdateJSONWithLimit(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	affected := qb.From("table_test_update").
+		Where("id", ">", 2).
+		OrderBy("id").
+		Limit(1).
+		MustUpdate(xun.R{"options->theme": "dark"})
+	assert.Equal(t, int64(1), affected, "The affected rows should be 1")
+
+	rows := qb.Table("table_test_update").
+		WhereJSONContains("options->theme", "dark").
+		MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should be have 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, int64(3), rows[0]["id"].(int64), "the id of the row should be 3")
+	}
+}
+
 func TestUpdateMustIncrement(t *testing.T) {
 	NewTableForUpdateTest()
 	qb := getTestBuilder()
@@ -288,6 +500,7 @@ func TestUpdateMustUpdateOrInsertUpdateScore(t *testing.T) {
 func TestUpdateClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
 	builder.DropTableIfExists("table_test_update")
+	builder.DropTableIfExists("table_test_update_users")
 }
 
 func NewTableForUpdateTest() {
@@ -304,13 +517,34 @@ func NewTableForUpdateTest() {
 		table.Enum("status", []string{"WAITING", "PENDING", "DONE"}).SetDefault("WAITING")
 		table.Timestamps()
 		table.SoftDeletes()
+		table.JSON("options").Null()
 	})
 
 	qb := getTestBuilder()
 	qb.Table("table_test_update").Insert([]xun.R{
-		{"email": "john@yao.run", "name": "John", "vote": 10, "score": 96.32, "score_grade": 99.27, "status": "WAITING", "created_at": "2021-03-25 00:21:16"},
-		{"email": "lee@yao.run", "name": "Lee", "vote": 5, "score": 64.56, "score_grade": 99.27, "status": "PENDING", "created_at": "2021-03-25 08:30:15"},
-		{"email": "ken@yao.run", "name": "Ken", "vote": 125, "score": 99.27, "score_grade": 99.27, "status": "DONE", "created_at": "2021-03-25 09:40:23"},
-		{"email": "ben@yao.run", "name": "Ben", "vote": 6, "score": 48.12, "score_grade": 99.27, "status": "DONE", "created_at": "2021-03-25 18:15:29"},
+		{"email": "john@yao.run", "name": "John", "vote": 10, "score": 96.32, "score_grade": 99.27, "status": "WAITING", "created_at": "2021-03-25 00:21:16", "options": `{"enabled":false,"theme":"light","languages":["en"]}`},
+		{"email": "lee@yao.run", "name": "Lee", "vote": 5, "score": 64.56, "score_grade": 99.27, "status": "PENDING", "created_at": "2021-03-25 08:30:15", "options": `{"enabled":false,"theme":"light","languages":["en"]}`},
+		{"email": "ken@yao.run", "name": "Ken", "vote": 125, "score": 99.27, "score_grade": 99.27, "status": "DONE", "created_at": "2021-03-25 09:40:23", "options": `{"enabled":false,"theme":"light","languages":["en"]}`},
+		{"email": "ben@yao.run", "name": "Ben", "vote": 6, "score": 48.12, "score_grade": 99.27, "status": "DONE", "created_at": "2021-03-25 18:15:29", "options": `{"enabled":false,"theme":"light","languages":["en"]}`},
+	})
+}
+
+func NewTableForUpdateJoinTest() {
+	NewTableForUpdateTest()
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_update_users")
+	builder.MustCreateTable("table_test_update_users", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.Boolean("banned").SetDefault(false)
+		table.Integer("credits").SetDefault(0)
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_update_users").Insert([]xun.R{
+		{"email": "john@yao.run", "banned": false, "credits": 300},
+		{"email": "lee@yao.run", "banned": true, "credits": 100},
+		{"email": "ken@yao.run", "banned": true, "credits": 200},
 	})
 }
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 
@@ -241,9 +242,9 @@ func (builder *Builder) createSub(subquery interface{}) (interface{}, []interfac
 func (builder *Builder) makeSub(subquery interface{}) (interface{}, []interface{}, int) {
 	switch subquery.(type) {
 	case *Builder:
-		qb := builder.prependDatabaseNameIfCrossDatabaseQuery(subquery.(*Builder))
-		offset := len(builder.GetBindings())
-		bindings := qb.GetBindings()
+		qb := builder.prependDatabaseNameIfCrossDatabaseQuery(subquery.(*Builder)).withScopes()
+		offset := len(builder.Query.GetBindings())
+		bindings := qb.Query.GetBindings()
 		whereOffset := offset + len(utils.Flatten(bindings))
 		qb.Query.BindingOffset = offset
 		return qb.Query, bindings, whereOffset
@@ -268,6 +269,7 @@ func (builder *Builder) prependDatabaseNameIfCrossDatabaseQuery(subquery *Builde
 func (builder *Builder) whereSub(column string, operator string, callback func(qb Query), boolean string) *Builder {
 	new := builder.forSubQuery()
 	callback(new)
+	new = new.withScopes()
 	builder.Query.Wheres = append(builder.Query.Wheres, dbal.Where{
 		Type:     "sub",
 		Column:   column,
@@ -515,13 +517,14 @@ func (builder *Builder) OrWhereNotExists(closure func(qb Query)) Query {
 func (builder *Builder) whereExists(closure func(qb Query), boolean string, not bool) Query {
 	new := builder.forSubQuery()
 	closure(new)
+	new = new.withScopes()
 	builder.Query.Wheres = append(builder.Query.Wheres, dbal.Where{
 		Type:    "exists",
 		Not:     not,
 		Boolean: boolean,
 		Query:   new.Query,
 	})
-	builder.Query.AddBinding("where", new.GetBindings())
+	builder.Query.AddBinding("where", new.Query.GetBindings())
 	return builder
 }
 
@@ -645,25 +648,98 @@ func (builder *Builder) Unless(value bool, callback func(qb Query, value bool),
 }
 
 // WhereJSONContains Add a "where JSON contains" clause to the query.
-func (builder *Builder) WhereJSONContains() {
+// The value is encoded as JSON, e.g. WhereJSONContains("options->languages", []string{"en", "de"})
+func (builder *Builder) WhereJSONContains(column interface{}, value interface{}) Query {
+	return builder.whereJSONContains(column, value, "and", false)
 }
 
 // OrWhereJSONContains Add an "or where JSON contains" clause to the query.
-func (builder *Builder) OrWhereJSONContains() {
+func (builder *Builder) OrWhereJSONContains(column interface{}, value interface{}) Query {
+	return builder.whereJSONContains(column, value, "or", false)
 }
 
 // WhereJSONDoesntContain Add a "where JSON not contains" clause to the query.
-func (builder *Builder) WhereJSONDoesntContain() {
+func (builder *Builder) WhereJSONDoesntContain(column interface{}, value interface{}) Query {
+	return builder.whereJSONContains(column, value, "and", true)
 }
 
 // OrWhereJSONDoesntContain Add an "or where JSON not contains" clause to the query.
-func (builder *Builder) OrWhereJSONDoesntContain() {
+func (builder *Builder) OrWhereJSONDoesntContain(column interface{}, value interface{}) Query {
+	return builder.whereJSONContains(column, value, "or", true)
+}
+
+// whereJSONContains Add a "where JSON contains" clause to the query.
+func (builder *Builder) whereJSONContains(column interface{}, value interface{}, boolean string, not bool) Query {
+	bytes, err := json.Marshal(value)
+	utils.PanicIF(err)
+
+	builder.Query.Wheres = append(builder.Query.Wheres, dbal.Where{
+		Type:    "JSONContains",
+		Column:  column,
+		Value:   string(bytes),
+		Boolean: boolean,
+		Not:     not,
+		Offset:  1,
+	})
+
+	builder.Query.AddBinding("where", string(bytes))
+	return builder
 }
 
 // WhereJSONLength Add a "where JSON length" clause to the query.
-func (builder *Builder) WhereJSONLength() {
+func (builder *Builder) WhereJSONLength(column interface{}, args ...interface{}) Query {
+	operator, value, boolean, _ := builder.prepareWhereArgs(args...)
+
+	builder.Query.Wheres = append(builder.Query.Wheres, dbal.Where{
+		Type:     "JSONLength",
+		Column:   column,
+		Operator: operator,
+		Value:    value,
+		Boolean:  boolean,
+		Offset:   1,
+	})
+
+	if !builder.isExpression(value) {
+		builder.Query.AddBinding("where", value)
+	}
+
+	return builder
 }
 
 // OrWhereJSONLength Add an "or where JSON length" clause to the query.
-func (builder *Builder) OrWhereJSONLength() {
+func (builder *Builder) OrWhereJSONLength(column interface{}, args ...interface{}) Query {
+	operator, value, _, _ := builder.prepareWhereArgs(args...)
+	return builder.WhereJSONLength(column, operator, value, "or")
+}
+
+// WhereJSONContainsKey Add a "where JSON contains key" clause to the query, e.g. WhereJSONContainsKey("options->languages[0]")
+func (builder *Builder) WhereJSONContainsKey(column interface{}) Query {
+	return builder.whereJSONContainsKey(column, "and", false)
+}
+
+// OrWhereJSONContainsKey Add an "or where JSON contains key" clause to the query.
+func (builder *Builder) OrWhereJSONContainsKey(column interface{}) Query {
+	return builder.whereJSONContainsKey(column, "or", false)
+}
+
+// WhereJSONDoesntContainKey Add a "where JSON not contains key" clause to the query.
+func (builder *Builder) WhereJSONDoesntContainKey(column interface{}) Query {
+	return builder.whereJSONContainsKey(column, "and", true)
+}
+
+// OrWhereJSONDoesntContainKey Add an "or where JSON not contains key" clause to the query.
+func (builder *Builder) OrWhereJSONDoesntContainKey(column interface{}) Query {
+	return builder.whereJSONContainsKey(column, "or", true)
+}
+
+// whereJSONContainsKey Add a "where JSON contains key" clause to the query.
+func (builder *Builder) whereJSONContainsKey(column interface{}, boolean string, not bool) Query {
+	builder.Query.Wheres = append(builder.Query.Wheres, dbal.Where{
+		Type:    "JSONContainsKey",
+		Column:  column,
+		Boolean: boolean,
+		Not:     not,
+		Offset:  0,
+	})
+	return builder
 }
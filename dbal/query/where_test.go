@@ -1,6 +1,7 @@
 package query
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -1519,10 +1520,151 @@ func TestWhereUnlessValueIsFalseWidthDefaults(t *testing.T) {
 	checkVoteGT(t, qb)
 }
 
+func TestWhereJSONSelector(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_json").
+		Select("id", "preferences->dining->>meal as meal").
+		Where("preferences->dining->>meal", "salad").
+		OrderBy("id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id", "preferences"->'dining'->>'meal' as "meal" from "table_test_where_json" where "preferences"->'dining'->>'meal' = $1 order by "id" asc`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select `id`, json_extract(`preferences`, '$.\"dining\".\"meal\"') as `meal` from `table_test_where_json` where json_extract(`preferences`, '$.\"dining\".\"meal\"') = ? order by `id` asc", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id`, json_unquote(json_extract(`preferences`, '$.\"dining\".\"meal\"')) as `meal` from `table_test_where_json` where json_unquote(json_extract(`preferences`, '$.\"dining\".\"meal\"')) = ? order by `id` asc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, int64(1), rows[0]["id"].(int64), "the id of the 1st row should be 1")
+		assert.Equal(t, "salad", fmt.Sprintf("%s", rows[0]["meal"]), "the meal of the 1st row should be salad")
+		assert.Equal(t, int64(3), rows[1]["id"].(int64), "the id of the 2nd row should be 3")
+	}
+}
+
+func TestWhereJSONSelectorEscape(t *testing.T) {
+	if unit.DriverIs("postgres") {
+		return
+	}
+	qb := getTestBuilder()
+	qb.Table("table_test_where_json").Select("id").Where(`preferences->dir\path->say"hi`, "salad")
+	sql := qb.ToSQL()
+	if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select `id` from `table_test_where_json` where json_extract(`preferences`, '$.\"dir\\\\path\".\"say\\\"hi\"') = ?", sql, "the backslashes and the double quotes of the keys should be escaped")
+	} else {
+		assert.Equal(t, "select `id` from `table_test_where_json` where json_unquote(json_extract(`preferences`, '$.\"dir\\\\path\".\"say\\\"hi\"')) = ?", sql, "the backslashes and the double quotes of the keys should be escaped")
+	}
+}
+
+func TestWhereJSONSelectorOrderBy(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_where_json").
+		Select("id").
+		OrderByDesc("preferences->>level").
+		MustGet()
+	assert.Equal(t, 3, len(rows), "the return value should be have 3 rows")
+	if len(rows) == 3 {
+		assert.Equal(t, int64(2), rows[0]["id"].(int64), "the id of the 1st row should be 2")
+		assert.Equal(t, int64(3), rows[1]["id"].(int64), "the id of the 2nd row should be 3")
+		assert.Equal(t, int64(1), rows[2]["id"].(int64), "the id of the 3rd row should be 1")
+	}
+}
+
+func TestWhereJSONContains(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_where_json").
+		WhereJSONContains("options->languages", "en").
+		OrderBy("id").
+		MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, int64(1), rows[0]["id"].(int64), "the id of the 1st row should be 1")
+		assert.Equal(t, int64(2), rows[1]["id"].(int64), "the id of the 2nd row should be 2")
+	}
+
+	qb = getTestBuilder()
+	rows = qb.Table("table_test_where_json").
+		WhereJSONContains("options->languages", []string{"en", "de"}).
+		OrWhereJSONContains("preferences->level", "b").
+		OrderBy("id").
+		MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, int64(2), rows[0]["id"].(int64), "the id of the 1st row should be 2")
+		assert.Equal(t, int64(3), rows[1]["id"].(int64), "the id of the 2nd row should be 3")
+	}
+
+	qb = getTestBuilder()
+	rows = qb.Table("table_test_where_json").
+		WhereJSONDoesntContain("options->languages", "en").
+		MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should be have 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, int64(3), rows[0]["id"].(int64), "the id of the row should be 3")
+	}
+}
+
+func TestWhereJSONLength(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_where_json").
+		WhereJSONLength("options->languages", 0).
+		OrWhereJSONLength("options->languages", ">", 1).
+		OrderBy("id").
+		MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, int64(2), rows[0]["id"].(int64), "the id of the 1st row should be 2")
+		assert.Equal(t, int64(3), rows[1]["id"].(int64), "the id of the 2nd row should be 3")
+	}
+}
+
+func TestWhereJSONContainsKey(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_where_json").
+		WhereJSONContainsKey("options->languages[0]").
+		OrderBy("id").
+		MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, int64(1), rows[0]["id"].(int64), "the id of the 1st row should be 1")
+		assert.Equal(t, int64(2), rows[1]["id"].(int64), "the id of the 2nd row should be 2")
+	}
+
+	qb = getTestBuilder()
+	rows = qb.Table("table_test_where_json").
+		WhereJSONContainsKey("options->languages[1]").
+		MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should be have 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, int64(2), rows[0]["id"].(int64), "the id of the row should be 2")
+	}
+
+	qb = getTestBuilder()
+	rows = qb.Table("table_test_where_json").
+		WhereJSONDoesntContainKey("preferences->dining").
+		OrWhereJSONContainsKey("options->languages[2]").
+		MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should be have 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, int64(2), rows[0]["id"].(int64), "the id of the row should be 2")
+	}
+}
+
 // clean the test data
 func TestWhereClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
 	builder.DropTableIfExists("table_test_where")
+	builder.DropTableIfExists("table_test_where_json")
 }
 
 func NewTableForWhereTest() {
@@ -1550,6 +1692,24 @@ func NewTableForWhereTest() {
 	})
 }
 
+func NewTableForWhereJSONTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_where_json")
+	builder.MustCreateTable("table_test_where_json", func(table schema.Blueprint) {
+		table.ID("id")
+		table.JSON("preferences").Null()
+		table.JSON("options").Null()
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_where_json").Insert([]xun.R{
+		{"preferences": `{"dining":{"meal":"salad"},"level":"a"}`, "options": `{"languages":["en"]}`},
+		{"preferences": `{"dining":{"meal":"pizza"},"level":"c"}`, "options": `{"languages":["en","de","fr"]}`},
+		{"preferences": `{"dining":{"meal":"salad"},"level":"b"}`, "options": `{"languages":[]}`},
+	})
+}
+
 func checkVoteGT(t *testing.T, qb Query) {
 	// checking sql
 	sql := qb.ToSQL()
@@ -0,0 +1,39 @@
+package query
+
+import (
+	"github.com/yaoapp/xun/dbal"
+)
+
+// With Add a common table expression to the query, the subquery could be a query builder instance, a Closure, or a raw SQL string, e.g.
+//    qb.With("active_users", func(qb Query) { qb.From("users").Where("status", "active") }).From("active_users")
+func (builder *Builder) With(name string, qb interface{}) Query {
+	return builder.with(name, nil, qb, false)
+}
+
+// WithRecursive Add a recursive common table expression to the query, e.g.
+//    qb.WithRecursive("tree", []string{"id", "parent_id"}, func(qb Query) {
+//        qb.From("categories").Select("id", "parent_id").Where("id", 1).
+//            UnionAll(func(qb Query) {
+//                qb.From("categories as c").Join("tree as t", "t.id", "=", "c.parent_id").Select("c.id", "c.parent_id")
+//            })
+//    }).From("tree")
+func (builder *Builder) WithRecursive(name string, columns []string, qb interface{}) Query {
+	return builder.with(name, columns, qb, true)
+}
+
+// with Add a common table expression to the query.
+func (builder *Builder) with(name string, columns []string, qb interface{}, recursive bool) Query {
+	sub, bindings, _ := builder.createSub(qb)
+	cte := dbal.CTE{
+		Name:      dbal.NewName(name, builder.Conn.Option.Prefix),
+		Columns:   []interface{}{},
+		Recursive: recursive,
+		SQL:       sub,
+	}
+	for _, column := range columns {
+		cte.Columns = append(cte.Columns, column)
+	}
+	builder.Query.CTEs = append(builder.Query.CTEs, cte)
+	builder.Query.AddBinding("with", bindings)
+	return builder
+}
@@ -0,0 +1,118 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestWithWith(t *testing.T) {
+	NewTableForWithTest()
+	qb := getTestBuilder()
+	qb.With("roots", func(qb Query) {
+		qb.From("table_test_with").
+			WhereNull("parent_id").
+			Where("status", "enabled").
+			Select("id", "name")
+	}).
+		From("roots").
+		Where("name", "<>", "Books").
+		OrderBy("id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `with "roots" as (select "id", "name" from "table_test_with" where "parent_id" is null and "status" = $1) select * from "roots" where "name" <> $2 order by "id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "with `roots` as (select `id`, `name` from `table_test_with` where `parent_id` is null and `status` = ?) select * from `roots` where `name` <> ? order by `id` asc", sql, "the query sql not equal")
+	}
+
+	bindings := qb.GetBindings()
+	assert.Equal(t, []interface{}{"enabled", "Books"}, bindings, "the bindings of the CTE should be in front of the others")
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should has 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, "Electronics", rows[0]["name"].(string), "the name of the row should be Electronics")
+	}
+}
+
+func TestWithWithRaw(t *testing.T) {
+	NewTableForWithTest()
+	qb := getTestBuilder()
+	count := qb.With("roots", "select id, name from table_test_with where parent_id is null").
+		From("roots").
+		MustCount()
+	assert.Equal(t, int64(2), count, "the count of the roots should be 2")
+}
+
+func TestWithWithRecursive(t *testing.T) {
+	NewTableForWithTest()
+	qb := getTestBuilder()
+	qb.WithRecursive("tree", []string{"id", "name", "depth"}, func(qb Query) {
+		qb.From("table_test_with").
+			Where("name", "Electronics").
+			Select("id", "name", dbal.Raw("0")).
+			UnionAll(func(qb Query) {
+				qb.From("table_test_with as c").
+					Join("tree as t", "t.id", "=", "c.parent_id").
+					Where("c.status", "enabled").
+					Select("c.id", "c.name", dbal.Raw("t.depth + 1"))
+			})
+	}).
+		From("tree").
+		Where("depth", ">", 0).
+		OrderBy("depth").
+		OrderBy("id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `with recursive "tree" ("id", "name", "depth") as (select "id", "name", 0 from "table_test_with" where "name" = $1 union all select "c"."id", "c"."name", t.depth + 1 from "table_test_with" as "c" inner join "tree" as "t" on "t"."id" = "c"."parent_id" where "c"."status" = $2) select * from "tree" where "depth" > $3 order by "depth" asc, "id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "with recursive `tree` (`id`, `name`, `depth`) as (select `id`, `name`, 0 from `table_test_with` where `name` = ? union all select `c`.`id`, `c`.`name`, t.depth + 1 from `table_test_with` as `c` inner join `tree` as `t` on `t`.`id` = `c`.`parent_id` where `c`.`status` = ?) select * from `tree` where `depth` > ? order by `depth` asc, `id` asc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 3, len(rows), "the return value should has 3 rows")
+	if len(rows) == 3 {
+		assert.Equal(t, "Phones", rows[0]["name"].(string), "the name of the 1st row should be Phones")
+		assert.Equal(t, "Laptops", rows[1]["name"].(string), "the name of the 2nd row should be Laptops")
+		assert.Equal(t, "Smart Phones", rows[2]["name"].(string), "the name of the 3rd row should be Smart Phones")
+		assert.Equal(t, 2, rows[2].GetInt("depth"), "the depth of the 3rd row should be 2")
+	}
+}
+
+// clean the test data
+func TestWithClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_with")
+}
+
+func NewTableForWithTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_with")
+	builder.MustCreateTable("table_test_with", func(table schema.Blueprint) {
+		table.ID("id")
+		table.BigInteger("parent_id").Null()
+		table.String("name", 80)
+		table.Enum("status", []string{"enabled", "disabled"}).SetDefault("enabled")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_with").Insert([]xun.R{
+		{"id": 1, "parent_id": nil, "name": "Electronics", "status": "enabled"},
+		{"id": 2, "parent_id": 1, "name": "Phones", "status": "enabled"},
+		{"id": 3, "parent_id": 1, "name": "Laptops", "status": "enabled"},
+		{"id": 4, "parent_id": 2, "name": "Smart Phones", "status": "enabled"},
+		{"id": 5, "parent_id": 2, "name": "Feature Phones", "status": "disabled"},
+		{"id": 6, "parent_id": nil, "name": "Books", "status": "enabled"},
+	})
+}
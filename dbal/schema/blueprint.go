@@ -1,5 +1,7 @@
 package schema
 
+import "fmt"
+
 // Character types
 
 // String Create a new string column on the table.
@@ -423,3 +425,45 @@ func (table *Table) DropSoftDeletes() {
 func (table *Table) DropSoftDeletesTz() {
 	table.DropSoftDeletes()
 }
+
+// Morphs Add the "{name}_type" and "{name}_id" columns of a polymorphic relation, and a composite index of them.
+func (table *Table) Morphs(name string) map[string]*Column {
+	return table.morphs(name, table.UnsignedBigInteger)
+}
+
+// NullableMorphs Add the nullable "{name}_type" and "{name}_id" columns of a polymorphic relation.
+func (table *Table) NullableMorphs(name string) map[string]*Column {
+	columns := table.Morphs(name)
+	for _, column := range columns {
+		column.Null()
+	}
+	return columns
+}
+
+// UUIDMorphs Add the "{name}_type" and the uuid "{name}_id" columns of a polymorphic relation, and a composite index of them.
+func (table *Table) UUIDMorphs(name string) map[string]*Column {
+	return table.morphs(name, table.UUID)
+}
+
+// NullableUUIDMorphs Add the nullable "{name}_type" and the uuid "{name}_id" columns of a polymorphic relation.
+func (table *Table) NullableUUIDMorphs(name string) map[string]*Column {
+	columns := table.UUIDMorphs(name)
+	for _, column := range columns {
+		column.Null()
+	}
+	return columns
+}
+
+// DropMorphs drop the "{name}_type" and "{name}_id" columns of a polymorphic relation, and the index of them.
+func (table *Table) DropMorphs(name string) {
+	table.DropIndex(fmt.Sprintf("%s_type_id_index", name))
+	table.DropColumn(fmt.Sprintf("%s_type", name), fmt.Sprintf("%s_id", name))
+}
+
+// morphs add the "{name}_type" column and the composite index of the polymorphic relation columns.
+func (table *Table) morphs(name string, idColumn func(name string) *Column) map[string]*Column {
+	typ := table.String(fmt.Sprintf("%s_type", name)).NotNull()
+	id := idColumn(fmt.Sprintf("%s_id", name)).NotNull()
+	table.AddIndex(fmt.Sprintf("%s_type_id_index", name), typ.Name, id.Name)
+	return map[string]*Column{typ.Name: typ, id.Name: id}
+}
@@ -903,9 +903,6 @@ func TestBlueprintTimestampsWithP(t *testing.T) {
 }
 
 func TestBlueprintDropTimestamps(t *testing.T) {
-	if unit.DriverIs("sqlite3") {
-		return
-	}
 	TestBlueprintTimestamps(t)
 	builder := getTestBuilder()
 	err := builder.AlterTable("table_test_blueprint", func(table Blueprint) {
@@ -992,9 +989,6 @@ func TestBlueprintTimestampsTzWithP(t *testing.T) {
 }
 
 func TestBlueprintDropTimestampsTz(t *testing.T) {
-	if unit.DriverIs("sqlite3") {
-		return
-	}
 	TestBlueprintTimestampsTz(t)
 	builder := getTestBuilder()
 	err := builder.AlterTable("table_test_blueprint", func(table Blueprint) {
@@ -1045,9 +1039,6 @@ func TestBlueprintSoftDeletesWithP(t *testing.T) {
 }
 
 func TestBlueprintDropSoftDeletes(t *testing.T) {
-	if unit.DriverIs("sqlite3") {
-		return
-	}
 	TestBlueprintSoftDeletes(t)
 	builder := getTestBuilder()
 	err := builder.AlterTable("table_test_blueprint", func(table Blueprint) {
@@ -1105,9 +1096,6 @@ func TestBlueprintSoftDeletesTzWithP(t *testing.T) {
 }
 
 func TestBlueprintDropSoftDeletesTz(t *testing.T) {
-	if unit.DriverIs("sqlite3") {
-		return
-	}
 	TestBlueprintSoftDeletes(t)
 	builder := getTestBuilder()
 	err := builder.AlterTable("table_test_blueprint", func(table Blueprint) {
@@ -1118,6 +1106,57 @@ func TestBlueprintDropSoftDeletesTz(t *testing.T) {
 	assert.True(t, table.GetColumn("deleted_at") == nil, "the column deleted_at should be nil")
 }
 
+func TestBlueprintMorphs(t *testing.T) {
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_blueprint")
+	err := builder.CreateTable("table_test_blueprint", func(table Blueprint) {
+		table.ID("id")
+		table.Morphs("commentable")
+		table.NullableUUIDMorphs("taggable")
+	})
+	assert.Nil(t, err, "the CreateTable shold be return nil")
+
+	table := testGetTable()
+	commentableType := table.GetColumn("commentable_type")
+	commentableID := table.GetColumn("commentable_id")
+	taggableID := table.GetColumn("taggable_id")
+	assert.True(t, commentableType != nil, "the column commentable_type should be created")
+	assert.True(t, commentableID != nil, "the column commentable_id should be created")
+	assert.True(t, taggableID != nil, "the column taggable_id should be created")
+	assert.True(t, table.HasIndex("commentable_type_id_index", "taggable_type_id_index"), "the table should have commentable_type_id_index and taggable_type_id_index indexes")
+
+	if commentableType != nil {
+		assert.Equal(t, "string", commentableType.Type, "the column commentable_type type should be string")
+		if unit.DriverNot("sqlite3") { // the columns without default values are nullable in SQLite
+			assert.False(t, commentableType.Nullable, "the column commentable_type nullable should be false")
+		}
+	}
+
+	if commentableID != nil {
+		assert.Equal(t, "bigInteger", commentableID.Type, "the column commentable_id type should be bigInteger")
+		if unit.DriverNot("sqlite3") {
+			assert.False(t, commentableID.Nullable, "the column commentable_id nullable should be false")
+		}
+	}
+
+	if taggableID != nil {
+		assert.True(t, taggableID.Nullable, "the column taggable_id nullable should be true")
+	}
+}
+
+func TestBlueprintDropMorphs(t *testing.T) {
+	TestBlueprintMorphs(t)
+	builder := getTestBuilder()
+	err := builder.AlterTable("table_test_blueprint", func(table Blueprint) {
+		table.DropMorphs("commentable")
+	})
+	assert.True(t, err == nil, "the alter method should be return nil")
+	table := testGetTable()
+	assert.True(t, table.GetColumn("commentable_type") == nil, "the column commentable_type should be nil")
+	assert.True(t, table.GetColumn("commentable_id") == nil, "the column commentable_id should be nil")
+	assert.False(t, table.HasIndex("commentable_type_id_index"), "the index commentable_type_id_index should be dropped")
+}
+
 // clean the test data
 func TestBlueprintClean(t *testing.T) {
 	builder := getTestBuilder()
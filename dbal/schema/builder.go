@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -87,6 +88,23 @@ func (builder *Builder) Builder() *Builder {
 	return builder
 }
 
+// WithContext Create a new schema builder instance with the given context.
+// The context is passed to the database driver, it can be used to cancel the statements or to set a deadline.
+func (builder *Builder) WithContext(ctx context.Context) Schema {
+	new := *builder
+	new.Ctx = ctx
+	new.Grammar = builder.Grammar.WithContext(ctx)
+	return &new
+}
+
+// Context Get the context of the schema builder, returns context.Background() if the context is not set.
+func (builder *Builder) Context() context.Context {
+	if builder.Ctx != nil {
+		return builder.Ctx
+	}
+	return context.Background()
+}
+
 // reconnect reconnect db server using setting driver and dsn
 func (builder *Builder) reconnect() {
 	driver := builder.Conn.WriteConfig.Driver
@@ -219,6 +237,16 @@ func (builder *Builder) GetTable(name string) (Blueprint, error) {
 		}
 	}
 
+	// attaching foreign keys
+	for _, foreign := range table.Table.Foreigns {
+		name := foreign.Name
+		table.ForeignNames = append(table.ForeignNames, name)
+		table.ForeignMap[name] = &Foreign{
+			Foreign: foreign,
+			Table:   table,
+		}
+	}
+
 	// attaching primary
 	if table.Table.Primary != nil {
 		table.Primary = &Primary{
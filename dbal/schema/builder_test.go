@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -180,6 +181,28 @@ func TestBuilderHasTable(t *testing.T) {
 	assert.False(t, has, "the return value should be false")
 }
 
+func TestBuilderWithContext(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	ctx, cancel := context.WithCancel(context.Background())
+	ctxBuilder := builder.WithContext(ctx)
+	assert.Equal(t, ctx, ctxBuilder.Context(), "the context should be the given context")
+	assert.Equal(t, context.Background(), builder.Context(), "the context of the original builder should not be changed")
+
+	_, err := ctxBuilder.HasTable("table_test_builder")
+	assert.True(t, err == nil, "the return error should be nil")
+
+	cancel()
+	_, err = ctxBuilder.HasTable("table_test_builder")
+	assert.Equal(t, context.Canceled, err, "the return error should be context.Canceled")
+
+	err = ctxBuilder.CreateTable("table_test_builder_context", func(table Blueprint) {
+		table.ID("id")
+	})
+	assert.Equal(t, context.Canceled, err, "the return error should be context.Canceled")
+	assert.False(t, builder.MustHasTable("table_test_builder_context"), "the table should not be created")
+}
+
 func TestBuilderCreateTable(t *testing.T) {
 	defer unit.Catch()
 	builder := getTestBuilder()
@@ -64,9 +64,6 @@ func TestColumnRenameColumnFail(t *testing.T) {
 }
 
 func TestColumnDropColumn(t *testing.T) {
-	if unit.DriverIs("sqlite3") {
-		return
-	}
 	defer unit.Catch()
 	builder := getTestBuilder()
 	NewTableForColumnTest()
@@ -78,6 +75,71 @@ func TestColumnDropColumn(t *testing.T) {
 	assert.False(t, table.HasColumn("field2"), "the table table_test_column should not have the field2 column")
 }
 
+func TestColumnDropColumnKeepRows(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForColumnTest()
+	db := builder.MustGetDB()
+	_, err := db.Exec("INSERT INTO table_test_column (field1, field2) VALUES ('v1', 'v2')")
+	assert.Equal(t, nil, err, "the return error should be nil")
+
+	builder.MustAlterTable("table_test_column", func(table Blueprint) {
+		table.DropColumn("field2")
+	})
+	table := builder.MustGetTable("table_test_column")
+	assert.False(t, table.HasColumn("field2"), "the table table_test_column should not have the field2 column")
+	assert.True(t, table.HasIndex("field1_index"), "the table table_test_column should have the field1_index index")
+	assert.Equal(t, "AutoIncrement", utils.StringVal(table.GetColumn("id").Extra), "the id extra should be AutoIncrement")
+
+	rows := []struct {
+		ID     int    `db:"id"`
+		Field1 string `db:"field1"`
+		Field3 string `db:"field3"`
+	}{}
+	err = db.Select(&rows, "SELECT id, field1, field3 FROM table_test_column")
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.Equal(t, 1, len(rows), "the table table_test_column should have 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, 1, rows[0].ID, "the id of the row should be 1")
+		assert.Equal(t, "v1", rows[0].Field1, "the field1 of the row should be v1")
+		assert.Equal(t, "DefaultValue3", rows[0].Field3, "the field3 of the row should be DefaultValue3")
+	}
+}
+
+func TestColumnChangeColumn(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForColumnTest()
+	builder.MustAlterTable("table_test_column", func(table Blueprint) {
+		table.String("field2", 100)
+	})
+	table := builder.MustGetTable("table_test_column")
+	assert.Equal(t, 100, utils.IntVal(table.GetColumn("field2").Length), "the length of the field2 column should be 100")
+	assert.True(t, table.HasIndex("field1_field2"), "the table table_test_column should have the field1_field2 index")
+}
+
+func TestColumnChangeColumnWithoutRebuild(t *testing.T) {
+	if unit.DriverNot("sqlite3") {
+		return
+	}
+
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForColumnTest()
+	origin := testColumnTableSQL(t, builder)
+	builder.MustAlterTable("table_test_column", func(table Blueprint) {
+		table.String("field2").SetComment("the comments are not supported by SQLite")
+	})
+	assert.Equal(t, origin, testColumnTableSQL(t, builder), "the table should not be rebuilt")
+}
+
+func testColumnTableSQL(t *testing.T, builder Schema) string {
+	sql := ""
+	err := builder.MustGetDB().Get(&sql, "SELECT `sql` FROM sqlite_master WHERE type='table' AND name='table_test_column'")
+	assert.Nil(t, err, "the return error should be nil")
+	return sql
+}
+
 func TestColumnSetLength(t *testing.T) {
 	defer unit.Catch()
 	builder := getTestBuilderInstance()
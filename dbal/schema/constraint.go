@@ -1,3 +1,94 @@
 package schema
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+)
+
 // the constraint methods definition
+
+// GetForeign get the foreign key instance for the given name, if the foreign key does not exist return nil.
+func (table *Table) GetForeign(name string) *Foreign {
+	return table.ForeignMap[name]
+}
+
+// HasForeign Determine if the table has a given foreign key.
+func (table *Table) HasForeign(name ...string) bool {
+	has := true
+	for _, n := range name {
+		_, has = table.ForeignMap[n]
+		if !has {
+			return has
+		}
+	}
+	return has
+}
+
+// Foreign Indicate that the given columns should reference another table.
+// The foreign key is named {column1}_{column2}_foreign, e.g.
+//    table.Foreign("user_id").References("id").On("users").OnDelete("cascade")
+func (table *Table) Foreign(columnNames ...string) *Foreign {
+	columns := []*dbal.Column{}
+	for _, name := range columnNames {
+		column := table.GetColumn(name)
+		if column == nil {
+			panic(fmt.Errorf("the column %s does not exists", name))
+		}
+		columns = append(columns, column.Column)
+	}
+
+	name := fmt.Sprintf("%s_foreign", strings.Join(columnNames, "_"))
+	foreign := &Foreign{
+		Foreign: table.Table.NewForeign(name, columns...),
+		Table:   table,
+	}
+	table.pushForeign(foreign)
+	table.createForeignCommand(foreign.Foreign, nil, func() {
+		delete(table.ForeignMap, foreign.Name)
+	})
+	return foreign
+}
+
+// DropForeign Indicate that the given foreign keys should be dropped.
+func (table *Table) DropForeign(name ...string) {
+	for _, n := range name {
+		table.dropForeignCommand(n, func() {
+			delete(table.ForeignMap, n)
+		}, nil)
+	}
+}
+
+// pushForeign add a foreign key to the table
+func (table *Table) pushForeign(foreign *Foreign) *Table {
+	table.Table.PushForeign(foreign.Foreign)
+	table.ForeignMap[foreign.Name] = foreign
+	return table
+}
+
+// the foreign key portables
+
+// References Specify the referenced columns.
+func (foreign *Foreign) References(columnNames ...string) *Foreign {
+	foreign.Foreign.References = columnNames
+	return foreign
+}
+
+// On Specify the referenced table, the table prefix will be added.
+func (foreign *Foreign) On(name string) *Foreign {
+	foreign.ReferenceTableName = fmt.Sprintf("%s%s", foreign.Table.Prefix, name)
+	return foreign
+}
+
+// OnDelete Add an ON DELETE action. (cascade, set null, set default, restrict, no action)
+func (foreign *Foreign) OnDelete(action string) *Foreign {
+	foreign.Foreign.OnDelete = strings.ToUpper(action)
+	return foreign
+}
+
+// OnUpdate Add an ON UPDATE action. (cascade, set null, set default, restrict, no action)
+func (foreign *Foreign) OnUpdate(action string) *Foreign {
+	foreign.Foreign.OnUpdate = strings.ToUpper(action)
+	return foreign
+}
@@ -0,0 +1,102 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestConstraintForeign(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForConstraintTest(builder)
+
+	table := builder.MustGetTable("table_test_constraint_post")
+	assert.True(t, table.HasForeign("user_id_foreign"), "the table should have the user_id_foreign foreign key")
+	if table.HasForeign("user_id_foreign") {
+		foreign := table.GetForeign("user_id_foreign")
+		assert.Equal(t, "table_test_constraint_user", foreign.ReferenceTableName, "the reference table of user_id_foreign should be table_test_constraint_user")
+		assert.Equal(t, []string{"id"}, foreign.Foreign.References, "the reference columns of user_id_foreign should be [id]")
+		assert.Equal(t, "CASCADE", foreign.Foreign.OnDelete, "the on delete action of user_id_foreign should be CASCADE")
+		assert.Equal(t, 1, len(foreign.Columns), "the user_id_foreign should have 1 column")
+		if len(foreign.Columns) == 1 {
+			assert.Equal(t, "user_id", foreign.Columns[0].Name, "the column of user_id_foreign should be user_id")
+		}
+	}
+	assert.Equal(t, []string{"user_id_foreign"}, table.GetForeignNames(), "the foreign names should be [user_id_foreign]")
+}
+
+func TestConstraintForeignColumnNotExists(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.MustDropTableIfExists("table_test_constraint_post")
+	assert.Panics(t, func() {
+		builder.CreateTable("table_test_constraint_post", func(table Blueprint) {
+			table.ID("id")
+			table.Foreign("user_id").References("id").On("table_test_constraint_user")
+		})
+	})
+}
+
+func TestConstraintDropForeign(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForConstraintTest(builder)
+
+	builder.MustAlterTable("table_test_constraint_post", func(table Blueprint) {
+		table.DropForeign("user_id_foreign")
+	})
+	table := builder.MustGetTable("table_test_constraint_post")
+	assert.False(t, table.HasForeign("user_id_foreign"), "the table should not have the user_id_foreign foreign key")
+	assert.True(t, table.HasColumn("user_id"), "the table should have the user_id column")
+
+	builder.MustAlterTable("table_test_constraint_post", func(table Blueprint) {
+		table.Foreign("user_id").References("id").On("table_test_constraint_user").OnUpdate("cascade")
+	})
+	table = builder.MustGetTable("table_test_constraint_post")
+	assert.True(t, table.HasForeign("user_id_foreign"), "the table should have the user_id_foreign foreign key")
+	if table.HasForeign("user_id_foreign") {
+		assert.Equal(t, "CASCADE", table.GetForeign("user_id_foreign").Foreign.OnUpdate, "the on update action of user_id_foreign should be CASCADE")
+	}
+}
+
+func TestConstraintDropForeignIndex(t *testing.T) {
+	if unit.DriverNot("mysql") {
+		return
+	}
+
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForConstraintTest(builder)
+
+	builder.MustAlterTable("table_test_constraint_post", func(table Blueprint) {
+		table.DropForeign("user_id_foreign")
+	})
+	table := builder.MustGetTable("table_test_constraint_post")
+	assert.Equal(t, []string{}, table.GetForeignNames(), "the foreign names should be empty")
+	assert.False(t, table.HasIndex("table_test_constraint_post_user_id_foreign"), "the index created by MySQL for the foreign key should be dropped")
+	assert.NotContains(t, table.GetIndexNames(), "table_test_constraint_post_user_id_foreign", "the index names should not have the index of the foreign key")
+}
+
+// clean the test data
+func TestConstraintClean(t *testing.T) {
+	builder := getTestBuilder()
+	builder.MustDropTableIfExists("table_test_constraint_post")
+	builder.MustDropTableIfExists("table_test_constraint_user")
+}
+
+func NewTableForConstraintTest(builder Schema) {
+	builder.MustDropTableIfExists("table_test_constraint_post")
+	builder.MustDropTableIfExists("table_test_constraint_user")
+	builder.MustCreateTable("table_test_constraint_user", func(table Blueprint) {
+		table.ID("id")
+		table.String("name", 80)
+	})
+	builder.MustCreateTable("table_test_constraint_post", func(table Blueprint) {
+		table.ID("id")
+		table.String("title", 200)
+		table.ForeignID("user_id")
+		table.Foreign("user_id").References("id").On("table_test_constraint_user").OnDelete("cascade")
+	})
+}
@@ -0,0 +1,457 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// the types stored as another type by some of the drivers, e.g. SQLite stores json as text.
+var typeAliases = map[string][]string{
+	"char":        {"string"},
+	"mediumText":  {"text"},
+	"longText":    {"text"},
+	"json":        {"text"},
+	"jsonb":       {"json", "text"},
+	"uuid":        {"string", "char"},
+	"ipAddress":   {"integer"},
+	"macAddress":  {"bigInteger"},
+	"year":        {"smallInteger"},
+	"boolean":     {"tinyInteger"},
+	"enum":        {"text", "string"},
+	"dateTimeTz":  {"dateTime"},
+	"timeTz":      {"time"},
+	"timestampTz": {"timestamp"},
+}
+
+// the integer types, the auto-incrementing integer column could be stored as another integer type.
+var integerTypes = map[string]bool{
+	"tinyInteger":   true,
+	"smallInteger":  true,
+	"integer":       true,
+	"bigInteger":    true,
+	"mediumInteger": true,
+}
+
+// the type cast suffix of the default values, e.g. 'active'::character varying on Postgres.
+var castPattern = regexp.MustCompile(`::[a-zA-Z ]+$`)
+
+// DiffTable Compare the table definition with the live table, returns the differences between them.
+// The columns are compared using the attributes set in the definition, renaming a column is treated as dropping and adding it.
+// The foreign keys are compared by the columns, the referenced table and columns and the actions.
+func (builder *Builder) DiffTable(name string, define func(table Blueprint)) (*Diff, error) {
+	live, err := builder.GetTable(name)
+	if err != nil {
+		return nil, err
+	}
+
+	table := builder.table(name)
+	define(table)
+	return builder.diff(table, live.Get()), nil
+}
+
+// MustDiffTable Compare the table definition with the live table, returns the differences between them.
+func (builder *Builder) MustDiffTable(name string, define func(table Blueprint)) *Diff {
+	diff, err := builder.DiffTable(name, define)
+	utils.PanicIF(err)
+	return diff
+}
+
+// SyncTable Create the table if it does not exist, otherwise alter the table to match the definition.
+// The columns, indexes and foreign keys of the live table missing from the definition are kept, use PruneTable to drop them.
+func (builder *Builder) SyncTable(name string, define func(table Blueprint)) error {
+	return builder.syncTable(name, define, false)
+}
+
+// MustSyncTable Create the table if it does not exist, otherwise alter the table to match the definition.
+func (builder *Builder) MustSyncTable(name string, define func(table Blueprint)) {
+	err := builder.SyncTable(name, define)
+	utils.PanicIF(err)
+}
+
+// PruneTable Create the table if it does not exist, otherwise alter the table to match the definition,
+// and drop the columns, indexes and foreign keys of the live table missing from the definition.
+func (builder *Builder) PruneTable(name string, define func(table Blueprint)) error {
+	return builder.syncTable(name, define, true)
+}
+
+// MustPruneTable Create the table if it does not exist, otherwise alter the table to match the definition,
+// and drop the columns, indexes and foreign keys of the live table missing from the definition.
+func (builder *Builder) MustPruneTable(name string, define func(table Blueprint)) {
+	err := builder.PruneTable(name, define)
+	utils.PanicIF(err)
+}
+
+// syncTable create or alter the table to match the definition, the undeclared columns, indexes and foreign keys are dropped only if prune is true.
+func (builder *Builder) syncTable(name string, define func(table Blueprint), prune bool) error {
+	has, err := builder.HasTable(name)
+	if err != nil {
+		return err
+	}
+
+	if !has {
+		return builder.CreateTable(name, define)
+	}
+
+	diff, err := builder.DiffTable(name, define)
+	if err != nil {
+		return err
+	}
+
+	if !prune {
+		diff.keep()
+	}
+
+	if diff.IsEmpty() {
+		return nil
+	}
+
+	return builder.AlterTable(name, diff.Apply)
+}
+
+// IsEmpty Determine if there are no differences
+func (diff *Diff) IsEmpty() bool {
+	return len(diff.AddColumns) == 0 &&
+		len(diff.ChangeColumns) == 0 &&
+		len(diff.DropColumns) == 0 &&
+		len(diff.CreateIndexes) == 0 &&
+		len(diff.DropIndexes) == 0 &&
+		diff.CreatePrimary == nil &&
+		!diff.DropPrimary &&
+		len(diff.CreateForeigns) == 0 &&
+		len(diff.DropForeigns) == 0
+}
+
+// keep remove the dropping of the undeclared columns, indexes, foreign keys and primary key, only the ones to recreate are dropped.
+func (diff *Diff) keep() {
+	diff.DropColumns = []string{}
+
+	indexes := map[string]bool{}
+	for _, index := range diff.CreateIndexes {
+		indexes[index.Name] = true
+	}
+	dropIndexes := []string{}
+	for _, name := range diff.DropIndexes {
+		if indexes[name] {
+			dropIndexes = append(dropIndexes, name)
+		}
+	}
+	diff.DropIndexes = dropIndexes
+
+	foreigns := map[string]bool{}
+	for _, foreign := range diff.CreateForeigns {
+		foreigns[foreign.Name] = true
+	}
+	dropForeigns := []string{}
+	for _, name := range diff.DropForeigns {
+		if foreigns[name] {
+			dropForeigns = append(dropForeigns, name)
+		}
+	}
+	diff.DropForeigns = dropForeigns
+
+	if diff.CreatePrimary == nil {
+		diff.DropPrimary = false
+	}
+}
+
+// Apply Add the commands converging the live table to the definition, e.g.
+//    builder.AlterTable("users", diff.Apply)
+func (diff *Diff) Apply(table Blueprint) {
+	live := table.Get()
+	for _, name := range diff.DropForeigns {
+		live.DropForeign(name)
+	}
+
+	for _, name := range diff.DropIndexes {
+		live.DropIndex(name)
+	}
+
+	if diff.DropPrimary && live.Primary != nil {
+		live.DropPrimary()
+	}
+
+	for _, name := range diff.DropColumns {
+		live.DropColumn(name)
+	}
+
+	for _, column := range diff.ChangeColumns {
+		live.changeColumn(live.bindColumn(column))
+	}
+
+	for _, column := range diff.AddColumns {
+		live.addColumn(live.bindColumn(column))
+	}
+
+	if diff.CreatePrimary != nil {
+		live.addPrimaryWithName(diff.CreatePrimary.Name, columnNames(diff.CreatePrimary.Columns)...)
+	}
+
+	for _, index := range diff.CreateIndexes {
+		if index.Type == "unique" {
+			live.AddUnique(index.Name, columnNames(index.Columns)...)
+			continue
+		}
+		live.AddIndex(index.Name, columnNames(index.Columns)...)
+	}
+
+	for _, foreign := range diff.CreateForeigns {
+		new := live.Foreign(columnNames(foreign.Columns)...).References(foreign.Foreign.References...)
+		new.ReferenceTableName = foreign.ReferenceTableName
+		new.OnDelete(foreign.Foreign.OnDelete)
+		new.OnUpdate(foreign.Foreign.OnUpdate)
+	}
+}
+
+// diff compare the table definition with the live table
+func (builder *Builder) diff(table *Table, live *Table) *Diff {
+	diff := &Diff{
+		Name:           table.Name,
+		AddColumns:     []*Column{},
+		ChangeColumns:  []*Column{},
+		DropColumns:    []string{},
+		CreateIndexes:  []*Index{},
+		DropIndexes:    []string{},
+		CreateForeigns: []*Foreign{},
+		DropForeigns:   []string{},
+	}
+
+	// Columns
+	for _, column := range table.Table.Columns {
+		liveColumn, has := live.ColumnMap[column.Name]
+		if !has {
+			diff.AddColumns = append(diff.AddColumns, table.ColumnMap[column.Name])
+			continue
+		}
+		if builder.columnChanged(column, liveColumn.Column) {
+			diff.ChangeColumns = append(diff.ChangeColumns, table.ColumnMap[column.Name])
+		}
+	}
+
+	for _, column := range live.Table.Columns {
+		if _, has := table.ColumnMap[column.Name]; !has {
+			diff.DropColumns = append(diff.DropColumns, column.Name)
+		}
+	}
+
+	// Indexes
+	for _, idx := range table.Table.Indexes {
+		name := idx.Name
+		index, has := table.IndexMap[name]
+		if !has || index.Type == "primary" {
+			continue
+		}
+		liveIndex, has := live.IndexMap[name]
+		if has && liveIndex.Type == index.Type && columnsEqual(liveIndex.Columns, index.Columns) {
+			continue
+		}
+		if has {
+			diff.DropIndexes = append(diff.DropIndexes, name)
+		}
+		diff.CreateIndexes = append(diff.CreateIndexes, index)
+	}
+
+	for _, name := range live.IndexNames {
+		index := live.IndexMap[name]
+		if index.Type == "primary" || strings.HasPrefix(name, "sqlite_autoindex_") {
+			continue
+		}
+		if _, has := table.IndexMap[name]; !has {
+			diff.DropIndexes = append(diff.DropIndexes, name)
+		}
+	}
+
+	// Primary key
+	if table.Primary == nil && live.Primary != nil {
+		diff.DropPrimary = true
+	} else if table.Primary != nil && live.Primary == nil {
+		diff.CreatePrimary = table.Primary
+	} else if table.Primary != nil && !columnsEqual(table.Primary.Columns, live.Primary.Columns) {
+		diff.DropPrimary = true
+		diff.CreatePrimary = table.Primary
+	}
+
+	// Foreign keys
+	for _, fk := range table.Table.Foreigns {
+		name := fk.Name
+		foreign, has := table.ForeignMap[name]
+		if !has {
+			continue
+		}
+		liveForeign, has := live.ForeignMap[name]
+		if has && foreignEqual(foreign.Foreign, liveForeign.Foreign) {
+			continue
+		}
+		if has {
+			diff.DropForeigns = append(diff.DropForeigns, name)
+		}
+		diff.CreateForeigns = append(diff.CreateForeigns, foreign)
+	}
+
+	for _, name := range live.ForeignNames {
+		if _, has := table.ForeignMap[name]; !has {
+			diff.DropForeigns = append(diff.DropForeigns, name)
+		}
+	}
+
+	return diff
+}
+
+// columnChanged Determine if the column definition is different from the live column.
+// Only the attributes set in the definition are compared.
+func (builder *Builder) columnChanged(column *dbal.Column, live *dbal.Column) bool {
+
+	if !typeEqual(column, live) {
+		return true
+	}
+
+	if column.Type == "string" || column.Type == "char" || column.Type == "binary" {
+		if column.Length != nil && utils.IntVal(live.Length) != utils.IntVal(column.Length) {
+			return true
+		}
+	}
+
+	if column.Type == "decimal" || column.Type == "float" || column.Type == "double" {
+		if column.Precision != nil && live.Precision != nil && utils.IntVal(live.Precision) != utils.IntVal(column.Precision) {
+			return true
+		}
+		if column.Scale != nil && live.Scale != nil && utils.IntVal(live.Scale) != utils.IntVal(column.Scale) {
+			return true
+		}
+	}
+
+	if column.DateTimePrecision != nil && live.DateTimePrecision != nil &&
+		utils.IntVal(live.DateTimePrecision) != utils.IntVal(column.DateTimePrecision) {
+		return true
+	}
+
+	// the primary key columns are always not null
+	if !column.Primary && !live.Primary && builder.nullable(column) != live.Nullable {
+		return true
+	}
+
+	// the raw default values are rewritten by the drivers, e.g. NOW() => datetime('now','localtime'), so only the literal values are compared
+	if column.Default != nil && defaultValue(column) != defaultValue(live) {
+		return true
+	}
+
+	if column.Comment != nil && live.Comment != nil && utils.StringVal(column.Comment) != utils.StringVal(live.Comment) {
+		return true
+	}
+
+	return false
+}
+
+// nullable get the nullable attribute of the column stored by the driver.
+// SQLite creates the not null column without a default value as a nullable column.
+func (builder *Builder) nullable(column *dbal.Column) bool {
+	if builder.Conn.WriteConfig.Driver == "sqlite3" && column.Default == nil && column.DefaultRaw == "" {
+		return true
+	}
+	return column.Nullable
+}
+
+// typeEqual Determine if the column types are the same
+func typeEqual(column *dbal.Column, live *dbal.Column) bool {
+	if column.Type == live.Type {
+		return true
+	}
+
+	if column.Extra != nil && live.Extra != nil && integerTypes[column.Type] && integerTypes[live.Type] {
+		return true
+	}
+
+	for _, alias := range typeAliases[column.Type] {
+		if alias == live.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultValue get the normalized default value of the column, e.g. 'hello'::character varying => hello
+func defaultValue(column *dbal.Column) string {
+	value := column.DefaultRaw
+	if value == "" {
+		switch v := column.Default.(type) {
+		case nil:
+			return ""
+		case []byte:
+			value = string(v)
+		case bool:
+			value = utils.GetIF(v, "1", "0").(string)
+		default:
+			value = fmt.Sprintf("%v", v)
+		}
+	}
+
+	value = castPattern.ReplaceAllString(value, "")
+	value = strings.TrimSpace(value)
+	for strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		value = strings.TrimSpace(value[1 : len(value)-1])
+	}
+	value = strings.Trim(value, "'\"")
+
+	switch strings.ToLower(value) {
+	case "true":
+		return "1"
+	case "false":
+		return "0"
+	}
+	return value
+}
+
+// bindColumn copy the column of the table definition and bind it to the table
+func (table *Table) bindColumn(column *Column) *Column {
+	new := *column.Column
+	new.Table = table.Table
+	new.TableName = table.Table.TableName
+	new.Indexes = []*dbal.Index{}
+	return &Column{Column: &new, Table: table}
+}
+
+// foreignEqual Determine if the foreign keys have the same columns, referenced table, referenced columns and actions
+func foreignEqual(foreign *dbal.Foreign, live *dbal.Foreign) bool {
+	if !columnsEqual(foreign.Columns, live.Columns) ||
+		!strings.EqualFold(foreign.ReferenceTableName, live.ReferenceTableName) ||
+		strings.Join(foreign.References, ",") != strings.Join(live.References, ",") {
+		return false
+	}
+	return foreignAction(foreign.OnDelete) == foreignAction(live.OnDelete) &&
+		foreignAction(foreign.OnUpdate) == foreignAction(live.OnUpdate)
+}
+
+// foreignAction get the normalized action of the foreign key,
+// the drivers report the default action as NO ACTION or RESTRICT, the both of them reject the change of the referenced rows.
+func foreignAction(action string) string {
+	action = strings.ToUpper(strings.TrimSpace(action))
+	if action == "NO ACTION" || action == "RESTRICT" {
+		return ""
+	}
+	return action
+}
+
+// columnsEqual Determine if the columns have the same names in the same order
+func columnsEqual(columns []*dbal.Column, others []*dbal.Column) bool {
+	if len(columns) != len(others) {
+		return false
+	}
+	for i := range columns {
+		if columns[i].Name != others[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// columnNames get the names of the columns
+func columnNames(columns []*dbal.Column) []string {
+	names := []string{}
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	return names
+}
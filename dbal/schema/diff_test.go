@@ -0,0 +1,162 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/unit"
+	"github.com/yaoapp/xun/utils"
+)
+
+func TestDiffTableEmpty(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.MustDropTableIfExists("table_test_diff")
+	builder.MustCreateTable("table_test_diff", testDiffDefine)
+	diff := builder.MustDiffTable("table_test_diff", testDiffDefine)
+	assert.True(t, diff.IsEmpty(), "the table should be the same as the definition")
+}
+
+func TestDiffTable(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.MustDropTableIfExists("table_test_diff")
+	builder.MustCreateTable("table_test_diff", testDiffDefine)
+	diff := builder.MustDiffTable("table_test_diff", func(table Blueprint) {
+		table.ID("id")
+		table.String("name", 120).Unique()
+		table.Integer("vote").SetDefault(1)
+		table.Text("bio").Null()
+		table.AddIndex("name_vote", "vote", "name")
+		table.Timestamps()
+	})
+
+	assert.False(t, diff.IsEmpty(), "the table should be different from the definition")
+	assert.Equal(t, []string{"bio"}, testDiffColumnNames(diff.AddColumns), "the bio column should be added")
+	assert.Equal(t, []string{"name", "vote"}, testDiffColumnNames(diff.ChangeColumns), "the name and vote columns should be changed")
+	assert.Equal(t, []string{"score"}, diff.DropColumns, "the score column should be dropped")
+	assert.Equal(t, 1, len(diff.CreateIndexes), "the name_vote index should be recreated")
+	assert.Equal(t, []string{"name_vote", "score_index"}, diff.DropIndexes, "the name_vote and score_index indexes should be dropped")
+	assert.Nil(t, diff.CreatePrimary, "the primary key should not be changed")
+	assert.False(t, diff.DropPrimary, "the primary key should not be changed")
+}
+
+func TestDiffSyncTable(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.MustDropTableIfExists("table_test_diff")
+	builder.MustSyncTable("table_test_diff", testDiffDefine)
+	assert.True(t, builder.MustHasTable("table_test_diff"), "the table should be created")
+
+	define := func(table Blueprint) {
+		table.ID("id")
+		table.String("name", 120).Unique()
+		table.Integer("vote").SetDefault(1)
+		table.Text("bio").Null()
+		table.AddIndex("name_vote", "vote", "name")
+		table.Timestamps()
+	}
+	builder.MustSyncTable("table_test_diff", define)
+	table := builder.MustGetTable("table_test_diff")
+	assert.True(t, table.HasColumn("bio"), "the bio column should be added")
+	assert.True(t, table.HasColumn("score"), "the score column should be kept")
+	assert.True(t, table.HasIndex("score_index"), "the score_index index should be kept")
+	assert.Equal(t, 120, utils.IntVal(table.GetColumn("name").Length), "the length of the name column should be 120")
+	assert.True(t, table.HasIndex("name_vote"), "the name_vote index should be created")
+	if table.HasIndex("name_vote") {
+		assert.Equal(t, "vote", table.GetIndex("name_vote").Columns[0].Name, "the first column of the name_vote index should be vote")
+	}
+	diff := builder.MustDiffTable("table_test_diff", define)
+	assert.Equal(t, []string{"score"}, diff.DropColumns, "only the score column should be different")
+	assert.Equal(t, []string{"score_index"}, diff.DropIndexes, "only the score_index index should be different")
+	assert.Equal(t, 0, len(diff.AddColumns)+len(diff.ChangeColumns)+len(diff.CreateIndexes), "the table should match the definition")
+}
+
+func TestDiffPruneTable(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.MustDropTableIfExists("table_test_diff")
+	builder.MustCreateTable("table_test_diff", testDiffDefine)
+
+	define := func(table Blueprint) {
+		table.ID("id")
+		table.String("name", 80).Unique()
+		table.Integer("vote")
+		table.AddIndex("name_vote", "name", "vote")
+		table.Timestamps()
+	}
+	builder.MustPruneTable("table_test_diff", define)
+	table := builder.MustGetTable("table_test_diff")
+	assert.False(t, table.HasColumn("score"), "the score column should be dropped")
+	assert.False(t, table.HasIndex("score_index"), "the score_index index should be dropped")
+	assert.True(t, builder.MustDiffTable("table_test_diff", define).IsEmpty(), "the table should be the same as the definition")
+}
+
+func TestDiffForeign(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.MustDropTableIfExists("table_test_diff_post")
+	builder.MustDropTableIfExists("table_test_diff")
+	builder.MustCreateTable("table_test_diff", testDiffDefine)
+
+	define := func(table Blueprint) {
+		table.ID("id")
+		table.String("title", 80)
+		table.BigInteger("user_id").Unsigned()
+		table.Foreign("user_id").References("id").On("table_test_diff")
+	}
+	builder.MustSyncTable("table_test_diff_post", define)
+	assert.True(t, builder.MustDiffTable("table_test_diff_post", define).IsEmpty(), "the table should be the same as the definition")
+
+	cascade := func(table Blueprint) {
+		table.ID("id")
+		table.String("title", 80)
+		table.BigInteger("user_id").Unsigned()
+		table.Foreign("user_id").References("id").On("table_test_diff").OnDelete("cascade")
+	}
+	diff := builder.MustDiffTable("table_test_diff_post", cascade)
+	assert.Equal(t, []string{"user_id_foreign"}, diff.DropForeigns, "the user_id_foreign foreign key should be dropped")
+	assert.Equal(t, 1, len(diff.CreateForeigns), "the user_id_foreign foreign key should be recreated")
+
+	builder.MustSyncTable("table_test_diff_post", cascade)
+	table := builder.MustGetTable("table_test_diff_post")
+	assert.True(t, table.HasForeign("user_id_foreign"), "the user_id_foreign foreign key should be created")
+	if table.HasForeign("user_id_foreign") {
+		assert.Equal(t, "CASCADE", table.GetForeign("user_id_foreign").Foreign.OnDelete, "the user_id_foreign foreign key should cascade on delete")
+	}
+	assert.True(t, builder.MustDiffTable("table_test_diff_post", cascade).IsEmpty(), "the table should be the same as the definition")
+
+	withoutForeign := func(table Blueprint) {
+		table.ID("id")
+		table.String("title", 80)
+		table.BigInteger("user_id").Unsigned()
+	}
+	builder.MustSyncTable("table_test_diff_post", withoutForeign)
+	assert.True(t, builder.MustGetTable("table_test_diff_post").HasForeign("user_id_foreign"), "the undeclared foreign key should be kept")
+	builder.MustPruneTable("table_test_diff_post", withoutForeign)
+	assert.False(t, builder.MustGetTable("table_test_diff_post").HasForeign("user_id_foreign"), "the undeclared foreign key should be dropped")
+}
+
+// clean the test data
+func TestDiffClean(t *testing.T) {
+	builder := getTestBuilder()
+	builder.MustDropTableIfExists("table_test_diff_post")
+	builder.MustDropTableIfExists("table_test_diff")
+}
+
+func testDiffDefine(table Blueprint) {
+	table.ID("id")
+	table.String("name", 80).Unique()
+	table.Integer("vote")
+	table.Float("score", 5, 2).Index()
+	table.AddIndex("name_vote", "name", "vote")
+	table.Timestamps()
+}
+
+func testDiffColumnNames(columns []*Column) []string {
+	names := []string{}
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	return names
+}
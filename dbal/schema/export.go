@@ -0,0 +1,241 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// the types keeping the length, precision and scale attributes in the document
+var lengthTypes = map[string]bool{"string": true, "char": true, "binary": true}
+var precisionTypes = map[string]bool{"decimal": true, "unsignedDecimal": true, "float": true, "unsignedFloat": true, "double": true, "unsignedDouble": true}
+var dateTimeTypes = map[string]bool{"dateTime": true, "dateTimeTz": true, "time": true, "timeTz": true, "timestamp": true, "timestampTz": true}
+
+// ExportTable Export the structure of the table as a driver-neutral document, e.g.
+//    doc, err := builder.ExportTable("users")
+//    data, err := doc.YAML()
+// The table prefix is removed from the table names, the current time defaults are exported as NOW().
+func (builder *Builder) ExportTable(name string) (*TableDocument, error) {
+	table, err := builder.GetTable(name)
+	if err != nil {
+		return nil, err
+	}
+	return table.Get().document(), nil
+}
+
+// MustExportTable Export the structure of the table as a driver-neutral document.
+func (builder *Builder) MustExportTable(name string) *TableDocument {
+	doc, err := builder.ExportTable(name)
+	utils.PanicIF(err)
+	return doc
+}
+
+// ImportTable Create the table using the document, e.g.
+//    doc, err := schema.LoadTableDocument(data)
+//    err = builder.ImportTable(doc)
+func (builder *Builder) ImportTable(doc *TableDocument) error {
+	if doc.Name == "" {
+		return fmt.Errorf("the name of the table is required")
+	}
+	return builder.CreateTable(doc.Name, doc.define)
+}
+
+// MustImportTable Create the table using the document.
+func (builder *Builder) MustImportTable(doc *TableDocument) {
+	err := builder.ImportTable(doc)
+	utils.PanicIF(err)
+}
+
+// LoadTableDocument Parse the JSON or YAML document of the table structure.
+func LoadTableDocument(data []byte) (*TableDocument, error) {
+	doc := &TableDocument{}
+	var err error
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		err = json.Unmarshal(data, doc)
+	} else {
+		err = yaml.Unmarshal(data, doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// JSON Serialize the table document as JSON
+func (doc *TableDocument) JSON() ([]byte, error) {
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// YAML Serialize the table document as YAML
+func (doc *TableDocument) YAML() ([]byte, error) {
+	return yaml.Marshal(doc)
+}
+
+// define add the columns, indexes, primary key and foreign keys of the document to the table
+func (doc *TableDocument) define(blueprint Blueprint) {
+	table := blueprint.Get()
+	for _, col := range doc.Columns {
+		column := table.newColumn(col.Name).SetType(col.Type)
+		column.Length = col.Length
+		column.Precision = col.Precision
+		column.Scale = col.Scale
+		column.DateTimePrecision = col.DateTimePrecision
+		column.Default = col.Default
+		column.DefaultRaw = col.DefaultRaw
+		column.Nullable = col.Nullable
+		column.IsUnsigned = col.Unsigned
+		column.Option = col.Option
+		if col.AutoIncrement {
+			column.AutoIncrement()
+		}
+		if col.Comment != "" {
+			column.SetComment(col.Comment)
+		}
+		table.putColumn(column)
+	}
+
+	if len(doc.Primary) > 0 {
+		table.AddPrimary(doc.Primary...)
+	}
+
+	for _, index := range doc.Indexes {
+		switch index.Type {
+		case "unique":
+			table.AddUnique(index.Name, index.Columns...)
+		case "fulltext":
+			table.AddFulltext(index.Name, index.Columns...)
+		default:
+			table.AddIndex(index.Name, index.Columns...)
+		}
+	}
+
+	for _, foreign := range doc.Foreigns {
+		table.Foreign(foreign.Columns...).
+			References(foreign.References...).
+			On(foreign.Table).
+			OnDelete(foreign.OnDelete).
+			OnUpdate(foreign.OnUpdate)
+	}
+}
+
+// document get the driver-neutral document of the table
+func (table *Table) document() *TableDocument {
+	doc := &TableDocument{
+		Name:    table.Name,
+		Comment: table.Table.Comment,
+		Columns: []ColumnDocument{},
+	}
+
+	for _, column := range table.Table.Columns {
+		doc.Columns = append(doc.Columns, columnDocument(column))
+	}
+
+	for _, name := range table.IndexNames {
+		index := table.IndexMap[name]
+		if index.Type == "primary" || strings.HasPrefix(name, "sqlite_autoindex_") {
+			continue
+		}
+		doc.Indexes = append(doc.Indexes, IndexDocument{
+			Name:    name,
+			Type:    index.Type,
+			Columns: columnNames(index.Columns),
+		})
+	}
+
+	// the indexes and foreign keys are sorted, so that the document is stable across the drivers
+	sort.Slice(doc.Indexes, func(i, j int) bool { return doc.Indexes[i].Name < doc.Indexes[j].Name })
+
+	if table.Table.Primary != nil {
+		doc.Primary = columnNames(table.Table.Primary.Columns)
+	}
+
+	for _, name := range table.ForeignNames {
+		foreign := table.ForeignMap[name]
+		doc.Foreigns = append(doc.Foreigns, ForeignDocument{
+			Columns:    columnNames(foreign.Columns),
+			Table:      strings.TrimPrefix(foreign.ReferenceTableName, table.Prefix),
+			References: foreign.Foreign.References,
+			OnDelete:   foreign.Foreign.OnDelete,
+			OnUpdate:   foreign.Foreign.OnUpdate,
+		})
+	}
+	sort.Slice(doc.Foreigns, func(i, j int) bool {
+		return strings.Join(doc.Foreigns[i].Columns, ",") < strings.Join(doc.Foreigns[j].Columns, ",")
+	})
+
+	return doc
+}
+
+// columnDocument get the driver-neutral document of the column
+func columnDocument(column *dbal.Column) ColumnDocument {
+	doc := ColumnDocument{
+		Name:          column.Name,
+		Type:          column.Type,
+		Nullable:      column.Nullable,
+		Unsigned:      column.IsUnsigned,
+		AutoIncrement: utils.StringVal(column.Extra) != "",
+		Comment:       utils.StringVal(column.Comment),
+		Option:        column.Option,
+	}
+
+	if lengthTypes[column.Type] {
+		doc.Length = column.Length
+	}
+
+	if precisionTypes[column.Type] {
+		doc.Precision = column.Precision
+		doc.Scale = column.Scale
+	}
+
+	if dateTimeTypes[column.Type] {
+		doc.DateTimePrecision = column.DateTimePrecision
+	}
+
+	// the primary key columns are always not null
+	if column.Primary {
+		doc.Nullable = false
+	}
+
+	if !doc.AutoIncrement {
+		doc.Default, doc.DefaultRaw = documentDefault(column)
+	}
+
+	return doc
+}
+
+// documentDefault get the driver-neutral default value of the column.
+// The current time expressions are exported as NOW(), the other expressions are kept as they are.
+func documentDefault(column *dbal.Column) (interface{}, string) {
+	raw := column.DefaultRaw
+	if raw == "" {
+		switch v := column.Default.(type) {
+		case nil:
+			return nil, ""
+		case []byte:
+			raw = string(v)
+		default:
+			raw = fmt.Sprintf("%v", v)
+		}
+	}
+
+	expr := castPattern.ReplaceAllString(strings.TrimSpace(raw), "")
+	lower := strings.ToLower(expr)
+	if lower == "null" || lower == "" {
+		return nil, ""
+	}
+
+	if strings.Contains(lower, "now") || strings.Contains(lower, "current_timestamp") {
+		return nil, "NOW()"
+	}
+
+	if strings.Contains(strings.Trim(expr, "()"), "(") {
+		return nil, expr
+	}
+
+	return defaultValue(column), ""
+}
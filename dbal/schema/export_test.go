@@ -0,0 +1,122 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/unit"
+	"github.com/yaoapp/xun/utils"
+)
+
+func TestExportExportTable(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForExportTest()
+	doc := builder.MustExportTable("table_test_export")
+	assert.Equal(t, "table_test_export", doc.Name, "the name of the document should be table_test_export")
+	assert.Equal(t, []string{"id"}, doc.Primary, "the primary key should be id")
+
+	columns := map[string]ColumnDocument{}
+	for _, column := range doc.Columns {
+		columns[column.Name] = column
+	}
+	assert.True(t, columns["id"].AutoIncrement, "the id column should be auto-incrementing")
+	assert.Equal(t, 80, utils.IntVal(columns["name"].Length), "the length of the name column should be 80")
+	assert.Equal(t, "0", columns["vote"].Default, "the default value of the vote column should be 0")
+	assert.Equal(t, 8, utils.IntVal(columns["score"].Precision), "the precision of the score column should be 8")
+	assert.Equal(t, 2, utils.IntVal(columns["score"].Scale), "the scale of the score column should be 2")
+	assert.Equal(t, "NOW()", columns["created_at"].DefaultRaw, "the default value of the created_at column should be NOW()")
+	assert.True(t, columns["bio"].Nullable, "the bio column should be nullable")
+
+	indexes := map[string]IndexDocument{}
+	for _, index := range doc.Indexes {
+		indexes[index.Name] = index
+	}
+	assert.Equal(t, "unique", indexes["name_unique"].Type, "the name_unique index should be unique")
+	assert.Equal(t, []string{"name", "vote"}, indexes["name_vote"].Columns, "the columns of the name_vote index should be name and vote")
+}
+
+func TestExportImportTable(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForExportTest()
+	builder.MustDropTableIfExists("table_test_export_copy")
+
+	doc := builder.MustExportTable("table_test_export")
+	data, err := doc.YAML()
+	assert.Nil(t, err)
+
+	copy, err := LoadTableDocument(data)
+	assert.Nil(t, err)
+	copy.Name = "table_test_export_copy"
+	builder.MustImportTable(copy)
+
+	imported := builder.MustExportTable("table_test_export_copy")
+	imported.Name = "table_test_export"
+	assert.Equal(t, doc, imported, "the imported table should be the same as the exported one")
+}
+
+func TestExportLoadTableDocument(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForExportTest()
+	doc := builder.MustExportTable("table_test_export")
+
+	data, err := doc.JSON()
+	assert.Nil(t, err)
+	fromJSON, err := LoadTableDocument(data)
+	assert.Nil(t, err)
+	assert.Equal(t, doc, fromJSON, "the document should be the same after loading the JSON")
+
+	data, err = doc.YAML()
+	assert.Nil(t, err)
+	fromYAML, err := LoadTableDocument(data)
+	assert.Nil(t, err)
+	assert.Equal(t, doc, fromYAML, "the document should be the same after loading the YAML")
+
+	_, err = LoadTableDocument([]byte("name: [unclosed"))
+	assert.NotNil(t, err, "the invalid document should return an error")
+}
+
+func TestExportForeigns(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForConstraintTest(builder)
+	doc := builder.MustExportTable("table_test_constraint_post")
+	assert.Equal(t, 1, len(doc.Foreigns), "the document should have 1 foreign key")
+	if len(doc.Foreigns) == 1 {
+		assert.Equal(t, []string{"user_id"}, doc.Foreigns[0].Columns, "the columns of the foreign key should be user_id")
+		assert.Equal(t, "table_test_constraint_user", doc.Foreigns[0].Table, "the referenced table should be table_test_constraint_user")
+		assert.Equal(t, []string{"id"}, doc.Foreigns[0].References, "the referenced columns should be id")
+		assert.Equal(t, "CASCADE", doc.Foreigns[0].OnDelete, "the on delete action should be CASCADE")
+	}
+
+	builder.MustDropTableIfExists("table_test_export_post")
+	doc.Name = "table_test_export_post"
+	builder.MustImportTable(doc)
+	table := builder.MustGetTable("table_test_export_post")
+	assert.True(t, table.HasForeign("user_id_foreign"), "the foreign key should be imported")
+	builder.MustDropTableIfExists("table_test_export_post")
+}
+
+// clean the test data
+func TestExportClean(t *testing.T) {
+	builder := getTestBuilder()
+	builder.MustDropTableIfExists("table_test_export")
+	builder.MustDropTableIfExists("table_test_export_copy")
+}
+
+func NewTableForExportTest() {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.MustDropTableIfExists("table_test_export")
+	builder.MustCreateTable("table_test_export", func(table Blueprint) {
+		table.ID("id")
+		table.String("name", 80).Unique()
+		table.Integer("vote").SetDefault(0)
+		table.Decimal("score", 8, 2).Null()
+		table.Text("bio").Null()
+		table.AddIndex("name_vote", "name", "vote")
+		table.Timestamps()
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/yaoapp/xun/unit"
+	"github.com/yaoapp/xun/utils"
 )
 
 func TestIndexGetIndex(t *testing.T) {
@@ -79,9 +80,6 @@ func TestIndexAddUnique(t *testing.T) {
 }
 
 func TestIndexRenameIndex(t *testing.T) {
-	if unit.DriverIs("sqlite3") {
-		return
-	}
 	defer unit.Catch()
 	builder := getTestBuilder()
 	TestIndexAddIndex(t)
@@ -111,6 +109,55 @@ func TestIndexDropIndex(t *testing.T) {
 	assert.False(t, table.HasIndex("field1_field2"), "the table should have not the field1_field2 index")
 }
 
+func TestIndexRenameIndexWithoutRebuild(t *testing.T) {
+	if unit.DriverNot("sqlite3") {
+		return
+	}
+
+	defer unit.Catch()
+	builder := getTestBuilder()
+	TestIndexAddIndex(t)
+	origin := testIndexTableSQL(t, builder)
+	builder.MustAlterTable("table_test_index", func(table Blueprint) {
+		table.RenameIndex("field1_field2", "re_field1_field2")
+	})
+	table := builder.MustGetTable("table_test_index")
+	assert.True(t, table.HasIndex("re_field1_field2"), "the table should have the re_field1_field2 index")
+	assert.Equal(t, origin, testIndexTableSQL(t, builder), "the table should not be rebuilt")
+}
+
+func TestIndexUniqueConstraintRebuild(t *testing.T) {
+	if unit.DriverNot("sqlite3") {
+		return
+	}
+
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.MustDropTableIfExists("table_test_index")
+	db := builder.MustGetDB()
+	_, err := db.Exec("CREATE TABLE table_test_index (id INTEGER PRIMARY KEY AUTOINCREMENT, email VARCHAR(80) UNIQUE, name VARCHAR(80))")
+	assert.Nil(t, err, "the return error should be nil")
+	_, err = db.Exec("INSERT INTO table_test_index (email, name) VALUES ('john@yao.run', 'John')")
+	assert.Nil(t, err, "the return error should be nil")
+
+	builder.MustAlterTable("table_test_index", func(table Blueprint) {
+		table.String("name", 100)
+	})
+	table := builder.MustGetTable("table_test_index")
+	assert.Equal(t, 100, utils.IntVal(table.GetColumn("name").Length), "the length of the name column should be 100")
+	assert.True(t, table.HasIndex("sqlite_autoindex_table_test_index_1"), "the unique constraint should be kept")
+
+	_, err = db.Exec("INSERT INTO table_test_index (email, name) VALUES ('john@yao.run', 'Lee')")
+	assert.Error(t, err, "the unique constraint should be kept")
+}
+
+func testIndexTableSQL(t *testing.T, builder Schema) string {
+	sql := ""
+	err := builder.MustGetDB().Get(&sql, "SELECT `sql` FROM sqlite_master WHERE type='table' AND name='table_test_index'")
+	assert.Nil(t, err, "the return error should be nil")
+	return sql
+}
+
 // clean the test data
 func TestIndexClean(t *testing.T) {
 	builder := getTestBuilder()
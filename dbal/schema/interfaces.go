@@ -1,6 +1,8 @@
 package schema
 
 import (
+	"context"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun/dbal"
 )
@@ -10,6 +12,8 @@ type Schema interface {
 	SetOption(option *dbal.Option)
 
 	Builder() *Builder
+	WithContext(ctx context.Context) Schema
+	Context() context.Context
 	GetConnection() (*dbal.Connection, error)
 	GetDB() (*sqlx.DB, error)
 	GetVersion() (*dbal.Version, error)
@@ -23,6 +27,17 @@ type Schema interface {
 	HasTable(name string) (bool, error)
 	RenameTable(old string, new string) error
 	DropTableIfExists(name string) error
+	DiffTable(name string, define func(table Blueprint)) (*Diff, error)
+	SyncTable(name string, define func(table Blueprint)) error
+	PruneTable(name string, define func(table Blueprint)) error
+	ExportTable(name string) (*TableDocument, error)
+	ImportTable(doc *TableDocument) error
+
+	Begin() (Schema, error)
+	Commit() error
+	Rollback() error
+	Transaction(callback func(tx Schema) error) error
+	InTransaction() bool
 
 	MustGetConnection() *dbal.Connection
 	MustGetDB() *sqlx.DB
@@ -37,6 +52,16 @@ type Schema interface {
 	MustHasTable(name string) bool
 	MustRenameTable(old string, new string) Blueprint
 	MustDropTableIfExists(name string)
+	MustDiffTable(name string, define func(table Blueprint)) *Diff
+	MustSyncTable(name string, define func(table Blueprint))
+	MustPruneTable(name string, define func(table Blueprint))
+	MustExportTable(name string) *TableDocument
+	MustImportTable(doc *TableDocument)
+
+	MustBegin() Schema
+	MustCommit()
+	MustRollback()
+	MustTransaction(callback func(tx Schema) error)
 
 	DB() *sqlx.DB // alias MustGetDB
 }
@@ -53,6 +78,8 @@ type Blueprint interface {
 	GetColumns() map[string]*Column
 	GetIndexNames() []string
 	GetIndexes() map[string]*Index
+	GetForeignNames() []string
+	GetForeigns() map[string]*Foreign
 
 	// defined in column.go
 	GetColumn(name string) *Column
@@ -76,6 +103,10 @@ type Blueprint interface {
 
 	// defined in constraint.go
 	// @todo: GetUniqueConstraint, AddUniqueConstraint, DropUniqueConstraint
+	GetForeign(name string) *Foreign
+	HasForeign(name ...string) bool
+	Foreign(columnNames ...string) *Foreign
+	DropForeign(name ...string)
 
 	// defined in blueprint.go
 	// Character types
@@ -152,6 +183,11 @@ type Blueprint interface {
 	DropSoftDeletes()
 	DropSoftDeletesTz()
 
-	//@todo: morphs, nullableMorphs, uuidMorphs nullableUuidMorphs
+	// morphs, nullableMorphs, uuidMorphs, nullableUuidMorphs
+	Morphs(name string) map[string]*Column
+	NullableMorphs(name string) map[string]*Column
+	UUIDMorphs(name string) map[string]*Column
+	NullableUUIDMorphs(name string) map[string]*Column
+	DropMorphs(name string)
 
 }
@@ -60,9 +60,6 @@ func TestPrimaryGetPrimary(t *testing.T) {
 
 func TestPrimaryDropPrimary(t *testing.T) {
 	defer unit.Catch()
-	if unit.DriverIs("sqlite3") {
-		return
-	}
 	builder := getTestBuilderInstance()
 	TestPrimaryAddPrimary(t)
 	builder.MustAlterTable("table_test_primary", func(table Blueprint) {
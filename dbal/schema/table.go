@@ -25,14 +25,16 @@ func (table *Table) GetFullName() string {
 func NewTable(name string, builder *Builder) *Table {
 	tableName := fmt.Sprintf("%s%s", builder.Conn.Option.Prefix, name)
 	table := &Table{
-		Name:        name,
-		Prefix:      builder.Conn.Option.Prefix,
-		Table:       dbal.NewTable(tableName, builder.Schema, builder.Database),
-		Builder:     builder,
-		IndexNames:  []string{},
-		ColumnNames: []string{},
-		ColumnMap:   map[string]*Column{},
-		IndexMap:    map[string]*Index{},
+		Name:         name,
+		Prefix:       builder.Conn.Option.Prefix,
+		Table:        dbal.NewTable(tableName, builder.Schema, builder.Database),
+		Builder:      builder,
+		IndexNames:   []string{},
+		ColumnNames:  []string{},
+		ColumnMap:    map[string]*Column{},
+		IndexMap:     map[string]*Index{},
+		ForeignNames: []string{},
+		ForeignMap:   map[string]*Foreign{},
 	}
 	return table
 }
@@ -57,6 +59,16 @@ func (table *Table) GetIndexes() map[string]*Index {
 	return table.IndexMap
 }
 
+// GetForeignNames Get the foreign key names
+func (table *Table) GetForeignNames() []string {
+	return table.ForeignNames
+}
+
+// GetForeigns Get the foreign keys map of the table
+func (table *Table) GetForeigns() map[string]*Foreign {
+	return table.ForeignMap
+}
+
 // Get Get the DBAL table instance
 func (table *Table) Get() *Table {
 	return table
@@ -0,0 +1,103 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/yaoapp/xun/utils"
+)
+
+// Begin Start a new database transaction, returns a new schema builder bound to the transaction.
+// Notice: MySQL commits the transaction implicitly when a DDL statement is executed,
+// the transactional DDL only works with PostgreSQL and SQLite.
+func (builder *Builder) Begin() (Schema, error) {
+	if builder.Tx != nil {
+		return nil, fmt.Errorf("the schema builder is already in a transaction")
+	}
+
+	if builder.Conn == nil || builder.Conn.Write == nil {
+		return nil, fmt.Errorf("the connection is nil")
+	}
+
+	tx, err := builder.Conn.Write.BeginTxx(builder.Context(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	new := *builder
+	new.Tx = tx
+	new.Grammar = builder.Grammar.WithTx(tx)
+	return &new, nil
+}
+
+// MustBegin Start a new database transaction, returns a new schema builder bound to the transaction.
+func (builder *Builder) MustBegin() Schema {
+	tx, err := builder.Begin()
+	utils.PanicIF(err)
+	return tx
+}
+
+// Commit Commit the active database transaction.
+func (builder *Builder) Commit() error {
+	if builder.Tx == nil {
+		return fmt.Errorf("the schema builder is not in a transaction")
+	}
+	return builder.Tx.Commit()
+}
+
+// MustCommit Commit the active database transaction.
+func (builder *Builder) MustCommit() {
+	err := builder.Commit()
+	utils.PanicIF(err)
+}
+
+// Rollback Rollback the active database transaction.
+func (builder *Builder) Rollback() error {
+	if builder.Tx == nil {
+		return fmt.Errorf("the schema builder is not in a transaction")
+	}
+	return builder.Tx.Rollback()
+}
+
+// MustRollback Rollback the active database transaction.
+func (builder *Builder) MustRollback() {
+	err := builder.Rollback()
+	utils.PanicIF(err)
+}
+
+// Transaction Execute a Closure within a transaction.
+// The transaction is committed if the callback returns nil, otherwise it is rolled back.
+// If the callback panics, the transaction is rolled back and the panic is re-raised.
+func (builder *Builder) Transaction(callback func(tx Schema) error) error {
+	tx, err := builder.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	err = callback(tx)
+	if err != nil {
+		if errRollback := tx.Rollback(); errRollback != nil {
+			return fmt.Errorf("%s (rollback failed: %s)", err, errRollback)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MustTransaction Execute a Closure within a transaction.
+func (builder *Builder) MustTransaction(callback func(tx Schema) error) {
+	err := builder.Transaction(callback)
+	utils.PanicIF(err)
+}
+
+// InTransaction Determine if the schema builder is in a transaction.
+func (builder *Builder) InTransaction() bool {
+	return builder.Tx != nil
+}
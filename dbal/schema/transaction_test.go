@@ -0,0 +1,103 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestTransactionMustTransactionCommit(t *testing.T) {
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_transaction")
+	builder.MustTransaction(func(tx Schema) error {
+		assert.True(t, tx.InTransaction(), "the schema builder should be in a transaction")
+		tx.MustCreateTable("table_test_transaction", func(table Blueprint) {
+			table.ID("id")
+			table.String("name", 80)
+		})
+		return nil
+	})
+	assert.False(t, builder.InTransaction(), "the schema builder should not be in a transaction")
+	assert.True(t, builder.MustHasTable("table_test_transaction"), "the table should be created")
+	builder.DropTableIfExists("table_test_transaction")
+}
+
+func TestTransactionMustTransactionRollback(t *testing.T) {
+	if unit.DriverIs("mysql") {
+		return // MySQL commits the DDL statements implicitly
+	}
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_transaction")
+	err := builder.Transaction(func(tx Schema) error {
+		tx.MustCreateTable("table_test_transaction", func(table Blueprint) {
+			table.ID("id")
+		})
+		return fmt.Errorf("something wrong")
+	})
+	assert.Equal(t, "something wrong", err.Error(), "the error should be returned")
+	assert.False(t, builder.MustHasTable("table_test_transaction"), "the table should not be created")
+}
+
+func TestTransactionCommitError(t *testing.T) {
+	builder := getTestBuilder()
+	assert.Panics(t, func() { builder.MustCommit() })
+	assert.Panics(t, func() { builder.MustRollback() })
+
+	tx := builder.MustBegin()
+	defer tx.Rollback()
+	_, err := tx.Begin()
+	assert.Error(t, err, "the nested transaction should be failed")
+}
+
+func TestTransactionRebuildKeepRows(t *testing.T) {
+	if unit.DriverNot("sqlite3") {
+		return
+	}
+
+	dsn := unit.DSN() + "?_foreign_keys=on"
+	if strings.Contains(unit.DSN(), "?") {
+		dsn = unit.DSN() + "&_foreign_keys=on"
+	}
+	builder := New(unit.Driver(), dsn)
+	builder.MustDropTableIfExists("table_test_transaction_child")
+	builder.MustDropTableIfExists("table_test_transaction_parent")
+	defer builder.MustDropTableIfExists("table_test_transaction_parent")
+	defer builder.MustDropTableIfExists("table_test_transaction_child")
+	builder.MustCreateTable("table_test_transaction_parent", func(table Blueprint) {
+		table.ID("id")
+		table.String("name", 80)
+	})
+	builder.MustCreateTable("table_test_transaction_child", func(table Blueprint) {
+		table.ID("id")
+		table.ForeignID("parent_id")
+		table.Foreign("parent_id").References("id").On("table_test_transaction_parent").OnDelete("cascade")
+	})
+
+	db := builder.MustGetDB()
+	_, err := db.Exec("INSERT INTO table_test_transaction_parent (id, name) VALUES (1, 'p1'), (2, 'p2')")
+	assert.Nil(t, err, "the return error should be nil")
+	_, err = db.Exec("INSERT INTO table_test_transaction_child (parent_id) VALUES (1), (1), (2)")
+	assert.Nil(t, err, "the return error should be nil")
+
+	err = builder.Transaction(func(tx Schema) error {
+		return tx.AlterTable("table_test_transaction_parent", func(table Blueprint) {
+			table.String("name", 100)
+		})
+	})
+	assert.Error(t, err, "the table referenced by the others should not be rebuilt within a transaction")
+
+	count := 0
+	err = db.Get(&count, "SELECT COUNT(*) FROM table_test_transaction_child")
+	assert.Nil(t, err, "the return error should be nil")
+	assert.Equal(t, 3, count, "the rows of the child table should be kept")
+
+	builder.MustAlterTable("table_test_transaction_parent", func(table Blueprint) {
+		table.String("name", 100)
+	})
+	err = db.Get(&count, "SELECT COUNT(*) FROM table_test_transaction_child")
+	assert.Nil(t, err, "the return error should be nil")
+	assert.Equal(t, 3, count, "the rows of the child table should be kept")
+}
@@ -1,6 +1,8 @@
 package schema
 
 import (
+	"context"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun/dbal"
 )
@@ -19,6 +21,8 @@ type Builder struct {
 	Mode     string
 	Database string
 	Schema   string
+	Tx       *sqlx.Tx
+	Ctx      context.Context
 	dbal.Grammar
 }
 
@@ -27,12 +31,14 @@ type Table struct {
 	*dbal.Table
 	*Builder
 	*Primary
-	ColumnNames []string
-	ColumnMap   map[string]*Column
-	IndexNames  []string
-	IndexMap    map[string]*Index
-	Name        string
-	Prefix      string
+	ColumnNames  []string
+	ColumnMap    map[string]*Column
+	IndexNames   []string
+	IndexMap     map[string]*Index
+	ForeignNames []string
+	ForeignMap   map[string]*Foreign
+	Name         string
+	Prefix       string
 }
 
 // Column the table column struct
@@ -47,8 +53,71 @@ type Index struct {
 	Table *Table
 }
 
+// Foreign the table foreign key
+type Foreign struct {
+	*dbal.Foreign
+	Table *Table
+}
+
 // Primary the table primary key
 type Primary struct {
 	*dbal.Primary
 	Table *Table
 }
+
+// Diff the differences between a table definition and the live table
+type Diff struct {
+	Name           string
+	AddColumns     []*Column
+	ChangeColumns  []*Column
+	DropColumns    []string
+	CreateIndexes  []*Index
+	DropIndexes    []string
+	CreatePrimary  *Primary
+	DropPrimary    bool
+	CreateForeigns []*Foreign
+	DropForeigns   []string
+}
+
+// TableDocument the driver-neutral document of a table structure, it could be serialized as JSON or YAML.
+type TableDocument struct {
+	Name     string            `json:"name" yaml:"name"`
+	Comment  string            `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Columns  []ColumnDocument  `json:"columns" yaml:"columns"`
+	Indexes  []IndexDocument   `json:"indexes,omitempty" yaml:"indexes,omitempty"`
+	Primary  []string          `json:"primary,omitempty" yaml:"primary,omitempty"`
+	Foreigns []ForeignDocument `json:"foreigns,omitempty" yaml:"foreigns,omitempty"`
+}
+
+// ColumnDocument the column of the table document
+type ColumnDocument struct {
+	Name              string      `json:"name" yaml:"name"`
+	Type              string      `json:"type" yaml:"type"`
+	Length            *int        `json:"length,omitempty" yaml:"length,omitempty"`
+	Precision         *int        `json:"precision,omitempty" yaml:"precision,omitempty"`
+	Scale             *int        `json:"scale,omitempty" yaml:"scale,omitempty"`
+	DateTimePrecision *int        `json:"datetime_precision,omitempty" yaml:"datetime_precision,omitempty"`
+	Default           interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+	DefaultRaw        string      `json:"default_raw,omitempty" yaml:"default_raw,omitempty"`
+	Nullable          bool        `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	Unsigned          bool        `json:"unsigned,omitempty" yaml:"unsigned,omitempty"`
+	AutoIncrement     bool        `json:"auto_increment,omitempty" yaml:"auto_increment,omitempty"`
+	Comment           string      `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Option            []string    `json:"option,omitempty" yaml:"option,omitempty"`
+}
+
+// IndexDocument the index of the table document
+type IndexDocument struct {
+	Name    string   `json:"name" yaml:"name"`
+	Type    string   `json:"type" yaml:"type"`
+	Columns []string `json:"columns" yaml:"columns"`
+}
+
+// ForeignDocument the foreign key of the table document
+type ForeignDocument struct {
+	Columns    []string `json:"columns" yaml:"columns"`
+	Table      string   `json:"table" yaml:"table"`
+	References []string `json:"references" yaml:"references"`
+	OnDelete   string   `json:"on_delete,omitempty" yaml:"on_delete,omitempty"`
+	OnUpdate   string   `json:"on_update,omitempty" yaml:"on_update,omitempty"`
+}
@@ -25,9 +25,12 @@ type Config struct {
 
 // Option the database configuration
 type Option struct {
-	Prefix    string `json:"prefix,omitempty"` // Table prifix
-	Collation string `json:"collation,omitempty"`
-	Charset   string `json:"charset,omitempty"`
+	Prefix       string   `json:"prefix,omitempty"` // Table prifix
+	Collation    string   `json:"collation,omitempty"`
+	Charset      string   `json:"charset,omitempty"`
+	SoftDeletes  []string `json:"soft_deletes,omitempty"`  // The tables using soft deletes, the deleted_at column is used
+	Timestamps   []string `json:"timestamps,omitempty"`    // The tables maintaining the created_at and updated_at timestamps, * for all the tables
+	TimestampsTz []string `json:"timestamps_tz,omitempty"` // The tables maintaining the created_at and updated_at timestamps with the time zone, * for all the tables
 }
 
 // Version the database version
@@ -57,6 +60,8 @@ type Table struct {
 	IndexMap      map[string]*Index
 	Columns       []*Column
 	Indexes       []*Index
+	ForeignMap    map[string]*Foreign
+	Foreigns      []*Foreign
 	Commands      []*Command
 }
 
@@ -127,6 +132,22 @@ type Primary struct {
 	Columns   []*Column
 }
 
+// Foreign the table foreign key
+type Foreign struct {
+	DBName              string `db:"db_name"`
+	TableName           string `db:"table_name"`
+	Name                string `db:"foreign_name"`
+	ColumnName          string `db:"column_name"`
+	ReferenceTableName  string `db:"reference_table_name"`
+	ReferenceColumnName string `db:"reference_column_name"`
+	OnDelete            string `db:"on_delete"`
+	OnUpdate            string `db:"on_update"`
+	SEQ                 int    `db:"seq_in_foreign"`
+	Table               *Table
+	Columns             []*Column
+	References          []string
+}
+
 // Constraint the table constraint
 type Constraint struct {
 	SchemaName string
@@ -191,6 +212,14 @@ type Union struct {
 	Query *Query
 }
 
+// CTE the common table expression of the query (with / with recursive)
+type CTE struct {
+	Name      Name
+	Columns   []interface{}
+	Recursive bool
+	SQL       interface{} // *Query, Expression or string
+}
+
 // Aggregate An aggregate function and column to be run.
 type Aggregate struct {
 	Func    string        // AVG, COUNT, MIN, MAX, SUM
@@ -235,12 +264,35 @@ type Select struct {
 	Alias  string
 	Offset int
 	SQL    string
+	Args   []interface{} // The arguments of the window function
+	Window *Window       // The window of the window function
+}
+
+// Window the window specification of the window functions (over / window clause)
+type Window struct {
+	Name        string        // The name of the window, defined by the window clause
+	PartitionBy []interface{} // The partition columns
+	OrderBy     []interface{} // The order columns, e.g. "salary desc"
+	Frame       string        // The frame clause, e.g. rows between unbounded preceding and current row
+}
+
+// Conflict the conflict handling of the upsert statements
+type Conflict struct {
+	Constraint string // The constraint name of the conflict target, on conflict on constraint "name" (Postgres only)
+	Where      *Query // The constraints of the update branch, the conflicting rows not matching them are not updated
+}
+
+// copyFromRows the CopyFromSource of the rows in memory
+type copyFromRows struct {
+	rows  [][]interface{}
+	index int
 }
 
 // Query the query builder
 type Query struct {
 	UseWriteConnection bool                     // Whether to use write connection for the select. default is false
 	Lock               interface{}              //  Indicates whether row locking is being used.
+	CTEs               []CTE                    // The common table expressions of the query.
 	From               From                     // The table which the query is targeting.
 	Columns            []interface{}            // The columns that should be returned. (Name or Expression)
 	Aggregate          Aggregate                // An aggregate function and column to be run.
@@ -255,6 +307,13 @@ type Query struct {
 	Offset             int                      // The number of records to skip.
 	Groups             []interface{}            // The groupings for the query.
 	Havings            []Having                 // The having constraints for the query.
+	Windows            []Window                 // The named windows of the query.
+	Returning          []interface{}            // The columns returned by the insert, update, upsert and delete statements.
+	Conflict           Conflict                 // The conflict handling of the upsert statements.
+	SoftDeletes        string                   // The soft delete column, the trashed records are excluded from the query if it is set.
+	Trashed            string                   // The trashed records to query, with or only. default is excluding the trashed records.
+	WithoutScopes      []string                 // The global scopes removed from the query, * removes all of them.
+	Timestamps         string                   // The type of the timestamps maintained by the insert and update statements, timestamp or timestampTz.
 	Bindings           map[string][]interface{} // The current query value bindings.
 	Distinct           bool                     // Indicates if the query returns distinct results. Occasionally contains the columns that should be distinct. default is false
 	DistinctColumns    []interface{}            // Indicates if the query returns distinct results. Occasionally contains the columns that should be distinct.
@@ -13,6 +13,7 @@ require (
 	github.com/mattn/go-sqlite3 v1.14.6
 	github.com/stretchr/testify v1.7.1
 	github.com/yaoapp/kun v0.9.0
+	gopkg.in/yaml.v3 v3.0.0
 )
 
 require (
@@ -24,5 +25,4 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/sirupsen/logrus v1.8.1 // indirect
 	golang.org/x/sys v0.6.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0 // indirect
 )
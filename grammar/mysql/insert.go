@@ -1,14 +1,153 @@
 package mysql
 
 import (
+	"bufio"
+	"database/sql/driver"
+	"fmt"
+	"io"
 	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun/dbal"
 )
 
+// the sequence of the reader handlers registered by the ProcessCopyFrom method
+var copySequence uint64 = 0
+
+// the escaper of the values in the LOAD DATA statement
+var loadDataEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`, "\x00", `\0`)
+
 // CompileInsertOrIgnore Compile an insert ignore statement into SQL.
 func (grammarSQL MySQL) CompileInsertOrIgnore(query *dbal.Query, columns []interface{}, values [][]interface{}) (string, []interface{}) {
 	sql, bindings := grammarSQL.CompileInsert(query, columns, values)
 	sql = strings.Replace(sql, "insert", "insert ignore", 1)
 	return sql, bindings
 }
+
+// CompileReturning Compile the returning clause of the insert, update, upsert and delete statements.
+// MySQL does not support the returning clause, select the rows after writing instead.
+func (grammarSQL MySQL) CompileReturning(query *dbal.Query, columns []interface{}) (string, error) {
+	return "", fmt.Errorf("the returning clauses are not supported by mysql")
+}
+
+// ProcessCopyFrom Import the rows into the table using the LOAD DATA LOCAL INFILE statement within a transaction.
+// The rows are streamed to the server through a reader handler, the local_infile variable of the server should be ON.
+func (grammarSQL MySQL) ProcessCopyFrom(query *dbal.Query, columns []string, rows dbal.CopyFromSource) (int64, error) {
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("the columns of the rows are required")
+	}
+
+	names := []interface{}{}
+	for _, column := range columns {
+		names = append(names, column)
+	}
+
+	reader, writer := io.Pipe()
+	name := fmt.Sprintf("xun_copy_%d", atomic.AddUint64(&copySequence, 1))
+	mysql.RegisterReaderHandler(name, func() io.Reader { return reader })
+	defer mysql.DeregisterReaderHandler(name)
+
+	// write the rows as the tab-separated values
+	done := make(chan error, 1)
+	go func() {
+		err := loadDataWrite(writer, columns, rows, grammarSQL.Loc)
+		writer.CloseWithError(err)
+		done <- err
+	}()
+
+	sql := fmt.Sprintf(
+		"load data local infile 'Reader::%s' into table %s character set utf8mb4 fields terminated by '\\t' escaped by '\\\\' lines terminated by '\\n' (%s)",
+		name, grammarSQL.WrapTable(query.From), grammarSQL.Columnize(names),
+	)
+
+	var copied int64 = 0
+	err := grammarSQL.Transact(func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(grammarSQL.Context(), sql)
+		if err != nil {
+			return err
+		}
+		copied, err = res.RowsAffected()
+		return err
+	})
+
+	// stop writing if the server did not read all of the rows
+	reader.Close()
+	if errWrite := <-done; err == nil && errWrite != nil && errWrite != io.ErrClosedPipe {
+		err = errWrite
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	return copied, nil
+}
+
+// loadDataWrite write the rows to the writer in the format of the LOAD DATA statement, the time values are converted to the given location.
+func loadDataWrite(writer io.Writer, columns []string, rows dbal.CopyFromSource, loc *time.Location) error {
+	buf := bufio.NewWriter(writer)
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return err
+		}
+		if len(values) != len(columns) {
+			return fmt.Errorf("the row has %d values, but there are %d columns", len(values), len(columns))
+		}
+
+		for i, value := range values {
+			if i > 0 {
+				buf.WriteByte('\t')
+			}
+			field, err := loadDataValue(value, loc)
+			if err != nil {
+				return err
+			}
+			buf.WriteString(field)
+		}
+
+		if err := buf.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+// loadDataValue get the field of the value in the LOAD DATA statement, nil => \N
+// The time values are converted to the given location first, the same as the driver does for the bindings.
+func loadDataValue(value interface{}, loc *time.Location) (string, error) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return "", err
+		}
+		value = v
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return `\N`, nil
+	case []byte:
+		return loadDataEscaper.Replace(string(v)), nil
+	case string:
+		return loadDataEscaper.Replace(v), nil
+	case time.Time:
+		if loc != nil {
+			v = v.In(loc)
+		}
+		return v.Format("2006-01-02 15:04:05.999999"), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	default:
+		return loadDataEscaper.Replace(fmt.Sprintf("%v", v)), nil
+	}
+}
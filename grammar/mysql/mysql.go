@@ -1,7 +1,9 @@
 package mysql
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/go-sql-driver/mysql"
@@ -15,6 +17,7 @@ import (
 // MySQL the MySQL Grammar
 type MySQL struct {
 	sql.SQL
+	Loc *time.Location // The location of the time values, the loc parameter of the DSN
 }
 
 func init() {
@@ -40,9 +43,18 @@ func (grammarSQL *MySQL) setup(db *sqlx.DB, config *dbal.Config, option *dbal.Op
 	}
 	grammarSQL.DatabaseName = cfg.DBName
 	grammarSQL.SchemaName = grammarSQL.DatabaseName
+	grammarSQL.Loc = cfg.Loc
 	return nil
 }
 
+// GetLocation get the location of the date and time values without the time zone, the loc parameter of the DSN, UTC by default
+func (grammarSQL MySQL) GetLocation() *time.Location {
+	if grammarSQL.Loc == nil {
+		return time.UTC
+	}
+	return grammarSQL.Loc
+}
+
 // NewWith Create a new grammar interface, using the given *sqlx.DB, *dbal.Config and *dbal.Option.
 func (grammarSQL MySQL) NewWith(db *sqlx.DB, config *dbal.Config, option *dbal.Option) (dbal.Grammar, error) {
 	err := grammarSQL.setup(db, config, option)
@@ -66,6 +78,18 @@ func (grammarSQL MySQL) NewWithRead(write *sqlx.DB, writeConfig *dbal.Config, re
 	return grammarSQL, nil
 }
 
+// WithTx Create a new grammar interface, the statements will be executed on the given transaction.
+func (grammarSQL MySQL) WithTx(tx *sqlx.Tx) dbal.Grammar {
+	grammarSQL.Tx = tx
+	return grammarSQL
+}
+
+// WithContext Create a new grammar interface, the statements will be executed with the given context.
+func (grammarSQL MySQL) WithContext(ctx context.Context) dbal.Grammar {
+	grammarSQL.Ctx = ctx
+	return grammarSQL
+}
+
 // OnConnected the event will be triggered when db server was connected
 func (grammarSQL MySQL) OnConnected() error {
 	version, err := grammarSQL.GetVersion()
@@ -3,12 +3,25 @@ package mysql
 import (
 	"fmt"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/yaoapp/xun/dbal"
 )
 
+// the references to the columns of the incoming rows, e.g. values(`version`)
+var excludedPattern = regexp.MustCompile("values\\(`[^`]*`\\)")
+
 // CompileUpsert Upsert new records or update the existing ones.
+// The conflicting rows are skipped if there are no update values, any unique key is the conflict target.
+// The conflicting rows are skipped by assigning the first column to itself, e.g. on duplicate key update `id`=`id`,
+// MySQL counts the skipped rows as affected if the clientFoundRows parameter of the DSN is true.
+// The constraints of the update branch are repeated in each assignment, e.g.
+//    on duplicate key update `name`=if(`version` < values(`version`), values(`name`), `name`), `version`=if(`version` < values(`version`), values(`version`), `version`)
+// MySQL evaluates the assignments from left to right, the later constraints get the values assigned by the former assignments,
+// so the columns referenced by the constraints are assigned last. If more than one of the updated columns are referenced by them,
+// only the last one is guaranteed to be compared with the original value.
 func (grammarSQL MySQL) CompileUpsert(query *dbal.Query, columns []interface{}, values [][]interface{}, uniqueBy []interface{}, updateValues interface{}) (string, []interface{}) {
 
 	if len(values) == 0 {
@@ -19,25 +32,73 @@ func (grammarSQL MySQL) CompileUpsert(query *dbal.Query, columns []interface{},
 	sql = fmt.Sprintf("%s on duplicate key update", sql)
 	offset := len(bindings)
 
+	where := ""
+	whereBindings := []interface{}{}
+	if query.Conflict.Where != nil && len(query.Conflict.Where.Wheres) > 0 {
+		where = strings.TrimPrefix(grammarSQL.CompileWheres(query.Conflict.Where, query.Conflict.Where.Wheres, &offset), "where ")
+		whereBindings = query.Conflict.Where.GetBindings("where")
+	}
+
 	update := reflect.ValueOf(updateValues)
 	kind := update.Kind()
-	segments := []string{}
+	assignments := []upsertAssignment{}
 	if kind == reflect.Array || kind == reflect.Slice {
 		for i := 0; i < update.Len(); i++ {
 			column := fmt.Sprintf("%v", update.Index(i).Interface())
-			segments = append(segments, fmt.Sprintf("%s=values(%s)", grammarSQL.Wrap(column), grammarSQL.Wrap(column)))
+			assignments = append(assignments, upsertAssignment{column: column, value: grammarSQL.CompileExcluded(column)})
 		}
 	} else if kind == reflect.Map {
 		for _, key := range update.MapKeys() {
 			column := fmt.Sprintf("%v", key)
 			value := update.MapIndex(key).Interface()
-			segments = append(segments, fmt.Sprintf("%s=%s", grammarSQL.Wrap(column), grammarSQL.Parameter(value, offset)))
+			assignment := upsertAssignment{column: column, value: grammarSQL.Parameter(value, offset)}
 			if !dbal.IsExpression(value) {
-				bindings = append(bindings, value)
+				assignment.bindings = []interface{}{value}
 				offset++
 			}
+			assignments = append(assignments, assignment)
+		}
+	}
+
+	// do nothing
+	if len(assignments) == 0 {
+		column := grammarSQL.Wrap(fmt.Sprintf("%v", columns[0]))
+		return fmt.Sprintf("%s %s=%s", sql, column, column), bindings
+	}
+
+	if where == "" {
+		segments := []string{}
+		for _, assignment := range assignments {
+			segments = append(segments, fmt.Sprintf("%s=%s", grammarSQL.Wrap(assignment.column), assignment.value))
+			bindings = append(bindings, assignment.bindings...)
 		}
+		return fmt.Sprintf("%s %s", sql, strings.Join(segments, ", ")), bindings
 	}
 
+	// assign the columns referenced by the constraints last, the references to the incoming rows are not counted
+	referenced := excludedPattern.ReplaceAllString(where, "")
+	sort.SliceStable(assignments, func(i, j int) bool {
+		return !strings.Contains(referenced, grammarSQL.ID(assignments[i].column)) && strings.Contains(referenced, grammarSQL.ID(assignments[j].column))
+	})
+
+	segments := []string{}
+	for _, assignment := range assignments {
+		column := grammarSQL.Wrap(assignment.column)
+		segments = append(segments, fmt.Sprintf("%s=if(%s, %s, %s)", column, where, assignment.value, column))
+		bindings = append(bindings, whereBindings...)
+		bindings = append(bindings, assignment.bindings...)
+	}
 	return fmt.Sprintf("%s %s", sql, strings.Join(segments, ", ")), bindings
 }
+
+// upsertAssignment the assignment of the update branch of the upsert statements
+type upsertAssignment struct {
+	column   string
+	value    string
+	bindings []interface{}
+}
+
+// CompileExcluded Compile the reference to the column of the row proposed for insertion in the upsert statements, e.g. values(`version`)
+func (grammarSQL MySQL) CompileExcluded(column string) string {
+	return fmt.Sprintf("values(%s)", grammarSQL.Wrap(column))
+}
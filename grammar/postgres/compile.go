@@ -7,6 +7,7 @@ import (
 
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/grammar/sql"
 )
 
 // CompileSelect Compile a select query into SQL.
@@ -34,6 +35,10 @@ func (grammarSQL Postgres) CompileSelectOffset(query *dbal.Query, offset *int) s
 
 	sqls := map[string]string{}
 
+	// The common table expressions are compiled first, so that the binding offsets
+	// of them are in front of the other components.
+	with := grammarSQL.CompileCTEs(query, query.CTEs, offset, grammarSQL.CompileSelectOffset)
+
 	// If the query does not have any columns set, we'll set the columns to the
 	// * character to just get all of the columns from the database. Then we
 	// can build the query and concatenate all the pieces together as one.
@@ -52,13 +57,14 @@ func (grammarSQL Postgres) CompileSelectOffset(query *dbal.Query, offset *int) s
 	sqls["wheres"] = grammarSQL.CompileWheres(query, query.Wheres, offset)
 	sqls["groups"] = grammarSQL.CompileGroups(query, query.Groups, offset)
 	sqls["havings"] = grammarSQL.CompileHavings(query, query.Havings, offset)
+	sqls["windows"] = grammarSQL.CompileWindows(query, query.Windows, offset)
 	sqls["orders"] = grammarSQL.CompileOrders(query, query.Orders, offset)
 	sqls["limit"] = grammarSQL.CompileLimit(query, query.Limit, offset)
 	sqls["offset"] = grammarSQL.CompileOffset(query, query.Offset)
 	sqls["lock"] = grammarSQL.CompileLock(query, query.Lock)
 
 	sql := ""
-	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "orders", "limit", "offset", "lock"} {
+	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "windows", "orders", "limit", "offset", "lock"} {
 		segment, has := sqls[name]
 		if has && segment != "" {
 			sql = sql + segment + " "
@@ -70,6 +76,10 @@ func (grammarSQL Postgres) CompileSelectOffset(query *dbal.Query, offset *int) s
 		sql = fmt.Sprintf("%s %s", grammarSQL.WrapUnion(sql), grammarSQL.CompileUnions(query, query.Unions, offset))
 	}
 
+	if with != "" {
+		sql = fmt.Sprintf("%s %s", with, sql)
+	}
+
 	// reset columns
 	query.Columns = columns
 	return strings.Trim(sql, " ")
@@ -92,7 +102,7 @@ func (grammarSQL Postgres) CompileColumns(query *dbal.Query, columns []interface
 		sql = "select distinct"
 	}
 
-	sql = fmt.Sprintf("%s %s", sql, grammarSQL.Columnize(columns))
+	sql = fmt.Sprintf("%s %s", sql, grammarSQL.Columnize(grammarSQL.CompileWindowColumns(columns)))
 
 	for _, col := range columns {
 		switch col.(type) {
@@ -193,6 +203,58 @@ func (grammarSQL Postgres) WhereDateBased(typ string, query *dbal.Query, where d
 	return fmt.Sprintf("extract(%s from %s)%s%s", typ, grammarSQL.Wrap(where.Column), where.Operator, value)
 }
 
+// WhereJSONContains Compile a "where JSON contains" clause.
+func (grammarSQL Postgres) WhereJSONContains(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	column, keys, _ := sql.ParseJSONSelector(fmt.Sprintf("%v", where.Column))
+	*bindingOffset = *bindingOffset + where.Offset
+	value := grammarSQL.Parameter(where.Value, *bindingOffset)
+
+	clause := fmt.Sprintf("(%s)::jsonb @> %s", jsonSelector(grammarSQL.Quoter, column, keys, false), value)
+	if where.Not {
+		return fmt.Sprintf("not %s", clause)
+	}
+	return clause
+}
+
+// WhereJSONLength Compile a "where JSON length" clause.
+func (grammarSQL Postgres) WhereJSONLength(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	column, keys, _ := sql.ParseJSONSelector(fmt.Sprintf("%v", where.Column))
+	value := ""
+	if !dbal.IsExpression(where.Value) {
+		*bindingOffset = *bindingOffset + where.Offset
+		value = grammarSQL.Parameter(where.Value, *bindingOffset)
+	} else {
+		value = where.Value.(dbal.Expression).GetValue()
+	}
+	return fmt.Sprintf("jsonb_array_length((%s)::jsonb) %s %s", jsonSelector(grammarSQL.Quoter, column, keys, false), where.Operator, value)
+}
+
+// WhereJSONContainsKey Compile a "where JSON contains key" clause.
+// The array index key is checked using the length of the array, e.g. options->languages[1] => jsonb_array_length("options"->'languages') > 1
+func (grammarSQL Postgres) WhereJSONContainsKey(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	column, keys, _ := sql.ParseJSONSelector(fmt.Sprintf("%v", where.Column))
+	if len(keys) == 0 && where.Not {
+		return "false = true"
+	} else if len(keys) == 0 {
+		return "true = true"
+	}
+
+	parent := jsonSelector(grammarSQL.Quoter, column, keys[:len(keys)-1], false)
+	key := keys[len(keys)-1]
+	clause := fmt.Sprintf("coalesce(jsonb_exists((%s)::jsonb, %s), false)", parent, grammarSQL.VAL(key))
+	if strings.HasPrefix(key, "[") {
+		clause = fmt.Sprintf(
+			"case when jsonb_typeof((%s)::jsonb) = 'array' then jsonb_array_length((%s)::jsonb) > %s else false end",
+			parent, parent, strings.Trim(key, "[]"),
+		)
+	}
+
+	if where.Not {
+		return fmt.Sprintf("not %s", clause)
+	}
+	return clause
+}
+
 // CompileLock the lock into SQL.
 func (grammarSQL Postgres) CompileLock(query *dbal.Query, lock interface{}) string {
 	lockType, ok := lock.(string)
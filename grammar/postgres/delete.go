@@ -7,6 +7,9 @@ import (
 )
 
 // CompileDelete  Compile a delete statement into SQL.
+// The inner joins are compiled to the using clause, and the conditions of them are moved to the where clause, e.g.
+//    delete from "orders" using "users" where ("users"."id" = "orders"."user_id") and ("users"."banned" = $1)
+// The other joins and the limit are compiled to a subquery selecting the ctid of the rows.
 func (grammarSQL Postgres) CompileDelete(query *dbal.Query) (string, []interface{}) {
 
 	if len(query.Joins) == 0 && query.Limit < 0 {
@@ -17,13 +20,14 @@ func (grammarSQL Postgres) CompileDelete(query *dbal.Query) (string, []interface
 	bindings := []interface{}{}
 	table := grammarSQL.WrapTable(query.From)
 
-	alias := query.From.Alias
-	if alias != "" {
-		query.Columns = []interface{}{fmt.Sprintf("%s.ctid", alias)}
-	} else {
-		query.Columns = []interface{}{"ctid"}
+	if fromJoins(query) {
+		using, wheres := grammarSQL.compileFromJoins(query, &offset)
+		bindings = append(bindings, query.GetBindings("join")...)
+		bindings = append(bindings, query.GetBindings("where")...)
+		return fmt.Sprintf("delete from %s using %s %s", table, using, wheres), bindings
 	}
 
+	query.Columns = []interface{}{grammarSQL.ctid(query)}
 	selectSQL := grammarSQL.CompileSelectOffset(query, &offset)
 
 	bindings = append(bindings, query.GetBindings()...)
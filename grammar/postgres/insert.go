@@ -2,7 +2,10 @@ package postgres
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/yaoapp/xun/dbal"
 )
 
@@ -23,9 +26,69 @@ func (grammarSQL Postgres) CompileInsertGetID(query *dbal.Query, columns []inter
 // ProcessInsertGetID Execute an insert and get ID statement and return the id
 func (grammarSQL Postgres) ProcessInsertGetID(sql string, bindings []interface{}, sequence string) (int64, error) {
 	var seq int64
-	err := grammarSQL.DB.Get(&seq, sql, bindings...)
+	err := grammarSQL.Executor().GetContext(grammarSQL.Context(), &seq, sql, bindings...)
 	if err != nil {
 		return 0, err
 	}
 	return seq, nil
 }
+
+// ProcessCopyFrom Import the rows into the table using the COPY ... FROM STDIN statement within a transaction.
+// The table is copied in the schema of the connection (the search_path of the DSN), unless it is given as schema.table.
+func (grammarSQL Postgres) ProcessCopyFrom(query *dbal.Query, columns []string, rows dbal.CopyFromSource) (int64, error) {
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("the columns of the rows are required")
+	}
+
+	table := ""
+	switch name := query.From.Name.(type) {
+	case dbal.Name:
+		table = name.Fullname()
+	case string:
+		table = name
+	default:
+		return 0, fmt.Errorf("the table of the copy statement should be a name, %#v given", query.From.Name)
+	}
+
+	schema := grammarSQL.SchemaName
+	if pos := strings.Index(table, "."); pos > 0 {
+		schema, table = table[:pos], table[pos+1:]
+	}
+
+	var copied int64 = 0
+	err := grammarSQL.Transact(func(tx *sqlx.Tx) error {
+		stmt, err := tx.PrepareContext(grammarSQL.Context(), pq.CopyInSchema(schema, table, columns...))
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for rows.Next() {
+			values, err := rows.Values()
+			if err != nil {
+				return err
+			}
+			if len(values) != len(columns) {
+				return fmt.Errorf("the row has %d values, but there are %d columns", len(values), len(columns))
+			}
+			_, err = stmt.ExecContext(grammarSQL.Context(), values...)
+			if err != nil {
+				return err
+			}
+			copied++
+		}
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		// flush the buffered rows
+		_, err = stmt.ExecContext(grammarSQL.Context())
+		return err
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return copied, nil
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"path/filepath"
@@ -86,6 +87,18 @@ func (grammarSQL Postgres) NewWithRead(write *sqlx.DB, writeConfig *dbal.Config,
 	return grammarSQL, nil
 }
 
+// WithTx Create a new grammar interface, the statements will be executed on the given transaction.
+func (grammarSQL Postgres) WithTx(tx *sqlx.Tx) dbal.Grammar {
+	grammarSQL.Tx = tx
+	return grammarSQL
+}
+
+// WithContext Create a new grammar interface, the statements will be executed with the given context.
+func (grammarSQL Postgres) WithContext(ctx context.Context) dbal.Grammar {
+	grammarSQL.Ctx = ctx
+	return grammarSQL
+}
+
 // New Create a new mysql grammar inteface
 func New() dbal.Grammar {
 	pg := Postgres{
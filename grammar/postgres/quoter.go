@@ -62,12 +62,46 @@ func (quoter *Quoter) Wrap(value interface{}) string {
 		}
 		return fmt.Sprintf("%s ", col.SQL)
 	case string:
+		if sql.IsJSONSelector(value.(string)) {
+			return quoter.WrapJSONSelector(value.(string))
+		}
 		return quoter.WrapAliasedValue(value.(string))
 	default:
 		return fmt.Sprintf("%v", value)
 	}
 }
 
+// WrapJSONSelector Wrap the given JSON selector, e.g.
+//    preferences->dining->meal  => "preferences"->'dining'->'meal'
+//    preferences->dining->>meal => "preferences"->'dining'->>'meal'
+func (quoter *Quoter) WrapJSONSelector(value string) string {
+	selector, alias := sql.SplitAlias(value)
+	column, keys, text := sql.ParseJSONSelector(selector)
+	res := jsonSelector(quoter, column, keys, text)
+	if alias != "" {
+		return fmt.Sprintf("%s as %s", res, quoter.ID(alias))
+	}
+	return res
+}
+
+// jsonSelector get the selector of the given JSON keys, the last key is selected as text if text is true.
+// The array indexes are selected as integers, e.g. options, [languages, [0]] => "options"->'languages'->0
+func jsonSelector(quoter dbal.Quoter, column string, keys []string, text bool) string {
+	res := quoter.Wrap(column)
+	for i, key := range keys {
+		operator := "->"
+		if text && i == len(keys)-1 {
+			operator = "->>"
+		}
+		if strings.HasPrefix(key, "[") {
+			res = fmt.Sprintf("%s%s%s", res, operator, strings.Trim(key, "[]"))
+			continue
+		}
+		res = fmt.Sprintf("%s%s%s", res, operator, quoter.VAL(key))
+	}
+	return res
+}
+
 // WrapAliasedValue Wrap a value that has an alias.
 func (quoter *Quoter) WrapAliasedValue(value string) string {
 	if value == "*" {
@@ -11,12 +11,17 @@ import (
 	"github.com/yaoapp/xun/utils"
 )
 
-// GetVersion get the version of the connection database
+// GetVersion get the version of the connection database, the version is cached per connection.
 func (grammarSQL Postgres) GetVersion() (*dbal.Version, error) {
+	return grammarSQL.CacheVersion(grammarSQL.queryVersion)
+}
+
+// queryVersion query the version of the connection database
+func (grammarSQL Postgres) queryVersion() (*dbal.Version, error) {
 	sql := fmt.Sprintf("SELECT VERSION()")
 	// defer logger.Debug(logger.RETRIEVE, sql).TimeCost(time.Now())
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &rows, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +77,7 @@ func (grammarSQL Postgres) GetTables() ([]string, error) {
 	)
 	defer log.Debug(sql)
 	tables := []string{}
-	err := grammarSQL.DB.Select(&tables, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &tables, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +94,7 @@ func (grammarSQL Postgres) TableExists(name string) (bool, error) {
 	)
 	defer log.Debug(sql)
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &rows, sql)
 	if err != nil {
 		return false, err
 	}
@@ -112,7 +117,7 @@ func (grammarSQL Postgres) CreateType(table *dbal.Table, types map[string][]stri
 	END $$;
 	`, table.SchemaName, name, typ)
 		defer log.Debug(typeSQL)
-		_, err := grammarSQL.DB.Exec(typeSQL)
+		_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context(), typeSQL)
 		if err != nil {
 			return err
 		}
@@ -130,6 +135,7 @@ func (grammarSQL Postgres) CreateTable(table *dbal.Table) error {
 	var primary *dbal.Primary = nil
 	columns := []*dbal.Column{}
 	indexes := []*dbal.Index{}
+	foreigns := []*dbal.Foreign{}
 	cbCommands := []*dbal.Command{}
 	// Commands
 	// The commands must be:
@@ -140,6 +146,7 @@ func (grammarSQL Postgres) CreateTable(table *dbal.Table) error {
 	//    CreateIndex(index *Index) for creating a index
 	//    DropIndex( name string) for  dropping a index
 	//    RenameIndex(old string,new string)  for renaming a index
+	//    CreateForeign(foreign *Foreign) for creating a foreign key
 	for _, command := range table.Commands {
 		switch command.Name {
 		case "AddColumn":
@@ -154,6 +161,10 @@ func (grammarSQL Postgres) CreateTable(table *dbal.Table) error {
 			primary = command.Params[0].(*dbal.Primary)
 			cbCommands = append(cbCommands, command)
 			break
+		case "CreateForeign":
+			foreigns = append(foreigns, command.Params[0].(*dbal.Foreign))
+			cbCommands = append(cbCommands, command)
+			break
 		}
 	}
 
@@ -166,12 +177,18 @@ func (grammarSQL Postgres) CreateTable(table *dbal.Table) error {
 	if primary != nil {
 		stmts = append(stmts, grammarSQL.SQLAddPrimary(primary))
 	}
+
+	// Foreign keys
+	for _, foreign := range foreigns {
+		stmts = append(stmts, grammarSQL.SQLAddForeign(foreign))
+	}
+
 	sql = sql + strings.Join(stmts, ",\n")
 	sql = sql + fmt.Sprintf("\n)")
 
 	// Create table
 	defer log.Debug(sql)
-	_, err = grammarSQL.DB.Exec(sql)
+	_, err = grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql)
 	if err != nil {
 		return err
 	}
@@ -235,7 +252,7 @@ func (grammarSQL Postgres) createTableCreateIndex(table *dbal.Table, indexes []*
 	if len(indexStmts) > 0 {
 		sql := strings.Join(indexStmts, ";\n")
 		defer log.Debug(sql)
-		_, err := grammarSQL.DB.Exec(sql)
+		_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql)
 		return err
 	}
 	return nil
@@ -245,7 +262,7 @@ func (grammarSQL Postgres) createTableAddComment(table *dbal.Table, commentStmts
 	if len(commentStmts) > 0 {
 		sql := strings.Join(commentStmts, ";\n")
 		defer log.Debug(sql)
-		_, err := grammarSQL.DB.Exec(sql)
+		_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql)
 		return err
 	}
 	return nil
@@ -255,7 +272,7 @@ func (grammarSQL Postgres) createTableAddComment(table *dbal.Table, commentStmts
 func (grammarSQL Postgres) RenameTable(old string, new string) error {
 	sql := fmt.Sprintf("ALTER TABLE %s RENAME TO %s", grammarSQL.ID(old), grammarSQL.ID(new))
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql)
 	return err
 }
 
@@ -279,6 +296,10 @@ func (grammarSQL Postgres) GetTable(name string) (*dbal.Table, error) {
 	if err != nil {
 		return nil, err
 	}
+	foreigns, err := grammarSQL.GetForeignListing(table.SchemaName, table.TableName)
+	if err != nil {
+		return nil, err
+	}
 
 	primaryKeyName := ""
 
@@ -288,6 +309,23 @@ func (grammarSQL Postgres) GetTable(name string) (*dbal.Table, error) {
 		table.PushColumn(column)
 	}
 
+	// attaching foreign keys
+	for i := range foreigns {
+		fk := foreigns[i]
+		if !table.HasColumn(fk.ColumnName) {
+			return nil, fmt.Errorf("the column %s does not exists", fk.ColumnName)
+		}
+		if !table.HasForeign(fk.Name) {
+			foreign := *fk
+			foreign.Columns = []*dbal.Column{}
+			foreign.References = []string{}
+			table.PushForeign(&foreign)
+		}
+		foreign := table.ForeignMap[fk.Name]
+		foreign.AddColumn(table.ColumnMap[fk.ColumnName])
+		foreign.References = append(foreign.References, fk.ReferenceColumnName)
+	}
+
 	// attaching indexes
 	for i := range indexes {
 		idx := indexes[i]
@@ -344,6 +382,8 @@ func (grammarSQL Postgres) AlterTable(table *dbal.Table) error {
 	//    CreateIndex(index *Index) for creating a index
 	//    DropIndex(name string) for  dropping a index
 	//    RenameIndex(old string,new string)  for renaming a index
+	//    CreateForeign(foreign *Foreign) for creating a foreign key
+	//    DropForeign(name string) for dropping a foreign key
 	for _, command := range table.Commands {
 		switch command.Name {
 		case "AddColumn":
@@ -373,6 +413,12 @@ func (grammarSQL Postgres) AlterTable(table *dbal.Table) error {
 		case "DropPrimary":
 			grammarSQL.alterTableDropPrimary(table, command, sql, &stmts, &errs)
 			break
+		case "CreateForeign":
+			grammarSQL.alterTableCreateForeign(table, command, sql, &stmts, &errs)
+			break
+		case "DropForeign":
+			grammarSQL.alterTableDropForeign(table, command, sql, &stmts, &errs)
+			break
 		}
 	}
 
@@ -547,9 +593,31 @@ func (grammarSQL Postgres) alterTableRenameIndex(table *dbal.Table, command *dba
 	command.Callback(err)
 }
 
+func (grammarSQL Postgres) alterTableCreateForeign(table *dbal.Table, command *dbal.Command, sql string, stmts *[]string, errs *[]error) {
+	foreign := command.Params[0].(*dbal.Foreign)
+	stmt := "ADD " + grammarSQL.SQLAddForeign(foreign)
+	*stmts = append(*stmts, sql+stmt)
+	err := grammarSQL.ExecSQL(table, sql+stmt)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("CreateForeign: %s", err))
+	}
+	command.Callback(err)
+}
+
+func (grammarSQL Postgres) alterTableDropForeign(table *dbal.Table, command *dbal.Command, sql string, stmts *[]string, errs *[]error) {
+	name := fmt.Sprintf("%s_%s", table.TableName, command.Params[0])
+	stmt := fmt.Sprintf("DROP CONSTRAINT %s", grammarSQL.ID(name))
+	*stmts = append(*stmts, sql+stmt)
+	err := grammarSQL.ExecSQL(table, sql+stmt)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("DropForeign: %s", err))
+	}
+	command.Callback(err)
+}
+
 // ExecSQL execute sql then update table structure
 func (grammarSQL Postgres) ExecSQL(table *dbal.Table, sql string) error {
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql)
 	if err != nil {
 		return err
 	}
@@ -680,7 +748,7 @@ func (grammarSQL Postgres) GetIndexListing(dbName string, tableName string) ([]*
 	)
 	defer log.Debug(sql)
 	indexes := []*dbal.Index{}
-	err := grammarSQL.DB.Select(&indexes, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &indexes, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -700,6 +768,60 @@ func (grammarSQL Postgres) GetIndexListing(dbName string, tableName string) ([]*
 	return indexes, nil
 }
 
+// GetForeignListing get a table foreign keys structure
+func (grammarSQL Postgres) GetForeignListing(dbName string, tableName string) ([]*dbal.Foreign, error) {
+	actions := `CASE %s
+			WHEN 'c' THEN 'CASCADE'
+			WHEN 'n' THEN 'SET NULL'
+			WHEN 'd' THEN 'SET DEFAULT'
+			WHEN 'r' THEN 'RESTRICT'
+			ELSE 'NO ACTION'
+		END`
+	selectColumns := []string{
+		"n.nspname as db_name",
+		"t.relname as table_name",
+		"c.conname as foreign_name",
+		"a.attname as column_name",
+		"rt.relname as reference_table_name",
+		"ra.attname as reference_column_name",
+		fmt.Sprintf(actions, "c.confdeltype") + " as on_delete",
+		fmt.Sprintf(actions, "c.confupdtype") + " as on_update",
+		"k.seq as seq_in_foreign",
+	}
+	sql := fmt.Sprintf(`
+			SELECT %s
+			FROM
+				pg_constraint c
+				INNER JOIN pg_class t ON t.oid = c.conrelid
+				INNER JOIN pg_namespace n ON n.oid = t.relnamespace
+				INNER JOIN pg_class rt ON rt.oid = c.confrelid
+				CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, refattnum, seq)
+				INNER JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+				INNER JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = k.refattnum
+			WHERE
+				c.contype = 'f'
+				and n.nspname = %s
+				and t.relname = %s
+			ORDER BY
+				c.conname, k.seq
+			`,
+		strings.Join(selectColumns, ","),
+		grammarSQL.VAL(dbName),
+		grammarSQL.VAL(tableName),
+	)
+	defer log.Debug(sql)
+	foreigns := []*dbal.Foreign{}
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &foreigns, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, foreign := range foreigns {
+		foreign.Name = strings.TrimPrefix(foreign.Name, tableName+"_")
+	}
+	return foreigns, nil
+}
+
 // GetColumnListing get a table columns structure
 func (grammarSQL Postgres) GetColumnListing(dbName string, tableName string) ([]*dbal.Column, error) {
 	selectColumns := []string{
@@ -745,7 +867,7 @@ func (grammarSQL Postgres) GetColumnListing(dbName string, tableName string) ([]
 	)
 	defer log.Debug(sql)
 	columns := []*dbal.Column{}
-	err := grammarSQL.DB.Select(&columns, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &columns, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -773,7 +895,7 @@ func (grammarSQL Postgres) GetColumnListing(dbName string, tableName string) ([]
 				column.Type = "enum"
 				if _, has := enumOptions[column.TypeName]; !has {
 					optionRange := []string{}
-					err := grammarSQL.DB.Select(&optionRange, fmt.Sprintf("select enum_range(null::%s.%s)", dbName, column.TypeName))
+					err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &optionRange, fmt.Sprintf("select enum_range(null::%s.%s)", dbName, column.TypeName))
 					if err != nil {
 						return nil, err
 					}
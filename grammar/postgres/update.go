@@ -9,6 +9,7 @@ import (
 	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
+	xsql "github.com/yaoapp/xun/grammar/sql"
 )
 
 // Upsert Upsert new records or update the existing ones.
@@ -26,10 +27,11 @@ func (grammarSQL Postgres) Upsert(query *dbal.Query, values []xun.R, uniqueBy []
 
 	sql, bindings := grammarSQL.CompileUpsert(query, columns, insertValues, uniqueBy, updateValues)
 	defer log.Debug(sql)
-	return grammarSQL.DB.Exec(sql, bindings...)
+	return grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql, bindings...)
 }
 
 // CompileUpsert Upsert new records or update the existing ones.
+// The conflicting rows are skipped (do nothing) if there are no update values.
 func (grammarSQL Postgres) CompileUpsert(query *dbal.Query, columns []interface{}, values [][]interface{}, uniqueBy []interface{}, updateValues interface{}) (string, []interface{}) {
 
 	if len(values) == 0 {
@@ -37,9 +39,17 @@ func (grammarSQL Postgres) CompileUpsert(query *dbal.Query, columns []interface{
 	}
 
 	sql, bindings := grammarSQL.CompileInsert(query, columns, values)
-	sql = fmt.Sprintf("%s on conflict (%s) do update set", sql, grammarSQL.Columnize(uniqueBy))
-	offset := len(bindings) + 1
 
+	// on conflict on constraint "name", on conflict ("email")
+	if query.Conflict.Constraint != "" {
+		sql = fmt.Sprintf("%s on conflict on constraint %s", sql, grammarSQL.ID(query.Conflict.Constraint))
+	} else if len(uniqueBy) > 0 {
+		sql = fmt.Sprintf("%s on conflict (%s)", sql, grammarSQL.Columnize(uniqueBy))
+	} else {
+		sql = fmt.Sprintf("%s on conflict", sql)
+	}
+
+	offset := len(bindings) + 1
 	update := reflect.ValueOf(updateValues)
 	kind := update.Kind()
 	segments := []string{}
@@ -60,14 +70,32 @@ func (grammarSQL Postgres) CompileUpsert(query *dbal.Query, columns []interface{
 		}
 	}
 
-	return fmt.Sprintf("%s %s", sql, strings.Join(segments, ", ")), bindings
+	if len(segments) == 0 {
+		return fmt.Sprintf("%s do nothing", sql), bindings
+	}
+
+	sql = fmt.Sprintf("%s do update set %s", sql, strings.Join(segments, ", "))
+	if where := query.Conflict.Where; where != nil && len(where.Wheres) > 0 {
+		offset = offset - 1
+		sql = fmt.Sprintf("%s %s", sql, grammarSQL.CompileWheres(where, where.Wheres, &offset))
+		bindings = append(bindings, where.GetBindings("where")...)
+	}
+
+	return sql, bindings
 }
 
 // CompileUpdate Compile an update statement into SQL.
+// The inner joins are compiled to the from clause, and the conditions of them are moved to the where clause, e.g.
+//    update "orders" set "status"=$1 from "users" where ("users"."id" = "orders"."user_id") and ("users"."banned" = $2)
+// The other joins and the limit are compiled to a subquery selecting the ctid of the rows.
 func (grammarSQL Postgres) CompileUpdate(query *dbal.Query, values map[string]interface{}) (string, []interface{}) {
 
 	if len(query.Joins) == 0 && query.Limit < 0 {
-		return grammarSQL.SQL.CompileUpdate(query, values)
+		offset := 0
+		columns, bindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
+		wheres := grammarSQL.CompileWheres(query, query.Wheres, &offset)
+		bindings = append(bindings, query.GetBindings("where")...)
+		return fmt.Sprintf("update %s set %s %s", grammarSQL.WrapTable(query.From), columns, wheres), bindings
 	}
 
 	offset := 0
@@ -77,13 +105,14 @@ func (grammarSQL Postgres) CompileUpdate(query *dbal.Query, values map[string]in
 	columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
 	bindings = append(bindings, columnsBindings...)
 
-	alias := query.From.Alias
-	if alias != "" {
-		query.Columns = []interface{}{fmt.Sprintf("%s.ctid", alias)}
-	} else {
-		query.Columns = []interface{}{"ctid"}
+	if fromJoins(query) {
+		from, wheres := grammarSQL.compileFromJoins(query, &offset)
+		bindings = append(bindings, query.GetBindings("join")...)
+		bindings = append(bindings, query.GetBindings("where")...)
+		return fmt.Sprintf("update %s set %s from %s %s", table, columns, from, wheres), bindings
 	}
 
+	query.Columns = []interface{}{grammarSQL.ctid(query)}
 	selectSQL := grammarSQL.CompileSelectOffset(query, &offset)
 
 	bindings = append(bindings, query.GetBindings()...)
@@ -91,3 +120,107 @@ func (grammarSQL Postgres) CompileUpdate(query *dbal.Query, values map[string]in
 
 	return sql, bindings
 }
+
+// fromJoins determine if the joins of the update or delete statement could be compiled to the from (using) clause.
+// Only the inner and cross joins without the nested joins are able to, and the limit is not supported either.
+func fromJoins(query *dbal.Query) bool {
+	if query.Limit >= 0 {
+		return false
+	}
+	for _, join := range query.Joins {
+		if join.Type != "inner" && join.Type != "cross" {
+			return false
+		}
+		if join.Query != nil && len(join.Query.Joins) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// compileFromJoins Compile the joins to the from items, and the join conditions and the wheres to the where clause.
+// The joins are compiled in the order of their bindings, so that the parameters are numbered correctly.
+func (grammarSQL Postgres) compileFromJoins(query *dbal.Query, offset *int) (string, string) {
+	tables := []string{}
+	conditions := []string{}
+	for _, join := range query.Joins {
+		table := grammarSQL.WrapTable(join.Name)
+		if join.SQL != nil && join.Alias != "" {
+			table = fmt.Sprintf("(%s) as %s", grammarSQL.CompileSub(join.SQL, offset), join.Alias)
+		}
+		tables = append(tables, table)
+
+		if join.Query != nil && len(join.Query.Wheres) > 0 {
+			on := grammarSQL.CompileWheres(join.Query, join.Query.Wheres, offset)
+			conditions = append(conditions, fmt.Sprintf("(%s)", strings.TrimPrefix(on, "on ")))
+		}
+	}
+
+	if len(query.Wheres) > 0 {
+		wheres := grammarSQL.CompileWheres(query, query.Wheres, offset)
+		conditions = append(conditions, fmt.Sprintf("(%s)", strings.TrimPrefix(wheres, "where ")))
+	}
+
+	if len(conditions) == 0 {
+		return strings.Join(tables, ", "), ""
+	}
+	return strings.Join(tables, ", "), fmt.Sprintf("where %s", strings.Join(conditions, " and "))
+}
+
+// ctid get the ctid column of the table, qualified by the alias or the name of the table
+// to avoid the ambiguous references of the joined tables.
+func (grammarSQL Postgres) ctid(query *dbal.Query) dbal.Expression {
+	if query.From.Alias != "" {
+		return dbal.Raw(fmt.Sprintf("%s.ctid", grammarSQL.ID(query.From.Alias)))
+	}
+	return dbal.Raw(fmt.Sprintf("%s.ctid", grammarSQL.WrapTable(query.From)))
+}
+
+// CompileUpdateColumns Compile the columns for an update statement.
+// The JSON selectors of the same column are compiled to the nested jsonb_set calls, the NULL column is updated as an empty object, e.g.
+//    options->enabled, options->theme => "options"=jsonb_set(jsonb_set(coalesce("options"::jsonb, '{}'::jsonb), '{"enabled"}', $1::jsonb), '{"theme"}', $2::jsonb)
+func (grammarSQL Postgres) CompileUpdateColumns(query *dbal.Query, values map[string]interface{}, offset *int) (string, []interface{}) {
+	plain := map[string]interface{}{}
+	for key, value := range values {
+		if !xsql.IsJSONSelector(key) {
+			plain[key] = value
+		}
+	}
+
+	columns, bindings := grammarSQL.SQL.CompileUpdateColumns(query, plain, offset)
+	segments := []string{}
+	if columns != "" {
+		segments = append(segments, columns)
+	}
+
+	names, selectors := xsql.JSONUpdateColumns(values)
+	for _, name := range names {
+		target := fmt.Sprintf("coalesce(%s::jsonb, '{}'::jsonb)", grammarSQL.Wrap(name))
+		for _, selector := range selectors[name] {
+			_, keys, _ := xsql.ParseJSONSelector(selector)
+			value := xsql.JSONUpdateValue(values[selector])
+			param := grammarSQL.Parameter(value, *offset+1)
+			if !dbal.IsExpression(value) {
+				param = fmt.Sprintf("%s::jsonb", param)
+				bindings = append(bindings, value)
+				*offset++
+			}
+			target = fmt.Sprintf("jsonb_set(%s, %s, %s)", target, jsonbPath(keys), param)
+		}
+		segments = append(segments, fmt.Sprintf("%s=%s", grammarSQL.Wrap(name), target))
+	}
+
+	return strings.Join(segments, ", "), bindings
+}
+
+// jsonbPath get the text array path of the jsonb_set function, e.g. [languages, [0]] => '{"languages","0"}'
+func jsonbPath(keys []string) string {
+	elements := []string{}
+	for _, key := range keys {
+		if strings.HasPrefix(key, "[") {
+			key = strings.Trim(key, "[]")
+		}
+		elements = append(elements, fmt.Sprintf(`"%s"`, strings.ReplaceAll(key, `"`, `\"`)))
+	}
+	return fmt.Sprintf("'{%s}'", strings.ReplaceAll(strings.Join(elements, ","), "'", "''"))
+}
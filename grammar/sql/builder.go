@@ -166,3 +166,35 @@ func (grammarSQL SQL) SQLAddPrimary(primary *dbal.Primary) string {
 
 	return sql
 }
+
+// SQLAddForeign return the add foreign key sql for table create
+func (grammarSQL SQL) SQLAddForeign(foreign *dbal.Foreign) string {
+	quoter := grammarSQL.Quoter
+
+	// CONSTRAINT `users_user_id_foreign` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+	columns := []string{}
+	for _, column := range foreign.Columns {
+		columns = append(columns, quoter.ID(column.Name))
+	}
+
+	references := []string{}
+	for _, name := range foreign.References {
+		references = append(references, quoter.ID(name))
+	}
+
+	name := fmt.Sprintf("%s_%s", foreign.TableName, foreign.Name)
+	sql := fmt.Sprintf(
+		"CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		quoter.ID(name), strings.Join(columns, ","),
+		quoter.ID(foreign.ReferenceTableName), strings.Join(references, ","))
+
+	if foreign.OnDelete != "" {
+		sql = fmt.Sprintf("%s ON DELETE %s", sql, foreign.OnDelete)
+	}
+
+	if foreign.OnUpdate != "" {
+		sql = fmt.Sprintf("%s ON UPDATE %s", sql, foreign.OnUpdate)
+	}
+
+	return sql
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
 )
 
 // CompileSelect Compile a select query into SQL.
@@ -35,6 +36,10 @@ func (grammarSQL SQL) CompileSelectOffset(query *dbal.Query, offset *int) string
 
 	sqls := map[string]string{}
 
+	// The common table expressions are compiled first, so that the binding offsets
+	// of them are in front of the other components.
+	with := grammarSQL.CompileCTEs(query, query.CTEs, offset, grammarSQL.CompileSelectOffset)
+
 	// If the query does not have any columns set, we'll set the columns to the
 	// * character to just get all of the columns from the database. Then we
 	// can build the query and concatenate all the pieces together as one.
@@ -53,13 +58,14 @@ func (grammarSQL SQL) CompileSelectOffset(query *dbal.Query, offset *int) string
 	sqls["wheres"] = grammarSQL.CompileWheres(query, query.Wheres, offset)
 	sqls["groups"] = grammarSQL.CompileGroups(query, query.Groups, offset)
 	sqls["havings"] = grammarSQL.CompileHavings(query, query.Havings, offset)
+	sqls["windows"] = grammarSQL.CompileWindows(query, query.Windows, offset)
 	sqls["orders"] = grammarSQL.CompileOrders(query, query.Orders, offset)
 	sqls["limit"] = grammarSQL.CompileLimit(query, query.Limit, offset)
 	sqls["offset"] = grammarSQL.CompileOffset(query, query.Offset)
 	sqls["lock"] = grammarSQL.CompileLock(query, query.Lock)
 
 	sql := ""
-	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "orders", "limit", "offset", "lock"} {
+	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "windows", "orders", "limit", "offset", "lock"} {
 		segment, has := sqls[name]
 		if has && segment != "" {
 			sql = sql + segment + " "
@@ -71,6 +77,10 @@ func (grammarSQL SQL) CompileSelectOffset(query *dbal.Query, offset *int) string
 		sql = fmt.Sprintf("%s %s", grammarSQL.WrapUnion(sql), grammarSQL.CompileUnions(query, query.Unions, offset))
 	}
 
+	if with != "" {
+		sql = fmt.Sprintf("%s %s", with, sql)
+	}
+
 	// reset columns
 	query.Columns = columns
 	return strings.Trim(sql, " ")
@@ -144,6 +154,63 @@ func (grammarSQL SQL) CompileUnion(query *dbal.Query, union dbal.Union, offset *
 	return fmt.Sprintf("%s%s", conjunction, grammarSQL.WrapUnion(grammarSQL.CompileSelectOffset(union.Query, offset)))
 }
 
+// CompileCTEs Compile the common table expressions of the query, e.g.
+//    with recursive `tree` (`id`, `parent_id`) as (select ... union all select ...)
+// The subqueries are compiled by the given function, the unions of them are not wrapped, so that the recursive queries could reference themselves.
+func (grammarSQL SQL) CompileCTEs(query *dbal.Query, ctes []dbal.CTE, offset *int, compile func(query *dbal.Query, offset *int) string) string {
+	if len(ctes) == 0 {
+		return ""
+	}
+
+	recursive := false
+	segments := []string{}
+	for _, cte := range ctes {
+		if cte.Recursive {
+			recursive = true
+		}
+
+		name := grammarSQL.WrapTable(cte.Name)
+		if len(cte.Columns) > 0 {
+			name = fmt.Sprintf("%s (%s)", name, grammarSQL.Columnize(cte.Columns))
+		}
+
+		sub := ""
+		switch cte.SQL.(type) {
+		case *dbal.Query:
+			sub = grammarSQL.CompileCTEQuery(cte.SQL.(*dbal.Query), offset, compile)
+		default:
+			sub = grammarSQL.CompileSub(cte.SQL, offset)
+		}
+		segments = append(segments, fmt.Sprintf("%s as (%s)", name, sub))
+	}
+
+	if recursive {
+		return fmt.Sprintf("with recursive %s", strings.Join(segments, ", "))
+	}
+	return fmt.Sprintf("with %s", strings.Join(segments, ", "))
+}
+
+// CompileCTEQuery Compile the subquery of the common table expression, the unions are joined without parentheses.
+func (grammarSQL SQL) CompileCTEQuery(query *dbal.Query, offset *int, compile func(query *dbal.Query, offset *int) string) string {
+	if len(query.Unions) == 0 {
+		return compile(query, offset)
+	}
+
+	unions := query.Unions
+	query.Unions = []dbal.Union{}
+	sql := compile(query, offset)
+	query.Unions = unions
+
+	for _, union := range unions {
+		conjunction := "union"
+		if union.All {
+			conjunction = "union all"
+		}
+		sql = fmt.Sprintf("%s %s %s", sql, conjunction, compile(union.Query, offset))
+	}
+	return sql
+}
+
 // CompileJoins Compile the "join" portions of the query.
 func (grammarSQL SQL) CompileJoins(query *dbal.Query, joins []dbal.Join, offset *int) string {
 	sql := ""
@@ -208,7 +275,7 @@ func (grammarSQL SQL) CompileColumns(query *dbal.Query, columns []interface{}, b
 		sql = "select distinct"
 	}
 
-	sql = fmt.Sprintf("%s %s", sql, grammarSQL.Columnize(columns))
+	sql = fmt.Sprintf("%s %s", sql, grammarSQL.Columnize(grammarSQL.CompileWindowColumns(columns)))
 	for _, col := range columns {
 		switch col.(type) {
 		case dbal.Select:
@@ -219,6 +286,94 @@ func (grammarSQL SQL) CompileColumns(query *dbal.Query, columns []interface{}, b
 	return sql
 }
 
+// CompileWindowColumns Compile the window function columns, the other columns are returned as they are.
+func (grammarSQL SQL) CompileWindowColumns(columns []interface{}) []interface{} {
+	compiled := []interface{}{}
+	for _, col := range columns {
+		column, ok := col.(dbal.Select)
+		if !ok || column.Type != "window" {
+			compiled = append(compiled, col)
+			continue
+		}
+
+		args := []string{}
+		for _, arg := range column.Args {
+			switch arg.(type) {
+			case string, dbal.Expression, dbal.Name:
+				args = append(args, grammarSQL.Wrap(arg))
+			default:
+				args = append(args, fmt.Sprintf("%v", arg))
+			}
+		}
+
+		// row_number() over `w`, row_number() over (partition by `department` order by `salary` desc)
+		window := column.Window
+		if window == nil {
+			window = &dbal.Window{}
+		}
+		over := fmt.Sprintf("(%s)", grammarSQL.CompileWindow(*window))
+		if window.Name != "" && len(window.PartitionBy) == 0 && len(window.OrderBy) == 0 && window.Frame == "" {
+			over = grammarSQL.ID(window.Name)
+		}
+		column.SQL = fmt.Sprintf("%s(%s) over %s", column.Name, strings.Join(args, ", "), over)
+		compiled = append(compiled, column)
+	}
+	return compiled
+}
+
+// CompileWindows Compile the "window" portions of the query.
+func (grammarSQL SQL) CompileWindows(query *dbal.Query, windows []dbal.Window, bindingOffset *int) string {
+	if len(windows) == 0 {
+		return ""
+	}
+
+	segments := []string{}
+	for _, window := range windows {
+		spec := window
+		spec.Name = ""
+		segments = append(segments, fmt.Sprintf("%s as (%s)", grammarSQL.ID(window.Name), grammarSQL.CompileWindow(spec)))
+	}
+	return fmt.Sprintf("window %s", strings.Join(segments, ", "))
+}
+
+// CompileWindow Compile the window specification, e.g. partition by `department` order by `salary` desc
+func (grammarSQL SQL) CompileWindow(window dbal.Window) string {
+	segments := []string{}
+	if window.Name != "" {
+		segments = append(segments, grammarSQL.ID(window.Name))
+	}
+
+	if len(window.PartitionBy) > 0 {
+		segments = append(segments, fmt.Sprintf("partition by %s", grammarSQL.Columnize(window.PartitionBy)))
+	}
+
+	if len(window.OrderBy) > 0 {
+		orders := []string{}
+		for _, order := range window.OrderBy {
+			column, ok := order.(string)
+			if !ok {
+				orders = append(orders, grammarSQL.Wrap(order))
+				continue
+			}
+
+			direction := "asc"
+			fields := strings.Fields(column)
+			if len(fields) == 2 && utils.StringHave([]string{"asc", "desc"}, strings.ToLower(fields[1])) {
+				column = fields[0]
+				direction = strings.ToLower(fields[1])
+			}
+			orders = append(orders, fmt.Sprintf("%s %s", grammarSQL.Wrap(column), direction))
+		}
+		segments = append(segments, fmt.Sprintf("order by %s", strings.Join(orders, ", ")))
+	}
+
+	if window.Frame != "" {
+		segments = append(segments, window.Frame)
+	}
+
+	return strings.Join(segments, " ")
+}
+
 //CompileFrom  Compile the "from" portion of the query.
 func (grammarSQL SQL) CompileFrom(query *dbal.Query, from dbal.From, bindingOffset *int) string {
 	if from.Type == "" {
@@ -545,6 +700,51 @@ func (grammarSQL SQL) WhereIn(query *dbal.Query, where dbal.Where, bindingOffset
 	return sql
 }
 
+// WhereJSONContains Compile a "where JSON contains" clause.
+func (grammarSQL SQL) WhereJSONContains(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	column, path := grammarSQL.WrapJSONPath(where.Column)
+	*bindingOffset = *bindingOffset + where.Offset
+	value := grammarSQL.Parameter(where.Value, *bindingOffset)
+
+	sql := fmt.Sprintf("json_contains(%s, %s, %s)", column, value, path)
+	if where.Not {
+		return fmt.Sprintf("not %s", sql)
+	}
+	return sql
+}
+
+// WhereJSONLength Compile a "where JSON length" clause.
+func (grammarSQL SQL) WhereJSONLength(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	column, path := grammarSQL.WrapJSONPath(where.Column)
+	value := ""
+	if !dbal.IsExpression(where.Value) {
+		*bindingOffset = *bindingOffset + where.Offset
+		value = grammarSQL.Parameter(where.Value, *bindingOffset)
+	} else {
+		value = where.Value.(dbal.Expression).GetValue()
+	}
+
+	return fmt.Sprintf("json_length(%s, %s) %s %s", column, path, where.Operator, value)
+}
+
+// WhereJSONContainsKey Compile a "where JSON contains key" clause.
+func (grammarSQL SQL) WhereJSONContainsKey(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	column, path := grammarSQL.WrapJSONPath(where.Column)
+	sql := fmt.Sprintf("ifnull(json_contains_path(%s, 'one', %s), 0)", column, path)
+	if where.Not {
+		return fmt.Sprintf("not %s", sql)
+	}
+	return sql
+}
+
+// WrapJSONPath Wrap the column of the JSON selector and get the path of it, e.g.
+//    options->languages => `options`, '$."languages"'
+//    options            => `options`, '$'
+func (grammarSQL SQL) WrapJSONPath(selector interface{}) (string, string) {
+	column, keys, _ := ParseJSONSelector(fmt.Sprintf("%v", selector))
+	return grammarSQL.Wrap(column), JSONPath(keys)
+}
+
 // Utils for compiling
 
 // RemoveLeadingBoolean Remove the leading boolean from a statement.
@@ -2,12 +2,13 @@ package sql
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/yaoapp/xun/dbal"
 )
 
 // CompileDelete Compile a delete statement into SQL.
+// The rows of the table are deleted by the alias or the name of the table if there are joins, e.g.
+//    delete `orders` from `orders` inner join `users` on `users`.`id` = `orders`.`user_id` where `users`.`banned` = ?
 func (grammarSQL SQL) CompileDelete(query *dbal.Query) (string, []interface{}) {
 
 	offset := 0
@@ -20,9 +21,9 @@ func (grammarSQL SQL) CompileDelete(query *dbal.Query) (string, []interface{}) {
 		joins = grammarSQL.CompileJoins(query, query.Joins, &offset)
 		bindings = append(bindings, query.GetBindings("join")...)
 		offset = len(bindings)
-		tableArr := strings.Split(table, " as ")
-		if len(tableArr) >= 1 {
-			alias = tableArr[1]
+		alias = table
+		if query.From.Alias != "" {
+			alias = grammarSQL.ID(query.From.Alias)
 		}
 	}
 
@@ -41,3 +42,11 @@ func (grammarSQL SQL) CompileTruncate(query *dbal.Query) ([]string, [][]interfac
 	sql := fmt.Sprintf("truncate table %s", grammarSQL.WrapTable(query.From))
 	return []string{sql}, [][]interface{}{{}}
 }
+
+// CompileReturning Compile the returning clause of the insert, update, upsert and delete statements.
+func (grammarSQL SQL) CompileReturning(query *dbal.Query, columns []interface{}) (string, error) {
+	if len(columns) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("returning %s", grammarSQL.Columnize(columns)), nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun/dbal"
 )
 
@@ -43,13 +44,13 @@ func (grammarSQL SQL) CompileInsertGetID(query *dbal.Query, columns []interface{
 
 // ProcessInsertGetID Execute an insert and get ID statement and return the id
 func (grammarSQL SQL) ProcessInsertGetID(sql string, bindings []interface{}, sequence string) (int64, error) {
-	stmt, err := grammarSQL.DB.Prepare(sql)
+	stmt, err := grammarSQL.Executor().PrepareContext(grammarSQL.Context(), sql)
 	if err != nil {
 		return 0, err
 	}
 
 	defer stmt.Close()
-	res, err := stmt.Exec(bindings...)
+	res, err := stmt.ExecContext(grammarSQL.Context(), bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -61,3 +62,94 @@ func (grammarSQL SQL) ProcessInsertGetID(sql string, bindings []interface{}, seq
 func (grammarSQL SQL) CompileInsertUsing(query *dbal.Query, columns []interface{}, sql string) string {
 	return fmt.Sprintf("INSERT INTO %s (%s) %s", grammarSQL.WrapTable(query.From), grammarSQL.Columnize(columns), sql)
 }
+
+// ProcessCopyFrom Import the rows into the table using the batched prepared insert statements within a transaction.
+// The batches have 999 parameters at most, which is the smallest limit of the drivers.
+func (grammarSQL SQL) ProcessCopyFrom(query *dbal.Query, columns []string, rows dbal.CopyFromSource) (int64, error) {
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("the columns of the rows are required")
+	}
+
+	names := []interface{}{}
+	for _, column := range columns {
+		names = append(names, column)
+	}
+
+	size := 999 / len(columns)
+	if size < 1 {
+		size = 1
+	}
+
+	var inserted int64 = 0
+	err := grammarSQL.Transact(func(tx *sqlx.Tx) error {
+		var stmt *sqlx.Stmt
+		defer func() {
+			if stmt != nil {
+				stmt.Close()
+			}
+		}()
+
+		// the statement is prepared once for the batches having the same sql
+		prepared := ""
+		batch := [][]interface{}{}
+		insert := func() error {
+			insertSQL, bindings := grammarSQL.CompileInsert(query, names, batch)
+			if stmt == nil || insertSQL != prepared {
+				if stmt != nil {
+					stmt.Close()
+				}
+
+				var err error
+				stmt, err = tx.PreparexContext(grammarSQL.Context(), insertSQL)
+				if err != nil {
+					stmt = nil
+					return err
+				}
+				prepared = insertSQL
+			}
+
+			res, err := stmt.ExecContext(grammarSQL.Context(), bindings...)
+			if err != nil {
+				return err
+			}
+
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			inserted += affected
+			batch = [][]interface{}{}
+			return nil
+		}
+
+		for rows.Next() {
+			values, err := rows.Values()
+			if err != nil {
+				return err
+			}
+			if len(values) != len(columns) {
+				return fmt.Errorf("the row has %d values, but there are %d columns", len(values), len(columns))
+			}
+			batch = append(batch, values)
+			if len(batch) == size {
+				if err := insert(); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(batch) > 0 {
+			return insert()
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
@@ -2,6 +2,7 @@ package sql
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/jmoiron/sqlx"
@@ -9,6 +10,16 @@ import (
 	"github.com/yaoapp/xun/utils"
 )
 
+// the patterns of the JSON selector segments and the alias
+var (
+	jsonSegmentPattern = regexp.MustCompile(`^(.*?)((?:\[\d+\])*)$`)
+	jsonIndexPattern   = regexp.MustCompile(`\[\d+\]`)
+	aliasPattern       = regexp.MustCompile(`(?i)\s+as\s+`)
+)
+
+// jsonKeyEscaper escape the backslashes and the double quotes of the JSON path key, the escaping backslashes are not escaped again
+var jsonKeyEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
 // Quoter the database quoting query text SQL type
 type Quoter struct {
 	DB         *sqlx.DB
@@ -89,12 +100,80 @@ func (quoter *Quoter) Wrap(value interface{}) string {
 		}
 		return fmt.Sprintf("%s ", col.SQL)
 	case string:
+		if IsJSONSelector(value.(string)) {
+			return quoter.WrapJSONSelector(value.(string))
+		}
 		return quoter.WrapAliasedValue(value.(string))
 	default:
 		return fmt.Sprintf("%v", value)
 	}
 }
 
+// WrapJSONSelector Wrap the given JSON selector, e.g.
+//    preferences->dining->meal  => json_extract(`preferences`, '$."dining"."meal"')
+//    preferences->dining->>meal => json_unquote(json_extract(`preferences`, '$."dining"."meal"'))
+func (quoter *Quoter) WrapJSONSelector(value string) string {
+	selector, alias := SplitAlias(value)
+	column, keys, text := ParseJSONSelector(selector)
+	sql := fmt.Sprintf("json_extract(%s, %s)", quoter.WrapAliasedValue(column), JSONPath(keys))
+	if text {
+		sql = fmt.Sprintf("json_unquote(%s)", sql)
+	}
+	if alias != "" {
+		return fmt.Sprintf("%s as %s", sql, quoter.ID(alias))
+	}
+	return sql
+}
+
+// IsJSONSelector Determine if the given value is a JSON selector, e.g. preferences->dining->meal
+func IsJSONSelector(value string) bool {
+	return strings.Contains(value, "->")
+}
+
+// ParseJSONSelector Parse the JSON selector, returns the column, the keys of the path and whether the value should be unquoted (->>).
+// The array indexes are returned as the keys, e.g. options->languages[0] => options, [languages, [0]], false
+func ParseJSONSelector(value string) (string, []string, bool) {
+	text := false
+	if pos := strings.LastIndex(value, "->>"); pos >= 0 && !strings.Contains(value[pos+3:], "->") {
+		text = true
+	}
+
+	segments := strings.Split(strings.ReplaceAll(value, "->>", "->"), "->")
+	keys := []string{}
+	for _, segment := range segments[1:] {
+		segment = strings.Trim(segment, " '\"")
+		matches := jsonSegmentPattern.FindStringSubmatch(segment)
+		if matches[1] != "" {
+			keys = append(keys, matches[1])
+		}
+		keys = append(keys, jsonIndexPattern.FindAllString(matches[2], -1)...)
+	}
+	return strings.TrimSpace(segments[0]), keys, text
+}
+
+// JSONPath Get the JSON path literal of the given keys, e.g. [dining, meal, [0]] => '$."dining"."meal"[0]'
+// The backslashes and the double quotes of the keys are escaped, e.g. [a"b] => '$."a\"b"'
+func JSONPath(keys []string) string {
+	path := "$"
+	for _, key := range keys {
+		if strings.HasPrefix(key, "[") {
+			path = path + key
+			continue
+		}
+		path = fmt.Sprintf(`%s."%s"`, path, jsonKeyEscaper.Replace(key))
+	}
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(path, "'", "''"))
+}
+
+// SplitAlias Split the alias from the given value, e.g. preferences->theme as theme => preferences->theme, theme
+func SplitAlias(value string) (string, string) {
+	segments := aliasPattern.Split(value, 2)
+	if len(segments) == 2 {
+		return strings.TrimSpace(segments[0]), strings.TrimSpace(segments[1])
+	}
+	return value, ""
+}
+
 // WrapAliasedValue Wrap a value that has an alias.
 func (quoter *Quoter) WrapAliasedValue(value string) string {
 	if value == "*" {
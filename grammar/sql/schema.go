@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/blang/semver/v4"
 	"github.com/yaoapp/kun/log"
@@ -22,12 +24,43 @@ func (grammarSQL SQL) GetSchema() string {
 	return grammarSQL.SchemaName
 }
 
-// GetVersion get the version of the connection database
+// GetLocation get the location of the date and time values without the time zone of the current connection
+func (grammarSQL SQL) GetLocation() *time.Location {
+	return time.Local
+}
+
+// versions the versions of the databases, keyed by the connection (*sqlx.DB)
+var versions sync.Map
+
+// GetVersion get the version of the connection database, the version is cached per connection.
 func (grammarSQL SQL) GetVersion() (*dbal.Version, error) {
+	return grammarSQL.CacheVersion(grammarSQL.queryVersion)
+}
+
+// CacheVersion get the cached version of the connection, the version is queried using the given function once per connection.
+func (grammarSQL SQL) CacheVersion(query func() (*dbal.Version, error)) (*dbal.Version, error) {
+	if grammarSQL.DB == nil {
+		return query()
+	}
+
+	if version, has := versions.Load(grammarSQL.DB); has {
+		return version.(*dbal.Version), nil
+	}
+
+	version, err := query()
+	if err != nil {
+		return nil, err
+	}
+	cached, _ := versions.LoadOrStore(grammarSQL.DB, version)
+	return cached.(*dbal.Version), nil
+}
+
+// queryVersion query the version of the connection database
+func (grammarSQL SQL) queryVersion() (*dbal.Version, error) {
 	sql := fmt.Sprintf("SELECT VERSION()")
 	// defer logger.Debug(logger.RETRIEVE, sql).TimeCost(time.Now())
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &rows, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +108,7 @@ func (grammarSQL SQL) GetTables() ([]string, error) {
 	sql := "SHOW TABLES"
 	defer log.Debug(sql)
 	tables := []string{}
-	err := grammarSQL.DB.Select(&tables, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &tables, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +120,7 @@ func (grammarSQL SQL) TableExists(name string) (bool, error) {
 	sql := fmt.Sprintf("SHOW TABLES like %s", grammarSQL.VAL(name))
 	defer log.Debug(sql)
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &rows, sql)
 	if err != nil {
 		return false, err
 	}
@@ -120,6 +153,11 @@ func (grammarSQL SQL) GetTable(name string) (*dbal.Table, error) {
 		return nil, err
 	}
 
+	foreigns, err := grammarSQL.GetForeignListing(table.SchemaName, table.TableName)
+	if err != nil {
+		return nil, err
+	}
+
 	primaryKeyName := ""
 
 	// attaching columns
@@ -128,9 +166,34 @@ func (grammarSQL SQL) GetTable(name string) (*dbal.Table, error) {
 		table.PushColumn(column)
 	}
 
+	// attaching foreign keys
+	foreignIndexes := map[string]bool{}
+	for i := range foreigns {
+		fk := foreigns[i]
+		if !table.HasColumn(fk.ColumnName) {
+			return nil, fmt.Errorf("the column does not exists %s", fk.ColumnName)
+		}
+		if !table.HasForeign(fk.Name) {
+			foreign := *fk
+			foreign.Columns = []*dbal.Column{}
+			foreign.References = []string{}
+			table.PushForeign(&foreign)
+		}
+		foreign := table.ForeignMap[fk.Name]
+		foreign.AddColumn(table.ColumnMap[fk.ColumnName])
+		foreign.References = append(foreign.References, fk.ReferenceColumnName)
+		foreignIndexes[fmt.Sprintf("%s_%s", table.TableName, fk.Name)] = true
+	}
+
 	// attaching indexes
 	for i := range indexes {
 		idx := indexes[i]
+
+		// the index created by MySQL automatically for the foreign key
+		if _, has := foreignIndexes[idx.Name]; has {
+			continue
+		}
+
 		if !table.HasColumn(idx.ColumnName) {
 			return nil, fmt.Errorf("the column does not exists %s", idx.ColumnName)
 		}
@@ -203,7 +266,7 @@ func (grammarSQL SQL) GetIndexListing(dbName string, tableName string) ([]*dbal.
 	)
 	defer log.Debug(sql)
 	indexes := []*dbal.Index{}
-	err := grammarSQL.DB.Select(&indexes, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &indexes, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -221,6 +284,44 @@ func (grammarSQL SQL) GetIndexListing(dbName string, tableName string) ([]*dbal.
 	return indexes, nil
 }
 
+// GetForeignListing get a table foreign keys structure
+func (grammarSQL SQL) GetForeignListing(dbName string, tableName string) ([]*dbal.Foreign, error) {
+	selectColumns := []string{
+		"kcu.`TABLE_SCHEMA` AS `db_name`",
+		"kcu.`TABLE_NAME` AS `table_name`",
+		"kcu.`CONSTRAINT_NAME` AS `foreign_name`",
+		"kcu.`COLUMN_NAME` AS `column_name`",
+		"kcu.`REFERENCED_TABLE_NAME` AS `reference_table_name`",
+		"kcu.`REFERENCED_COLUMN_NAME` AS `reference_column_name`",
+		"rc.`DELETE_RULE` AS `on_delete`",
+		"rc.`UPDATE_RULE` AS `on_update`",
+		"kcu.`ORDINAL_POSITION` AS `seq_in_foreign`",
+	}
+	sql := fmt.Sprintf(`
+			SELECT %s
+			FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE AS kcu
+			INNER JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS AS rc
+				ON rc.CONSTRAINT_SCHEMA = kcu.CONSTRAINT_SCHEMA AND rc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME
+			WHERE kcu.TABLE_SCHEMA = %s AND kcu.TABLE_NAME = %s AND kcu.REFERENCED_TABLE_NAME IS NOT NULL
+			ORDER BY kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION;
+		`,
+		strings.Join(selectColumns, ","),
+		grammarSQL.VAL(dbName),
+		grammarSQL.VAL(tableName),
+	)
+	defer log.Debug(sql)
+	foreigns := []*dbal.Foreign{}
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &foreigns, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, foreign := range foreigns {
+		foreign.Name = strings.TrimPrefix(foreign.Name, tableName+"_")
+	}
+	return foreigns, nil
+}
+
 // GetColumnListing get a table columns structure
 func (grammarSQL SQL) GetColumnListing(dbName string, tableName string) ([]*dbal.Column, error) {
 	selectColumns := []string{
@@ -266,7 +367,7 @@ func (grammarSQL SQL) GetColumnListing(dbName string, tableName string) ([]*dbal
 	)
 	defer log.Debug(sql)
 	columns := []*dbal.Column{}
-	err := grammarSQL.DB.Select(&columns, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &columns, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -310,6 +411,7 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table) error {
 	var primary *dbal.Primary = nil
 	columns := []*dbal.Column{}
 	indexes := []*dbal.Index{}
+	foreigns := []*dbal.Foreign{}
 	cbCommands := []*dbal.Command{}
 
 	// Commands
@@ -322,6 +424,7 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table) error {
 	//    DropIndex( name string) for  dropping a index
 	//    RenameIndex(old string,new string)  for renaming a index
 	//    CreatePrimary for creating the primary key
+	//    CreateForeign(foreign *Foreign) for creating a foreign key
 	for _, command := range table.Commands {
 		switch command.Name {
 		case "AddColumn":
@@ -336,6 +439,10 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table) error {
 			primary = command.Params[0].(*dbal.Primary)
 			cbCommands = append(cbCommands, command)
 			break
+		case "CreateForeign":
+			foreigns = append(foreigns, command.Params[0].(*dbal.Foreign))
+			cbCommands = append(cbCommands, command)
+			break
 		}
 
 	}
@@ -362,6 +469,11 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table) error {
 		}
 	}
 
+	// foreign keys
+	for _, foreign := range foreigns {
+		stmts = append(stmts, grammarSQL.SQLAddForeign(foreign))
+	}
+
 	engine := utils.GetIF(table.Engine != "", "ENGINE "+table.Engine, "")
 	charset := utils.GetIF(table.Charset != "", "DEFAULT CHARSET "+table.Charset, "")
 	collation := utils.GetIF(table.Collation != "", "COLLATE="+table.Collation, "")
@@ -372,7 +484,7 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table) error {
 		engine, charset, collation,
 	)
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql)
 
 	// Callback
 	for _, cmd := range cbCommands {
@@ -386,7 +498,7 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table) error {
 func (grammarSQL SQL) DropTable(name string) error {
 	sql := fmt.Sprintf("DROP TABLE %s", grammarSQL.ID(name))
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql)
 	return err
 }
 
@@ -394,7 +506,7 @@ func (grammarSQL SQL) DropTable(name string) error {
 func (grammarSQL SQL) DropTableIfExists(name string) error {
 	sql := fmt.Sprintf("DROP TABLE IF EXISTS %s", grammarSQL.ID(name))
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql)
 	return err
 }
 
@@ -402,7 +514,7 @@ func (grammarSQL SQL) DropTableIfExists(name string) error {
 func (grammarSQL SQL) RenameTable(old string, new string) error {
 	sql := fmt.Sprintf("ALTER TABLE %s RENAME %s", grammarSQL.ID(old), grammarSQL.ID(new))
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql)
 	return err
 }
 
@@ -422,6 +534,8 @@ func (grammarSQL SQL) AlterTable(table *dbal.Table) error {
 	//    CreateIndex(index *Index) for creating a index
 	//    DropIndex(name string) for  dropping a index
 	//    RenameIndex(old string,new string)  for renaming a index
+	//    CreateForeign(foreign *Foreign) for creating a foreign key
+	//    DropForeign(name string) for dropping a foreign key
 	for _, command := range table.Commands {
 		switch command.Name {
 		case "AddColumn":
@@ -451,6 +565,12 @@ func (grammarSQL SQL) AlterTable(table *dbal.Table) error {
 		case "DropPrimary":
 			grammarSQL.alterTableDropPrimary(table, command, sql, &stmts, &errs)
 			break
+		case "CreateForeign":
+			grammarSQL.alterTableCreateForeign(table, command, sql, &stmts, &errs)
+			break
+		case "DropForeign":
+			grammarSQL.alterTableDropForeign(table, command, sql, &stmts, &errs)
+			break
 		}
 	}
 
@@ -619,9 +739,67 @@ func (grammarSQL SQL) alterTableDropPrimary(table *dbal.Table, command *dbal.Com
 	command.Callback(err)
 }
 
+func (grammarSQL SQL) alterTableCreateForeign(table *dbal.Table, command *dbal.Command, sql string, stmts *[]string, errs *[]error) {
+	foreign := command.Params[0].(*dbal.Foreign)
+	stmt := "ADD " + grammarSQL.SQLAddForeign(foreign)
+	*stmts = append(*stmts, sql+stmt)
+	err := grammarSQL.ExecSQL(table, sql+stmt)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("CreateForeign: %s", err))
+	}
+	command.Callback(err)
+}
+
+func (grammarSQL SQL) alterTableDropForeign(table *dbal.Table, command *dbal.Command, sql string, stmts *[]string, errs *[]error) {
+	name := fmt.Sprintf("%s_%s", table.TableName, command.Params[0])
+	stmt := fmt.Sprintf("DROP FOREIGN KEY %s", grammarSQL.ID(name))
+	*stmts = append(*stmts, sql+stmt)
+	err := grammarSQL.ExecSQL(table, sql+stmt)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("DropForeign: %s", err))
+		command.Callback(err)
+		return
+	}
+
+	// drop the index created by MySQL automatically for the foreign key,
+	// it is not attached to the table by the GetTable, so check it in the information schema.
+	has, err := grammarSQL.indexExists(table, name)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("DropForeign: %s", err))
+		command.Callback(err)
+		return
+	}
+	if has {
+		stmt = fmt.Sprintf("DROP INDEX %s", grammarSQL.ID(name))
+		*stmts = append(*stmts, sql+stmt)
+		err = grammarSQL.ExecSQL(table, sql+stmt)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("DropForeign: %s", err))
+		}
+	}
+	command.Callback(err)
+}
+
+// indexExists check if the index exists on the table in the information schema
+func (grammarSQL SQL) indexExists(table *dbal.Table, name string) (bool, error) {
+	sql := fmt.Sprintf(
+		"SELECT COUNT(*) FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = %s AND TABLE_NAME = %s AND INDEX_NAME = %s",
+		grammarSQL.VAL(table.SchemaName),
+		grammarSQL.VAL(table.TableName),
+		grammarSQL.VAL(name),
+	)
+	defer log.Debug(sql)
+	count := 0
+	err := grammarSQL.Executor().GetContext(grammarSQL.Context(), &count, sql)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // ExecSQL execute sql then update table structure
 func (grammarSQL SQL) ExecSQL(table *dbal.Table, sql string) error {
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"path/filepath"
@@ -20,6 +21,8 @@ type SQL struct {
 	DatabaseName string
 	SchemaName   string
 	DB           *sqlx.DB
+	Tx           *sqlx.Tx
+	Ctx          context.Context
 	Config       *dbal.Config
 	Read         *sqlx.DB
 	ReadConfig   *dbal.Config
@@ -131,6 +134,54 @@ func (grammarSQL SQL) NewWithRead(write *sqlx.DB, writeConfig *dbal.Config, read
 	return grammarSQL, nil
 }
 
+// WithTx Create a new grammar interface, the statements will be executed on the given transaction.
+func (grammarSQL SQL) WithTx(tx *sqlx.Tx) dbal.Grammar {
+	grammarSQL.Tx = tx
+	return grammarSQL
+}
+
+// WithContext Create a new grammar interface, the statements will be executed with the given context.
+func (grammarSQL SQL) WithContext(ctx context.Context) dbal.Grammar {
+	grammarSQL.Ctx = ctx
+	return grammarSQL
+}
+
+// Context get the context of the grammar, returns context.Background() if the context is not set.
+func (grammarSQL SQL) Context() context.Context {
+	if grammarSQL.Ctx != nil {
+		return grammarSQL.Ctx
+	}
+	return context.Background()
+}
+
+// Executor get the database executor, returns the transaction if the grammar is bound to one.
+func (grammarSQL SQL) Executor() dbal.Executor {
+	if grammarSQL.Tx != nil {
+		return grammarSQL.Tx
+	}
+	return grammarSQL.DB
+}
+
+// Transact Execute the callback within the transaction the grammar bound to.
+// If the grammar is not bound to a transaction, a new one is started, and it is committed if the callback returns nil, otherwise it is rolled back.
+func (grammarSQL SQL) Transact(callback func(tx *sqlx.Tx) error) error {
+	if grammarSQL.Tx != nil {
+		return callback(grammarSQL.Tx)
+	}
+
+	tx, err := grammarSQL.DB.BeginTxx(grammarSQL.Context(), nil)
+	if err != nil {
+		return err
+	}
+
+	err = callback(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
 // OnConnected the event will be triggered when db server was connected
 func (grammarSQL SQL) OnConnected() error {
 	return nil
@@ -0,0 +1,18 @@
+package sql
+
+import "fmt"
+
+// CompileSavepoint Compile the SQL statement to define a savepoint.
+func (grammarSQL SQL) CompileSavepoint(name string) string {
+	return fmt.Sprintf("savepoint %s", grammarSQL.ID(name))
+}
+
+// CompileReleaseSavepoint Compile the SQL statement to release a savepoint.
+func (grammarSQL SQL) CompileReleaseSavepoint(name string) string {
+	return fmt.Sprintf("release savepoint %s", grammarSQL.ID(name))
+}
+
+// CompileRollbackToSavepoint Compile the SQL statement to execute a savepoint rollback.
+func (grammarSQL SQL) CompileRollbackToSavepoint(name string) string {
+	return fmt.Sprintf("rollback to savepoint %s", grammarSQL.ID(name))
+}
@@ -1,10 +1,13 @@
 package sql
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
 )
 
 // CompileUpsert Compile an "upsert" statement into SQL.
@@ -12,7 +15,14 @@ func (grammarSQL SQL) CompileUpsert(query *dbal.Query, columns []interface{}, va
 	panic(fmt.Errorf("This database engine does not support upserts"))
 }
 
+// CompileExcluded Compile the reference to the column of the row proposed for insertion in the upsert statements, e.g. excluded."version"
+func (grammarSQL SQL) CompileExcluded(column string) string {
+	return fmt.Sprintf("excluded.%s", grammarSQL.Wrap(column))
+}
+
 // CompileUpdate Compile an update statement into SQL.
+// The joins are compiled between the table and the set clause, e.g.
+//    update `orders` inner join `users` on `users`.`id` = `orders`.`user_id` set `status`=? where `users`.`banned` = ?
 func (grammarSQL SQL) CompileUpdate(query *dbal.Query, values map[string]interface{}) (string, []interface{}) {
 
 	offset := 0
@@ -21,7 +31,7 @@ func (grammarSQL SQL) CompileUpdate(query *dbal.Query, values map[string]interfa
 
 	joins := ""
 	if len(query.Joins) > 0 {
-		joins = grammarSQL.CompileJoins(query, query.Joins, &offset)
+		joins = grammarSQL.CompileJoins(query, query.Joins, &offset) + " "
 		bindings = append(bindings, query.GetBindings("join")...)
 		offset = len(bindings)
 	}
@@ -36,15 +46,72 @@ func (grammarSQL SQL) CompileUpdate(query *dbal.Query, values map[string]interfa
 }
 
 // CompileUpdateColumns Compile the columns for an update statement.
+// The JSON selectors of the same column are compiled to one json_set call, the NULL column is updated as an empty object, e.g.
+//    options->enabled, options->theme => `options`=json_set(coalesce(`options`, '{}'), '$."enabled"', cast(? as json), '$."theme"', cast(? as json))
 func (grammarSQL SQL) CompileUpdateColumns(query *dbal.Query, values map[string]interface{}, offset *int) (string, []interface{}) {
 	columns := []string{}
 	bindings := []interface{}{}
 	for key, value := range values {
+		if IsJSONSelector(key) {
+			continue
+		}
 		columns = append(columns, fmt.Sprintf("%s=%s", grammarSQL.Wrap(key), grammarSQL.Parameter(value, *offset+1)))
 		if !dbal.IsExpression(value) {
 			bindings = append(bindings, value)
 			*offset++
 		}
 	}
+
+	names, selectors := JSONUpdateColumns(values)
+	for _, name := range names {
+		args := []string{fmt.Sprintf("coalesce(%s, '{}')", grammarSQL.Wrap(name))}
+		for _, selector := range selectors[name] {
+			_, keys, _ := ParseJSONSelector(selector)
+			value := JSONUpdateValue(values[selector])
+			param := grammarSQL.Parameter(value, *offset+1)
+			if !dbal.IsExpression(value) {
+				param = fmt.Sprintf("cast(%s as json)", param)
+				bindings = append(bindings, value)
+				*offset++
+			}
+			args = append(args, JSONPath(keys), param)
+		}
+		columns = append(columns, fmt.Sprintf("%s=json_set(%s)", grammarSQL.Wrap(name), strings.Join(args, ", ")))
+	}
+
 	return strings.Join(columns, ", "), bindings
 }
+
+// JSONUpdateColumns Group the JSON selectors of the update values by the column, returns the columns and the selectors of them, e.g.
+//    {"options->enabled": true, "options->theme": "dark", "name": "Ken"} => [options], {options: [options->enabled, options->theme]}
+// The columns and the selectors are sorted, so that the bindings are in a stable order.
+func JSONUpdateColumns(values map[string]interface{}) ([]string, map[string][]string) {
+	names := []string{}
+	selectors := map[string][]string{}
+	for key := range values {
+		if !IsJSONSelector(key) {
+			continue
+		}
+		column, _, _ := ParseJSONSelector(key)
+		if _, has := selectors[column]; !has {
+			names = append(names, column)
+		}
+		selectors[column] = append(selectors[column], key)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		sort.Strings(selectors[name])
+	}
+	return names, selectors
+}
+
+// JSONUpdateValue Encode the value of the JSON selector as JSON, the expression is returned as it is.
+func JSONUpdateValue(value interface{}) interface{} {
+	if dbal.IsExpression(value) {
+		return value
+	}
+	bytes, err := json.Marshal(value)
+	utils.PanicIF(err)
+	return string(bytes)
+}
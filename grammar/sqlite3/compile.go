@@ -34,6 +34,10 @@ func (grammarSQL SQLite3) CompileSelectOffset(query *dbal.Query, offset *int) st
 
 	sqls := map[string]string{}
 
+	// The common table expressions are compiled first, so that the binding offsets
+	// of them are in front of the other components.
+	with := grammarSQL.CompileCTEs(query, query.CTEs, offset, grammarSQL.CompileSelectOffset)
+
 	// If the query does not have any columns set, we'll set the columns to the
 	// * character to just get all of the columns from the database. Then we
 	// can build the query and concatenate all the pieces together as one.
@@ -52,13 +56,14 @@ func (grammarSQL SQLite3) CompileSelectOffset(query *dbal.Query, offset *int) st
 	sqls["wheres"] = grammarSQL.CompileWheres(query, query.Wheres, offset)
 	sqls["groups"] = grammarSQL.CompileGroups(query, query.Groups, offset)
 	sqls["havings"] = grammarSQL.CompileHavings(query, query.Havings, offset)
+	sqls["windows"] = grammarSQL.CompileWindows(query, query.Windows, offset)
 	sqls["orders"] = grammarSQL.CompileOrders(query, query.Orders, offset)
 	sqls["limit"] = grammarSQL.CompileLimit(query, query.Limit, offset)
 	sqls["offset"] = grammarSQL.CompileOffset(query, query.Offset)
 	sqls["lock"] = grammarSQL.CompileLock(query, query.Lock)
 
 	sql := ""
-	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "orders", "limit", "offset", "lock"} {
+	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "windows", "orders", "limit", "offset", "lock"} {
 		segment, has := sqls[name]
 		if has && segment != "" {
 			sql = sql + segment + " "
@@ -70,6 +75,10 @@ func (grammarSQL SQLite3) CompileSelectOffset(query *dbal.Query, offset *int) st
 		sql = fmt.Sprintf("%s %s", grammarSQL.WrapUnion(sql), grammarSQL.CompileUnions(query, query.Unions, offset))
 	}
 
+	if with != "" {
+		sql = fmt.Sprintf("%s %s", with, sql)
+	}
+
 	// reset columns
 	query.Columns = columns
 	return strings.Trim(sql, " ")
@@ -151,6 +160,45 @@ func (grammarSQL SQLite3) WhereDateBased(typ string, query *dbal.Query, where db
 	return fmt.Sprintf("strftime('%s',%s) %s cast(%s as text)", typ, grammarSQL.Wrap(where.Column), where.Operator, value)
 }
 
+// WhereJSONContains Compile a "where JSON contains" clause.
+// The clause is true if each of the given values is the value or one of the items of the selected JSON value.
+func (grammarSQL SQLite3) WhereJSONContains(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	column, path := grammarSQL.WrapJSONPath(where.Column)
+	*bindingOffset = *bindingOffset + where.Offset
+	value := grammarSQL.Parameter(where.Value, *bindingOffset)
+
+	sql := fmt.Sprintf(
+		"json_type(%s, %s) is not null and not exists (select 1 from json_each(%s) as candidate where candidate.value not in (select value from json_each(%s, %s)))",
+		column, path, value, column, path,
+	)
+	if where.Not {
+		return fmt.Sprintf("not (%s)", sql)
+	}
+	return fmt.Sprintf("(%s)", sql)
+}
+
+// WhereJSONLength Compile a "where JSON length" clause.
+func (grammarSQL SQLite3) WhereJSONLength(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	column, path := grammarSQL.WrapJSONPath(where.Column)
+	value := ""
+	if !dbal.IsExpression(where.Value) {
+		*bindingOffset = *bindingOffset + where.Offset
+		value = grammarSQL.Parameter(where.Value, *bindingOffset)
+	} else {
+		value = where.Value.(dbal.Expression).GetValue()
+	}
+	return fmt.Sprintf("json_array_length(%s, %s) %s %s", column, path, where.Operator, value)
+}
+
+// WhereJSONContainsKey Compile a "where JSON contains key" clause.
+func (grammarSQL SQLite3) WhereJSONContainsKey(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	column, path := grammarSQL.WrapJSONPath(where.Column)
+	if where.Not {
+		return fmt.Sprintf("json_type(%s, %s) is null", column, path)
+	}
+	return fmt.Sprintf("json_type(%s, %s) is not null", column, path)
+}
+
 // CompileLock the lock into SQL.
 func (grammarSQL SQLite3) CompileLock(query *dbal.Query, lock interface{}) string {
 	return ""
@@ -7,6 +7,7 @@ import (
 )
 
 // CompileDelete Compile a delete statement into SQL.
+// The joins and the limit are compiled to a subquery selecting the rowid of the rows.
 func (grammarSQL SQLite3) CompileDelete(query *dbal.Query) (string, []interface{}) {
 
 	if len(query.Joins) == 0 && query.Limit < 0 {
@@ -17,17 +18,11 @@ func (grammarSQL SQLite3) CompileDelete(query *dbal.Query) (string, []interface{
 	bindings := []interface{}{}
 	table := grammarSQL.WrapTable(query.From)
 
-	alias := query.From.Alias
-	if alias != "" {
-		query.Columns = []interface{}{fmt.Sprintf("%s.rowid", alias)}
-	} else {
-		query.Columns = []interface{}{"rowid"}
-	}
-
+	query.Columns = []interface{}{grammarSQL.rowid(query)}
 	selectSQL := grammarSQL.CompileSelectOffset(query, &offset)
 
 	bindings = append(bindings, query.GetBindings()...)
-	sql := fmt.Sprintf("delete from  %s where %s in (%s)", table, grammarSQL.Wrap("rowid"), selectSQL)
+	sql := fmt.Sprintf("delete from %s where %s in (%s)", table, grammarSQL.Wrap("rowid"), selectSQL)
 
 	return sql, bindings
 }
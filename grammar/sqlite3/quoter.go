@@ -2,7 +2,9 @@ package sqlite3
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/grammar/sql"
 )
 
@@ -15,3 +17,53 @@ type Quoter struct {
 func (quoter *Quoter) WrapUnion(sql string) string {
 	return fmt.Sprintf("select * from (%s)", sql)
 }
+
+// Wrap a value in keyword identifiers.
+func (quoter *Quoter) Wrap(value interface{}) string {
+	switch value.(type) {
+	case dbal.Expression:
+		return value.(dbal.Expression).GetValue()
+	case dbal.Name:
+		col := value.(dbal.Name)
+		if col.As() != "" {
+			return fmt.Sprintf("%s as %s", quoter.ID(col.Name), quoter.ID(col.As()))
+		}
+		return quoter.ID(value.(dbal.Name).Name)
+	case dbal.Select:
+		col := value.(dbal.Select)
+		if col.Alias != "" {
+			return fmt.Sprintf("%s as %s", col.SQL, quoter.ID(col.Alias))
+		}
+		return fmt.Sprintf("%s ", col.SQL)
+	case string:
+		if sql.IsJSONSelector(value.(string)) {
+			return quoter.WrapJSONSelector(value.(string))
+		}
+		return quoter.WrapAliasedValue(value.(string))
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// WrapJSONSelector Wrap the given JSON selector, json_extract returns the unquoted text value, e.g.
+//    preferences->dining->meal  => json_extract(`preferences`, '$."dining"."meal"')
+//    preferences->dining->>meal => json_extract(`preferences`, '$."dining"."meal"')
+// The JSON functions require the JSON1 extension, build with the sqlite_json1 tag.
+func (quoter *Quoter) WrapJSONSelector(value string) string {
+	selector, alias := sql.SplitAlias(value)
+	column, keys, _ := sql.ParseJSONSelector(selector)
+	res := fmt.Sprintf("json_extract(%s, %s)", quoter.WrapAliasedValue(column), sql.JSONPath(keys))
+	if alias != "" {
+		return fmt.Sprintf("%s as %s", res, quoter.ID(alias))
+	}
+	return res
+}
+
+// Columnize Convert an array of column names into a delimited string.
+func (quoter *Quoter) Columnize(columns []interface{}) string {
+	wrapColumns := []string{}
+	for _, col := range columns {
+		wrapColumns = append(wrapColumns, quoter.Wrap(col))
+	}
+	return strings.Join(wrapColumns, ", ")
+}
@@ -1,6 +1,7 @@
 package sqlite3
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
@@ -8,17 +9,23 @@ import (
 	"strings"
 
 	"github.com/blang/semver/v4"
+	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/utils"
 )
 
-// GetVersion get the version of the connection database
+// GetVersion get the version of the connection database, the version is cached per connection.
 func (grammarSQL SQLite3) GetVersion() (*dbal.Version, error) {
+	return grammarSQL.CacheVersion(grammarSQL.queryVersion)
+}
+
+// queryVersion query the version of the connection database
+func (grammarSQL SQLite3) queryVersion() (*dbal.Version, error) {
 	sql := fmt.Sprintf("SELECT SQLITE_VERSION()")
 	// defer logger.Debug(logger.RETRIEVE, sql).TimeCost(time.Now())
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &rows, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +49,7 @@ func (grammarSQL SQLite3) GetTables() ([]string, error) {
 	sql := fmt.Sprintf("SELECT `name` FROM `sqlite_master` WHERE type='table'")
 	defer log.Debug(sql)
 	tables := []string{}
-	err := grammarSQL.DB.Select(&tables, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &tables, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +61,7 @@ func (grammarSQL SQLite3) TableExists(name string) (bool, error) {
 	sql := fmt.Sprintf("SELECT `name` FROM `sqlite_master` WHERE type='table' AND name=%s", grammarSQL.VAL(name))
 	defer log.Debug(sql)
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &rows, sql)
 	if err != nil {
 		return false, err
 	}
@@ -74,6 +81,7 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table) error {
 	var primary *dbal.Primary = nil
 	columns := []*dbal.Column{}
 	indexes := []*dbal.Index{}
+	foreigns := []*dbal.Foreign{}
 	cbCommands := []*dbal.Command{}
 
 	// Commands
@@ -85,6 +93,7 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table) error {
 	//    CreateIndex(index *Index) for creating a index
 	//    DropIndex( name string) for  dropping a index
 	//    RenameIndex(old string,new string)  for renaming a index
+	//    CreateForeign(foreign *Foreign) for creating a foreign key
 	for _, command := range table.Commands {
 		switch command.Name {
 		case "AddColumn":
@@ -98,6 +107,9 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table) error {
 		case "CreatePrimary":
 			primary = command.Params[0].(*dbal.Primary)
 			cbCommands = append(cbCommands, command)
+		case "CreateForeign":
+			foreigns = append(foreigns, command.Params[0].(*dbal.Foreign))
+			cbCommands = append(cbCommands, command)
 		}
 	}
 
@@ -115,12 +127,19 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table) error {
 		)
 	}
 
+	// Foreign keys
+	for _, foreign := range foreigns {
+		stmts = append(stmts,
+			grammarSQL.SQLAddForeign(foreign),
+		)
+	}
+
 	sql = sql + strings.Join(stmts, ",\n")
 	sql = sql + fmt.Sprintf("\n)")
 
 	// Create table
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql)
 	if err != nil {
 		return err
 	}
@@ -133,7 +152,7 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table) error {
 		)
 	}
 	defer log.Debug(strings.Join(indexStmts, ";\n"))
-	_, err = grammarSQL.DB.Exec(strings.Join(indexStmts, ";\n"))
+	_, err = grammarSQL.Executor().ExecContext(grammarSQL.Context(), strings.Join(indexStmts, ";\n"))
 
 	for _, cmd := range cbCommands {
 		cmd.Callback(err)
@@ -150,7 +169,7 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table) error {
 func (grammarSQL SQLite3) RenameTable(old string, new string) error {
 	sql := fmt.Sprintf("ALTER TABLE %s RENAME TO %s", grammarSQL.ID(old), grammarSQL.ID(new))
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql)
 	return err
 }
 
@@ -177,6 +196,11 @@ func (grammarSQL SQLite3) GetTable(name string) (*dbal.Table, error) {
 		return nil, err
 	}
 
+	foreigns, err := grammarSQL.GetForeignListing(table.DBName, table.TableName)
+	if err != nil {
+		return nil, err
+	}
+
 	primaryKeyName := ""
 
 	// attaching columns
@@ -185,6 +209,23 @@ func (grammarSQL SQLite3) GetTable(name string) (*dbal.Table, error) {
 		table.PushColumn(column)
 	}
 
+	// attaching foreign keys
+	for i := range foreigns {
+		fk := foreigns[i]
+		if !table.HasColumn(fk.ColumnName) {
+			return nil, fmt.Errorf("the column %s does not exists", fk.ColumnName)
+		}
+		if !table.HasForeign(fk.Name) {
+			foreign := *fk
+			foreign.Columns = []*dbal.Column{}
+			foreign.References = []string{}
+			table.PushForeign(&foreign)
+		}
+		foreign := table.ForeignMap[fk.Name]
+		foreign.AddColumn(table.ColumnMap[fk.ColumnName])
+		foreign.References = append(foreign.References, fk.ReferenceColumnName)
+	}
+
 	// attaching indexes
 	for i := range indexes {
 		idx := indexes[i]
@@ -269,8 +310,8 @@ func (grammarSQL SQLite3) GetIndexListing(dbName string, tableName string) ([]*d
 				"primary" as index_type,
 				1 as `+"`unique`"+`,
 				0 as `+"`seq_in_index`"+`,
-				0 as `+"`seq_in_column`"+`
-			FROM pragma_table_info(%s) AS ti WHERE ti.pk=1
+				ti.pk as `+"`seq_in_column`"+`
+			FROM pragma_table_info(%s) AS ti WHERE ti.pk > 0
 			ORDER BY seq_in_index,index_name,seq_in_column
 		`,
 		strings.Join(selectColumns, ","),
@@ -280,7 +321,7 @@ func (grammarSQL SQLite3) GetIndexListing(dbName string, tableName string) ([]*d
 	)
 	defer log.Debug(sql)
 	indexes := []*dbal.Index{}
-	err := grammarSQL.DB.Select(&indexes, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &indexes, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -296,6 +337,84 @@ func (grammarSQL SQLite3) GetIndexListing(dbName string, tableName string) ([]*d
 	return indexes, nil
 }
 
+// GetForeignListing get a table foreign keys structure
+func (grammarSQL SQLite3) GetForeignListing(dbName string, tableName string) ([]*dbal.Foreign, error) {
+	selectColumns := []string{
+		"fk.`id` as `id`",
+		"fk.`table` as reference_table_name",
+		"fk.`from` as column_name",
+		"fk.`to` as reference_column_name",
+		"fk.`on_delete` as on_delete",
+		"fk.`on_update` as on_update",
+		"(fk.`seq` + 1) as seq_in_foreign",
+	}
+	sql := fmt.Sprintf(
+		"SELECT %s FROM pragma_foreign_key_list(%s) AS fk ORDER BY fk.`id`, fk.`seq`",
+		strings.Join(selectColumns, ","),
+		grammarSQL.VAL(tableName),
+	)
+	defer log.Debug(sql)
+
+	rows := []struct {
+		ID int `db:"id"`
+		dbal.Foreign
+	}{}
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &rows, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	// the pragma does not return the constraint name, parse it from the table definition
+	names, err := grammarSQL.getForeignNames(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[int][]string{}
+	for _, row := range rows {
+		columns[row.ID] = append(columns[row.ID], row.ColumnName)
+	}
+
+	foreigns := []*dbal.Foreign{}
+	for i := range rows {
+		foreign := rows[i].Foreign
+		key := strings.Join(columns[rows[i].ID], ",")
+		name, has := names[key]
+		if !has {
+			name = fmt.Sprintf("%s_%s_foreign", tableName, strings.Join(columns[rows[i].ID], "_"))
+		}
+		foreign.DBName = dbName
+		foreign.TableName = tableName
+		foreign.Name = strings.TrimPrefix(name, tableName+"_")
+		foreigns = append(foreigns, &foreign)
+	}
+	return foreigns, nil
+}
+
+// getForeignNames get the foreign key names of the table, keyed by the column names
+func (grammarSQL SQLite3) getForeignNames(tableName string) (map[string]string, error) {
+	rows := []string{}
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &rows, "SELECT `sql` FROM sqlite_master WHERE type='table' and name=?", tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]string{}
+	if len(rows) < 1 {
+		return names, nil
+	}
+
+	re := regexp.MustCompile("CONSTRAINT [`\"]?([0-9a-zA-Z_]+)[`\"]? FOREIGN KEY \\(([^)]+)\\)")
+	for _, matched := range re.FindAllStringSubmatch(rows[0], -1) {
+		columns := strings.Split(matched[2], ",")
+		for i := range columns {
+			columns[i] = strings.Trim(columns[i], " `\"")
+		}
+		names[strings.Join(columns, ",")] = matched[1]
+	}
+	return names, nil
+}
+
 // GetColumnListing get a table columns structure
 func (grammarSQL SQLite3) GetColumnListing(schemaName string, tableName string) ([]*dbal.Column, error) {
 	selectColumns := []string{
@@ -333,7 +452,7 @@ func (grammarSQL SQLite3) GetColumnListing(schemaName string, tableName string)
 	)
 	defer log.Debug(sql)
 	columns := []*dbal.Column{}
-	err := grammarSQL.DB.Select(&columns, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &columns, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -383,6 +502,13 @@ func (grammarSQL SQLite3) AlterTable(table *dbal.Table) error {
 	//    CreateIndex(index *Index) for creating a index
 	//    DropIndex(name string) for  dropping a index
 	//    RenameIndex(old string,new string)  for renaming a index
+	//    CreatePrimary(primary *Primary) for creating the primary key
+	//    DropPrimary(name string, columns []*Column) for dropping the primary key
+	//    CreateForeign(foreign *Foreign) for creating a foreign key
+	//    DropForeign(name string) for dropping a foreign key
+	// SQLite does not support the statements modifying the column, primary key and foreign key,
+	// these commands are applied by rebuilding the table. The columns are dropped by the ALTER TABLE statement
+	// if SQLite supports it and the indexes are renamed by recreating them.
 	for _, command := range table.Commands {
 		switch command.Name {
 		case "AddColumn":
@@ -431,8 +557,14 @@ func (grammarSQL SQLite3) AlterTable(table *dbal.Table) error {
 			}
 			command.Callback(err)
 			break
-		case "DropColumn", "ChangeColumn", "DropPrimary", "RenameIndex":
-			log.Warn("sqlite3 not support %s operation", command.Name)
+		case "DropColumn":
+			grammarSQL.alterTableDropColumn(table, command, sql, &stmts, &errs)
+			break
+		case "RenameIndex":
+			grammarSQL.alterTableRenameIndex(table, command, &stmts, &errs)
+			break
+		case "ChangeColumn", "CreatePrimary", "DropPrimary", "CreateForeign", "DropForeign":
+			grammarSQL.alterTableRebuild(table, command, &stmts, &errs)
 			break
 		}
 	}
@@ -450,10 +582,479 @@ func (grammarSQL SQLite3) AlterTable(table *dbal.Table) error {
 	return nil
 }
 
+// alterTableDropColumn drop the column by the ALTER TABLE statement if SQLite supports it (3.35.0+),
+// the column of the primary key, the indexes or the foreign keys is dropped by rebuilding the table.
+func (grammarSQL SQLite3) alterTableDropColumn(table *dbal.Table, command *dbal.Command, sql string, stmts *[]string, errs *[]error) {
+	name := command.Params[0].(string)
+	native, err := grammarSQL.canDropColumn(table.TableName, name)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %s", command.Name, err))
+		command.Callback(err)
+		return
+	}
+
+	if !native {
+		grammarSQL.alterTableRebuild(table, command, stmts, errs)
+		return
+	}
+
+	stmt := fmt.Sprintf("%sDROP COLUMN %s", sql, grammarSQL.ID(name))
+	*stmts = append(*stmts, stmt)
+	err = grammarSQL.ExecSQL(table, stmt)
+	if err != nil {
+		*errs = append(*errs, errors.New("SQL: "+stmt+" ERROR: "+err.Error()))
+	}
+	command.Callback(err)
+}
+
+// canDropColumn check if the column can be dropped by the ALTER TABLE statement
+func (grammarSQL SQLite3) canDropColumn(tableName string, name string) (bool, error) {
+	version, err := grammarSQL.GetVersion()
+	if err != nil {
+		return false, err
+	}
+	if version.LT(semver.MustParse("3.35.0")) {
+		return false, nil
+	}
+
+	current, err := grammarSQL.GetTable(tableName)
+	if err != nil {
+		return false, err
+	}
+	if !current.HasColumn(name) {
+		return false, fmt.Errorf("the column %s does not exists", name)
+	}
+
+	if current.Primary != nil && len(withoutColumn(current.Primary.Columns, name)) != len(current.Primary.Columns) {
+		return false, nil
+	}
+	for _, index := range current.IndexMap {
+		if len(withoutColumn(index.Columns, name)) != len(index.Columns) {
+			return false, nil
+		}
+	}
+	for _, foreign := range current.ForeignMap {
+		if len(withoutColumn(foreign.Columns, name)) != len(foreign.Columns) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// alterTableRenameIndex rename the index by dropping it and creating it with the new name,
+// the index created by the unique constraint is renamed by rebuilding the table.
+func (grammarSQL SQLite3) alterTableRenameIndex(table *dbal.Table, command *dbal.Command, stmts *[]string, errs *[]error) {
+	old := command.Params[0].(string)
+	new := command.Params[1].(string)
+	if strings.HasPrefix(old, "sqlite_autoindex_") {
+		grammarSQL.alterTableRebuild(table, command, stmts, errs)
+		return
+	}
+
+	current, err := grammarSQL.GetTable(table.TableName)
+	if err == nil && !current.HasIndex(old) {
+		err = fmt.Errorf("the index %s does not exists", old)
+	}
+	if err == nil && current.HasIndex(new) {
+		err = fmt.Errorf("the index %s already exists", new)
+	}
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %s", command.Name, err))
+		command.Callback(err)
+		return
+	}
+
+	index := current.IndexMap[old]
+	index.Name = new
+	index.TableName = table.TableName
+	sqls := []string{
+		fmt.Sprintf("DROP INDEX %s", grammarSQL.ID(fmt.Sprintf("%s_%s", table.TableName, old))),
+		grammarSQL.SQLAddIndex(index),
+	}
+	*stmts = append(*stmts, sqls...)
+	err = grammarSQL.Transact(func(tx *sqlx.Tx) error {
+		return execStatements(grammarSQL.Context(), tx, sqls)
+	})
+	if err == nil {
+		current, err = grammarSQL.GetTable(table.TableName)
+		if err == nil {
+			*table = *current
+		}
+	}
+
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %s", command.Name, err))
+	}
+	command.Callback(err)
+}
+
+// alterTableRebuild apply the command by rebuilding the table:
+// create a new table with the altered structure, copy the rows, drop the old table,
+// rename the new table and recreate the indexes.
+func (grammarSQL SQLite3) alterTableRebuild(table *dbal.Table, command *dbal.Command, stmts *[]string, errs *[]error) {
+	current, err := grammarSQL.GetTable(table.TableName)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %s", command.Name, err))
+		command.Callback(err)
+		return
+	}
+
+	// the columns of the old table, the rows of these columns will be copied
+	columns := []string{}
+	for _, column := range current.Columns {
+		columns = append(columns, column.Name)
+		if column.Extra != nil && *column.Extra == "" {
+			column.Extra = nil
+		}
+		if column.Default != nil {
+			column.DefaultRaw = grammarSQL.rawDefault(column.Default)
+			column.Default = nil
+		}
+	}
+
+	origin := grammarSQL.sqlRebuildTable(current, columns)
+	err = grammarSQL.rebuildApply(current, command)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %s", command.Name, err))
+		command.Callback(err)
+		return
+	}
+
+	// the command does not change the table structure, e.g. changing the comment of the column
+	sqls := grammarSQL.sqlRebuildTable(current, columns)
+	if strings.Join(origin, ";\n") == strings.Join(sqls, ";\n") {
+		command.Callback(nil)
+		return
+	}
+
+	*stmts = append(*stmts, sqls...)
+	err = grammarSQL.execRebuild(table.TableName, sqls)
+	if err == nil {
+		var new *dbal.Table
+		new, err = grammarSQL.GetTable(table.TableName)
+		if err == nil {
+			*table = *new
+		}
+	}
+
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %s", command.Name, err))
+	}
+	command.Callback(err)
+}
+
+// rebuildApply apply the command to the table structure
+func (grammarSQL SQLite3) rebuildApply(table *dbal.Table, command *dbal.Command) error {
+	switch command.Name {
+	case "DropColumn":
+		name := command.Params[0].(string)
+		if !table.HasColumn(name) {
+			return fmt.Errorf("the column %s does not exists", name)
+		}
+		delete(table.ColumnMap, name)
+
+		// remove the column from the indexes, primary key and foreign keys
+		for _, index := range table.IndexMap {
+			index.Columns = withoutColumn(index.Columns, name)
+			if len(index.Columns) == 0 {
+				delete(table.IndexMap, index.Name)
+			}
+		}
+		if table.Primary != nil {
+			table.Primary.Columns = withoutColumn(table.Primary.Columns, name)
+			if len(table.Primary.Columns) == 0 {
+				table.Primary = nil
+			}
+		}
+		for _, foreign := range table.Foreigns {
+			for _, column := range foreign.Columns {
+				if column.Name == name {
+					delete(table.ForeignMap, foreign.Name)
+				}
+			}
+		}
+		break
+
+	case "ChangeColumn":
+		column := command.Params[0].(*dbal.Column)
+		if !table.HasColumn(column.Name) {
+			return fmt.Errorf("the column %s does not exists", column.Name)
+		}
+		for i := range table.Columns {
+			if table.Columns[i].Name == column.Name {
+				table.Columns[i] = column
+			}
+		}
+		table.ColumnMap[column.Name] = column
+		break
+
+	case "RenameIndex":
+		old := command.Params[0].(string)
+		new := command.Params[1].(string)
+		index, has := table.IndexMap[old]
+		if !has {
+			return fmt.Errorf("the index %s does not exists", old)
+		}
+		if table.HasIndex(new) {
+			return fmt.Errorf("the index %s already exists", new)
+		}
+		delete(table.IndexMap, old)
+		index.Name = new
+		table.IndexMap[new] = index
+		break
+
+	case "CreatePrimary":
+		primary := command.Params[0].(*dbal.Primary)
+		if table.Primary != nil {
+			return fmt.Errorf("the table %s already has a primary key", table.TableName)
+		}
+		columns := []*dbal.Column{}
+		for _, column := range primary.Columns {
+			if !table.HasColumn(column.Name) {
+				return fmt.Errorf("the column %s does not exists", column.Name)
+			}
+			columns = append(columns, table.ColumnMap[column.Name])
+		}
+		table.Primary = table.NewPrimary(primary.Name, columns...)
+		break
+
+	case "DropPrimary":
+		table.Primary = nil
+		break
+
+	case "CreateForeign":
+		foreign := command.Params[0].(*dbal.Foreign)
+		if table.HasForeign(foreign.Name) {
+			return fmt.Errorf("the foreign key %s already exists", foreign.Name)
+		}
+		for _, column := range foreign.Columns {
+			if !table.HasColumn(column.Name) {
+				return fmt.Errorf("the column %s does not exists", column.Name)
+			}
+		}
+		table.PushForeign(foreign)
+		break
+
+	case "DropForeign":
+		name := command.Params[0].(string)
+		if !table.HasForeign(name) {
+			return fmt.Errorf("the foreign key %s does not exists", name)
+		}
+		delete(table.ForeignMap, name)
+		break
+	}
+	return nil
+}
+
+// sqlRebuildTable return the statements rebuilding the table, the rows of the given columns will be copied.
+func (grammarSQL SQLite3) sqlRebuildTable(table *dbal.Table, copyColumns []string) []string {
+	name := table.TableName
+	temp := fmt.Sprintf("__temp__%s", name)
+
+	primaryColumns := map[string]bool{}
+	if table.Primary != nil {
+		for _, column := range table.Primary.Columns {
+			primaryColumns[column.Name] = true
+		}
+	}
+
+	// Columns
+	stmts := []string{}
+	columns := []string{}
+	for _, column := range table.Columns {
+		if table.ColumnMap[column.Name] != column {
+			continue
+		}
+
+		// the single column primary key is defined inline, the auto increment requires it.
+		col := *column
+		col.Primary = len(primaryColumns) == 1 && primaryColumns[col.Name]
+		if !col.Primary {
+			col.Extra = nil
+		}
+		stmts = append(stmts, grammarSQL.SQLAddColumn(&col))
+		for _, copyColumn := range copyColumns {
+			if copyColumn == col.Name {
+				columns = append(columns, grammarSQL.ID(col.Name))
+			}
+		}
+	}
+
+	// Primary key
+	if len(primaryColumns) > 1 {
+		stmts = append(stmts, grammarSQL.SQLAddPrimary(table.Primary))
+	}
+
+	// Unique constraints, the indexes of them are created by SQLite automatically
+	for _, index := range table.Indexes {
+		if table.IndexMap[index.Name] != index || !strings.HasPrefix(index.Name, "sqlite_autoindex_") || index.Type != "unique" {
+			continue
+		}
+		names := []string{}
+		for _, column := range index.Columns {
+			names = append(names, grammarSQL.ID(column.Name))
+		}
+		stmts = append(stmts, fmt.Sprintf("UNIQUE (%s)", strings.Join(names, ",")))
+	}
+
+	// Foreign keys
+	for _, foreign := range table.Foreigns {
+		if table.ForeignMap[foreign.Name] == foreign {
+			stmts = append(stmts, grammarSQL.SQLAddForeign(foreign))
+		}
+	}
+
+	sqls := []string{
+		fmt.Sprintf("CREATE TABLE %s (\n%s\n)", grammarSQL.ID(temp), strings.Join(stmts, ",\n")),
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+			grammarSQL.ID(temp), strings.Join(columns, ","),
+			strings.Join(columns, ","), grammarSQL.ID(name)),
+		fmt.Sprintf("DROP TABLE %s", grammarSQL.ID(name)),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", grammarSQL.ID(temp), grammarSQL.ID(name)),
+	}
+
+	// Indexes
+	for _, index := range table.Indexes {
+		if table.IndexMap[index.Name] != index || strings.HasPrefix(index.Name, "sqlite_autoindex_") {
+			continue
+		}
+		index.TableName = name
+		if sql := grammarSQL.SQLAddIndex(index); sql != "" {
+			sqls = append(sqls, sql)
+		}
+	}
+	return sqls
+}
+
+// execRebuild execute the statements rebuilding the table within a transaction, the foreign key constraints are deferred.
+// If the grammar is not bound to a transaction, the foreign key constraints are disabled while rebuilding the table,
+// so that dropping the old table does not trigger the ON DELETE actions of the tables referencing it.
+// The PRAGMA foreign_keys is a no-op within a transaction, so the table referenced by the others can not be rebuilt
+// within a transaction while the foreign key constraints are enabled.
+func (grammarSQL SQLite3) execRebuild(name string, sqls []string) error {
+	ctx := grammarSQL.Context()
+	if grammarSQL.Tx != nil {
+		tables, err := grammarSQL.referencingTables(ctx, grammarSQL.Tx, name)
+		if err != nil {
+			return err
+		}
+		if len(tables) > 0 {
+			return fmt.Errorf("the table %s is referenced by the foreign keys of the %s table, it can not be rebuilt within a transaction while the foreign key constraints are enabled", name, tables[0])
+		}
+		return execStatements(ctx, grammarSQL.Tx, append([]string{"PRAGMA defer_foreign_keys = ON"}, sqls...))
+	}
+
+	conn, err := grammarSQL.DB.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	enabled := false
+	err = conn.GetContext(ctx, &enabled, "PRAGMA foreign_keys")
+	if err != nil {
+		return err
+	}
+
+	if enabled {
+		_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
+		if err != nil {
+			return err
+		}
+		defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	}
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	err = execStatements(ctx, tx, append([]string{"PRAGMA defer_foreign_keys = ON"}, sqls...))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if enabled {
+		violations := []struct {
+			Table  string `db:"table"`
+			RowID  *int64 `db:"rowid"`
+			Parent string `db:"parent"`
+			FKID   int    `db:"fkid"`
+		}{}
+		err = tx.SelectContext(ctx, &violations, "PRAGMA foreign_key_check")
+		if err == nil && len(violations) > 0 {
+			err = fmt.Errorf("the foreign key constraint of the table %s failed", violations[0].Table)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// referencingTables get the other tables referencing the table by the foreign keys, nil is returned if the foreign key constraints are disabled.
+func (grammarSQL SQLite3) referencingTables(ctx context.Context, tx *sqlx.Tx, name string) ([]string, error) {
+	enabled := false
+	err := tx.GetContext(ctx, &enabled, "PRAGMA foreign_keys")
+	if err != nil || !enabled {
+		return nil, err
+	}
+
+	tables := []string{}
+	err = tx.SelectContext(ctx, &tables,
+		"SELECT DISTINCT m.`name` FROM sqlite_master AS m, pragma_foreign_key_list(m.`name`) AS fk "+
+			"WHERE m.`type` = 'table' AND m.`name` <> ? AND fk.`table` = ? COLLATE NOCASE",
+		name, name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+// execStatements execute the statements one by one
+func execStatements(ctx context.Context, tx *sqlx.Tx, sqls []string) error {
+	for _, sql := range sqls {
+		log.Debug(sql)
+		_, err := tx.ExecContext(ctx, sql)
+		if err != nil {
+			return fmt.Errorf("SQL: %s ERROR: %s", sql, err)
+		}
+	}
+	return nil
+}
+
+// rawDefault return the default value expression read from the table structure
+func (grammarSQL SQLite3) rawDefault(value interface{}) string {
+	raw := fmt.Sprintf("%v", value)
+	if bytes, ok := value.([]byte); ok {
+		raw = string(bytes)
+	}
+	// the expression must be wrapped in parentheses, e.g. datetime('now','localtime')
+	if strings.Contains(raw, "(") && !strings.HasPrefix(raw, "(") {
+		raw = fmt.Sprintf("(%s)", raw)
+	}
+	return raw
+}
+
+// withoutColumn return the columns without the given column
+func withoutColumn(columns []*dbal.Column, name string) []*dbal.Column {
+	res := []*dbal.Column{}
+	for _, column := range columns {
+		if column.Name != name {
+			res = append(res, column)
+		}
+	}
+	return res
+}
+
 // GetConstraintListing get the constraints of the table
 func (grammarSQL SQLite3) GetConstraintListing(schemaName string, tableName string) (map[string]*dbal.Constraint, error) {
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, "SELECT `sql` FROM sqlite_master WHERE type='table' and name=?", tableName)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context(), &rows, "SELECT `sql` FROM sqlite_master WHERE type='table' and name=?", tableName)
 	if err != nil {
 		return nil, err
 	}
@@ -499,7 +1100,7 @@ func (grammarSQL SQLite3) parseConstraint(schemaName string, tableName string, l
 
 // ExecSQL execute sql then update table structure
 func (grammarSQL SQLite3) ExecSQL(table *dbal.Table, sql string) error {
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context(), sql)
 	if err != nil {
 		return err
 	}
@@ -546,7 +1147,7 @@ func (grammarSQL SQLite3) ParseType(column *dbal.Column) {
 				column.DateTimePrecision = utils.IntPtr(precision)
 			}
 			break
-		case "float":
+		case "float", "double", "decimal":
 			if len(args) > 0 {
 				precision, _ := strconv.Atoi(args[0])
 				column.Precision = utils.IntPtr(precision)
@@ -1,6 +1,7 @@
 package sqlite3
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"path/filepath"
@@ -82,6 +83,18 @@ func (grammarSQL SQLite3) NewWithRead(write *sqlx.DB, writeConfig *dbal.Config,
 	return grammarSQL, nil
 }
 
+// WithTx Create a new grammar interface, the statements will be executed on the given transaction.
+func (grammarSQL SQLite3) WithTx(tx *sqlx.Tx) dbal.Grammar {
+	grammarSQL.Tx = tx
+	return grammarSQL
+}
+
+// WithContext Create a new grammar interface, the statements will be executed with the given context.
+func (grammarSQL SQLite3) WithContext(ctx context.Context) dbal.Grammar {
+	grammarSQL.Ctx = ctx
+	return grammarSQL
+}
+
 // New Create a new mysql grammar inteface
 func New() dbal.Grammar {
 	sqlite := SQLite3{
@@ -0,0 +1,18 @@
+package sqlite3
+
+import "fmt"
+
+// CompileSavepoint Compile the SQL statement to define a savepoint.
+func (grammarSQL SQLite3) CompileSavepoint(name string) string {
+	return fmt.Sprintf("savepoint %s", grammarSQL.ID(name))
+}
+
+// CompileReleaseSavepoint Compile the SQL statement to release a savepoint.
+func (grammarSQL SQLite3) CompileReleaseSavepoint(name string) string {
+	return fmt.Sprintf("release savepoint %s", grammarSQL.ID(name))
+}
+
+// CompileRollbackToSavepoint Compile the SQL statement to execute a savepoint rollback.
+func (grammarSQL SQLite3) CompileRollbackToSavepoint(name string) string {
+	return fmt.Sprintf("rollback transaction to savepoint %s", grammarSQL.ID(name))
+}
@@ -6,19 +6,29 @@ import (
 	"strings"
 
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/grammar/sql"
 )
 
 // CompileUpsert Upsert new records or update the existing ones.
+// The conflicting rows are skipped (do nothing) if there are no update values.
 func (grammarSQL SQLite3) CompileUpsert(query *dbal.Query, columns []interface{}, values [][]interface{}, uniqueBy []interface{}, updateValues interface{}) (string, []interface{}) {
 
 	if len(values) == 0 {
 		return fmt.Sprintf("insert into %s default values", grammarSQL.WrapTable(query.From)), []interface{}{}
 	}
 
+	if query.Conflict.Constraint != "" {
+		panic(fmt.Errorf("This database engine does not support the conflict target by constraint name"))
+	}
+
 	sql, bindings := grammarSQL.CompileInsert(query, columns, values)
-	sql = fmt.Sprintf("%s on conflict (%s) do update set", sql, grammarSQL.Columnize(uniqueBy))
-	offset := len(bindings) + 1
+	if len(uniqueBy) > 0 {
+		sql = fmt.Sprintf("%s on conflict (%s)", sql, grammarSQL.Columnize(uniqueBy))
+	} else {
+		sql = fmt.Sprintf("%s on conflict", sql)
+	}
 
+	offset := len(bindings) + 1
 	update := reflect.ValueOf(updateValues)
 	kind := update.Kind()
 	segments := []string{}
@@ -39,14 +49,32 @@ func (grammarSQL SQLite3) CompileUpsert(query *dbal.Query, columns []interface{}
 		}
 	}
 
-	return fmt.Sprintf("%s %s", sql, strings.Join(segments, ", ")), bindings
+	if len(segments) == 0 {
+		return fmt.Sprintf("%s do nothing", sql), bindings
+	}
+
+	sql = fmt.Sprintf("%s do update set %s", sql, strings.Join(segments, ", "))
+	if where := query.Conflict.Where; where != nil && len(where.Wheres) > 0 {
+		offset = offset - 1
+		sql = fmt.Sprintf("%s %s", sql, grammarSQL.CompileWheres(where, where.Wheres, &offset))
+		bindings = append(bindings, where.GetBindings("where")...)
+	}
+
+	return sql, bindings
 }
 
 // CompileUpdate Compile an update statement into SQL.
+// The joins and the limit are compiled to a subquery selecting the rowid of the rows, e.g.
+//    update `orders` set `status`=? where `rowid` in (select `orders`.rowid from `orders` inner join `users` on ... where `users`.`banned` = ?)
+// So the update values could not refer to the columns of the joined tables.
 func (grammarSQL SQLite3) CompileUpdate(query *dbal.Query, values map[string]interface{}) (string, []interface{}) {
 
 	if len(query.Joins) == 0 && query.Limit < 0 {
-		return grammarSQL.SQL.CompileUpdate(query, values)
+		offset := 0
+		columns, bindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
+		wheres := grammarSQL.CompileWheres(query, query.Wheres, &offset)
+		bindings = append(bindings, query.GetBindings("where")...)
+		return fmt.Sprintf("update %s set %s %s", grammarSQL.WrapTable(query.From), columns, wheres), bindings
 	}
 
 	offset := 0
@@ -56,13 +84,7 @@ func (grammarSQL SQLite3) CompileUpdate(query *dbal.Query, values map[string]int
 	columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
 	bindings = append(bindings, columnsBindings...)
 
-	alias := query.From.Alias
-	if alias != "" {
-		query.Columns = []interface{}{fmt.Sprintf("%s.rowid", alias)}
-	} else {
-		query.Columns = []interface{}{"rowid"}
-	}
-
+	query.Columns = []interface{}{grammarSQL.rowid(query)}
 	selectSQL := grammarSQL.CompileSelectOffset(query, &offset)
 
 	bindings = append(bindings, query.GetBindings()...)
@@ -70,3 +92,49 @@ func (grammarSQL SQLite3) CompileUpdate(query *dbal.Query, values map[string]int
 
 	return sql, bindings
 }
+
+// rowid get the rowid column of the table, qualified by the alias or the name of the table
+// to avoid the ambiguous references of the joined tables.
+func (grammarSQL SQLite3) rowid(query *dbal.Query) dbal.Expression {
+	if query.From.Alias != "" {
+		return dbal.Raw(fmt.Sprintf("%s.rowid", grammarSQL.ID(query.From.Alias)))
+	}
+	return dbal.Raw(fmt.Sprintf("%s.rowid", grammarSQL.WrapTable(query.From)))
+}
+
+// CompileUpdateColumns Compile the columns for an update statement.
+// The JSON selectors of the same column are compiled to one json_set call, the NULL column is updated as an empty object, e.g.
+//    options->enabled, options->theme => `options`=json_set(coalesce(`options`, '{}'), '$."enabled"', json(?), '$."theme"', json(?))
+func (grammarSQL SQLite3) CompileUpdateColumns(query *dbal.Query, values map[string]interface{}, offset *int) (string, []interface{}) {
+	plain := map[string]interface{}{}
+	for key, value := range values {
+		if !sql.IsJSONSelector(key) {
+			plain[key] = value
+		}
+	}
+
+	columns, bindings := grammarSQL.SQL.CompileUpdateColumns(query, plain, offset)
+	segments := []string{}
+	if columns != "" {
+		segments = append(segments, columns)
+	}
+
+	names, selectors := sql.JSONUpdateColumns(values)
+	for _, name := range names {
+		args := []string{fmt.Sprintf("coalesce(%s, '{}')", grammarSQL.Wrap(name))}
+		for _, selector := range selectors[name] {
+			_, keys, _ := sql.ParseJSONSelector(selector)
+			value := sql.JSONUpdateValue(values[selector])
+			param := grammarSQL.Parameter(value, *offset+1)
+			if !dbal.IsExpression(value) {
+				param = fmt.Sprintf("json(%s)", param)
+				bindings = append(bindings, value)
+				*offset++
+			}
+			args = append(args, sql.JSONPath(keys), param)
+		}
+		segments = append(segments, fmt.Sprintf("%s=json_set(%s)", grammarSQL.Wrap(name), strings.Join(args, ", ")))
+	}
+
+	return strings.Join(segments, ", "), bindings
+}
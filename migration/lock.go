@@ -0,0 +1,115 @@
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/utils"
+)
+
+// lock acquire the migration lock, so that only one process can migrate at a time.
+// The lock row is inserted into the lock table with the owner, the insertion is ignored if the lock is held by another process.
+// It waits for the lock until the LockWait duration elapses, the lock held longer than the LockTimeout is broken.
+func (migrator *Migrator) lock() error {
+	deadline := time.Now().Add(migrator.LockWait)
+	for {
+		affected, err := migrator.Query.New().Table(migrator.LockTable).InsertOrIgnore(xun.R{"id": 1, "owner": migrator.Owner, "locked_at": time.Now()})
+		if err != nil {
+			return err
+		}
+
+		if affected > 0 {
+			return nil
+		}
+
+		broken, err := migrator.breakStaleLock()
+		if err != nil {
+			return err
+		}
+
+		if broken {
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			row, err := migrator.Query.New().Table(migrator.LockTable).Where("id", 1).First()
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("the migrations are locked by %v since %v, call Unlock to release the lock if the process is dead", row.Get("owner"), row.Get("locked_at"))
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// breakStaleLock delete the lock held longer than the LockTimeout, returns true if the lock is broken.
+func (migrator *Migrator) breakStaleLock() (bool, error) {
+	if migrator.LockTimeout <= 0 {
+		return false, nil
+	}
+
+	affected, err := migrator.Query.New().Table(migrator.LockTable).
+		Where("id", 1).
+		Where("locked_at", "<", time.Now().Add(-migrator.LockTimeout)).
+		Delete()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// release release the migration lock held by the migrator
+func (migrator *Migrator) release() error {
+	_, err := migrator.Query.New().Table(migrator.LockTable).Where("id", 1).Where("owner", migrator.Owner).Delete()
+	return err
+}
+
+// Unlock release the migration lock whoever holds it
+func (migrator *Migrator) Unlock() error {
+	_, err := migrator.Query.New().Table(migrator.LockTable).Where("id", 1).Delete()
+	return err
+}
+
+// MustUnlock release the migration lock whoever holds it
+func (migrator *Migrator) MustUnlock() {
+	err := migrator.Unlock()
+	utils.PanicIF(err)
+}
+
+// IsLocked Determine if the migrations are locked by a process
+func (migrator *Migrator) IsLocked() (bool, error) {
+	err := migrator.prepare()
+	if err != nil {
+		return false, err
+	}
+	return migrator.Query.New().Table(migrator.LockTable).Where("id", 1).Exists()
+}
+
+// MustIsLocked Determine if the migrations are locked by a process
+func (migrator *Migrator) MustIsLocked() bool {
+	locked, err := migrator.IsLocked()
+	utils.PanicIF(err)
+	return locked
+}
+
+// withLock run the callback holding the migration lock, the error releasing the lock is returned if the callback succeeds.
+func (migrator *Migrator) withLock(callback func() error) (err error) {
+	err = migrator.prepare()
+	if err != nil {
+		return err
+	}
+
+	err = migrator.lock()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		errRelease := migrator.release()
+		if err == nil && errRelease != nil {
+			err = fmt.Errorf("release the migration lock: %s", errRelease)
+		}
+	}()
+	return callback()
+}
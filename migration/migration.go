@@ -0,0 +1,79 @@
+package migration
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/xun/dbal/schema"
+)
+
+// Migrations the registered migrations
+var Migrations = map[string]*Migration{}
+
+// Register register a migration, the migrations are run in the order of their names,
+// e.g. 2021_06_01_000000_create_users_table
+func Register(name string, up func(schema schema.Schema) error, down func(schema schema.Schema) error) {
+	if _, has := Migrations[name]; has {
+		panic(fmt.Errorf("the migration %s has been registered", name))
+	}
+	Migrations[name] = &Migration{Name: name, Up: up, Down: down}
+}
+
+// New create a new migrator using the given schema and query builders, the registered migrations will be added.
+func New(schema schema.Schema, query query.Query) *Migrator {
+	migrator := &Migrator{
+		Schema:      schema,
+		Query:       query,
+		Table:       "migrations",
+		LockTable:   "migrations_lock",
+		LockTimeout: time.Hour,
+		Owner:       owner(),
+		Migrations:  []*Migration{},
+	}
+	for _, migration := range Migrations {
+		migrator.Migrations = append(migrator.Migrations, migration)
+	}
+	migrator.sort()
+	return migrator
+}
+
+// owner get the default owner of the migration lock, the host name, the process id and the time the migrator was created.
+func owner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d:%d", host, os.Getpid(), time.Now().UnixNano())
+}
+
+// Add add migrations to the migrator
+func (migrator *Migrator) Add(migrations ...*Migration) *Migrator {
+	for _, migration := range migrations {
+		if migrator.getMigration(migration.Name) != nil {
+			panic(fmt.Errorf("the migration %s has been added", migration.Name))
+		}
+		migrator.Migrations = append(migrator.Migrations, migration)
+	}
+	migrator.sort()
+	return migrator
+}
+
+// getMigration get the migration by the given name, returns nil if the migration does not exist.
+func (migrator *Migrator) getMigration(name string) *Migration {
+	for _, migration := range migrator.Migrations {
+		if migration.Name == name {
+			return migration
+		}
+	}
+	return nil
+}
+
+// sort sort the migrations by name
+func (migrator *Migrator) sort() {
+	sort.SliceStable(migrator.Migrations, func(i, j int) bool {
+		return migrator.Migrations[i].Name < migrator.Migrations[j].Name
+	})
+}
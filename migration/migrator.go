@@ -0,0 +1,207 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/utils"
+)
+
+// Migrate Run the pending migrations, the migrations are recorded with a new batch number.
+// Returns the names of the migrations that were run.
+func (migrator *Migrator) Migrate() ([]string, error) {
+	names := []string{}
+	err := migrator.withLock(func() error {
+		var err error
+		names, err = migrator.runUp()
+		return err
+	})
+	return names, err
+}
+
+// MustMigrate Run the pending migrations.
+func (migrator *Migrator) MustMigrate() []string {
+	names, err := migrator.Migrate()
+	utils.PanicIF(err)
+	return names
+}
+
+// Rollback Rollback the last n migrations, if steps is less than 1, the migrations of the last batch are rolled back.
+// Returns the names of the migrations that were rolled back.
+func (migrator *Migrator) Rollback(steps int) ([]string, error) {
+	names := []string{}
+	err := migrator.withLock(func() error {
+		last, err := migrator.getLast(steps)
+		if err != nil {
+			return err
+		}
+		names, err = migrator.runDown(last)
+		return err
+	})
+	return names, err
+}
+
+// MustRollback Rollback the last n migrations.
+func (migrator *Migrator) MustRollback(steps int) []string {
+	names, err := migrator.Rollback(steps)
+	utils.PanicIF(err)
+	return names
+}
+
+// Reset Rollback all the applied migrations.
+// Returns the names of the migrations that were rolled back.
+func (migrator *Migrator) Reset() ([]string, error) {
+	names := []string{}
+	err := migrator.withLock(func() error {
+		var err error
+		names, err = migrator.runReset()
+		return err
+	})
+	return names, err
+}
+
+// MustReset Rollback all the applied migrations.
+func (migrator *Migrator) MustReset() []string {
+	names, err := migrator.Reset()
+	utils.PanicIF(err)
+	return names
+}
+
+// Refresh Rollback all the applied migrations and run all the migrations again.
+// Returns the names of the migrations that were run.
+func (migrator *Migrator) Refresh() ([]string, error) {
+	names := []string{}
+	err := migrator.withLock(func() error {
+		_, err := migrator.runReset()
+		if err != nil {
+			return err
+		}
+		names, err = migrator.runUp()
+		return err
+	})
+	return names, err
+}
+
+// MustRefresh Rollback all the applied migrations and run all the migrations again.
+func (migrator *Migrator) MustRefresh() []string {
+	names, err := migrator.Refresh()
+	utils.PanicIF(err)
+	return names
+}
+
+// Status Get the status of the migrations.
+func (migrator *Migrator) Status() ([]Status, error) {
+	err := migrator.prepare()
+	if err != nil {
+		return nil, err
+	}
+
+	ran, err := migrator.getRan()
+	if err != nil {
+		return nil, err
+	}
+
+	status := []Status{}
+	for _, migration := range migrator.Migrations {
+		batch, has := ran[migration.Name]
+		status = append(status, Status{Name: migration.Name, Ran: has, Batch: batch})
+	}
+	return status, nil
+}
+
+// MustStatus Get the status of the migrations.
+func (migrator *Migrator) MustStatus() []Status {
+	status, err := migrator.Status()
+	utils.PanicIF(err)
+	return status
+}
+
+// runUp run the pending migrations
+func (migrator *Migrator) runUp() ([]string, error) {
+	names := []string{}
+	ran, err := migrator.getRan()
+	if err != nil {
+		return names, err
+	}
+
+	batch, err := migrator.getLastBatch()
+	if err != nil {
+		return names, err
+	}
+	batch = batch + 1
+
+	for _, migration := range migrator.Migrations {
+		if _, has := ran[migration.Name]; has {
+			continue
+		}
+
+		err = migrator.transaction(func(sch schema.Schema, qb query.Query) error {
+			if migration.Up != nil {
+				err := migration.Up(sch)
+				if err != nil {
+					return fmt.Errorf("migrate %s: %s", migration.Name, err)
+				}
+			}
+			return migrator.log(qb, migration.Name, batch)
+		})
+		if err != nil {
+			return names, err
+		}
+		names = append(names, migration.Name)
+	}
+	return names, nil
+}
+
+// runDown rollback the given migrations in order
+func (migrator *Migrator) runDown(migrations []string) ([]string, error) {
+	names := []string{}
+	for _, name := range migrations {
+		migration := migrator.getMigration(name)
+		if migration == nil {
+			return names, fmt.Errorf("rollback %s: the migration not found", name)
+		}
+
+		err := migrator.transaction(func(sch schema.Schema, qb query.Query) error {
+			if migration.Down != nil {
+				err := migration.Down(sch)
+				if err != nil {
+					return fmt.Errorf("rollback %s: %s", name, err)
+				}
+			}
+			return migrator.delete(qb, name)
+		})
+		if err != nil {
+			return names, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// transaction run the callback within a transaction shared by the schema and query builders,
+// so that a failed migration does not leave the schema changes without the migration record.
+// MySQL commits the DDL statements implicitly, the callback is run without a transaction.
+func (migrator *Migrator) transaction(callback func(sch schema.Schema, qb query.Query) error) error {
+	if migrator.Schema.InTransaction() || migrator.Schema.Builder().Conn.WriteConfig.Driver == "mysql" {
+		return callback(migrator.Schema, migrator.Query)
+	}
+
+	return migrator.Schema.Transaction(func(tx schema.Schema) error {
+		return callback(tx, migrator.Query.WithTx(tx.Builder().Tx))
+	})
+}
+
+// runReset rollback all the applied migrations
+func (migrator *Migrator) runReset() ([]string, error) {
+	ran, err := migrator.getRan()
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := migrator.getLast(len(ran))
+	if err != nil {
+		return nil, err
+	}
+	return migrator.runDown(last)
+}
@@ -0,0 +1,222 @@
+package migration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+var testSchema schema.Schema
+var testQuery query.Query
+
+func getTestSchema() schema.Schema {
+	unit.SetLogger()
+	if testSchema == nil {
+		testSchema = schema.New(unit.Driver(), unit.DSN())
+	}
+	return testSchema
+}
+
+func getTestQuery() query.Query {
+	unit.SetLogger()
+	if testQuery == nil {
+		testQuery = query.New(unit.Driver(), unit.DSN())
+	}
+	return testQuery
+}
+
+func TestMigratorMigrate(t *testing.T) {
+	migrator := NewMigratorForTest()
+	names := migrator.MustMigrate()
+	assert.Equal(t, []string{"2021_06_01_000000_create_users_table", "2021_06_02_000000_create_posts_table"}, names, "the migrations should be run in order")
+	assert.True(t, migrator.Schema.MustHasTable("table_test_migration_users"), "the users table should be created")
+	assert.True(t, migrator.Schema.MustHasTable("table_test_migration_posts"), "the posts table should be created")
+	assert.Equal(t, []string{}, migrator.MustMigrate(), "there should be no pending migrations")
+
+	migrator.Add(&Migration{
+		Name: "2021_06_03_000000_add_title_to_posts",
+		Up: func(sch schema.Schema) error {
+			return sch.AlterTable("table_test_migration_posts", func(table schema.Blueprint) {
+				table.String("title", 200).Null()
+			})
+		},
+		Down: func(sch schema.Schema) error {
+			return sch.AlterTable("table_test_migration_posts", func(table schema.Blueprint) {
+				table.DropColumn("title")
+			})
+		},
+	})
+	assert.Equal(t, []string{"2021_06_03_000000_add_title_to_posts"}, migrator.MustMigrate(), "the new migration should be run")
+
+	status := migrator.MustStatus()
+	assert.Equal(t, 3, len(status), "the status should have 3 migrations")
+	assert.Equal(t, Status{Name: "2021_06_01_000000_create_users_table", Ran: true, Batch: 1}, status[0])
+	assert.Equal(t, Status{Name: "2021_06_02_000000_create_posts_table", Ran: true, Batch: 1}, status[1])
+	assert.Equal(t, Status{Name: "2021_06_03_000000_add_title_to_posts", Ran: true, Batch: 2}, status[2])
+}
+
+func TestMigratorMigrateFail(t *testing.T) {
+	migrator := NewMigratorForTest()
+	migrator.Add(&Migration{
+		Name: "2021_06_03_000000_fail",
+		Up:   func(sch schema.Schema) error { return fmt.Errorf("something wrong") },
+	})
+	names, err := migrator.Migrate()
+	assert.Equal(t, "migrate 2021_06_03_000000_fail: something wrong", err.Error(), "the error should be returned")
+	assert.Equal(t, 2, len(names), "the migrations before the failed one should be run")
+	assert.False(t, migrator.MustIsLocked(), "the lock should be released")
+
+	status := migrator.MustStatus()
+	assert.False(t, status[2].Ran, "the failed migration should not be recorded")
+}
+
+func TestMigratorMigrateFailRollback(t *testing.T) {
+	if unit.DriverIs("mysql") {
+		return // MySQL commits the DDL statements implicitly
+	}
+
+	migrator := NewMigratorForTest()
+	migrator.Add(&Migration{
+		Name: "2021_06_03_000000_create_temp_table_fail",
+		Up: func(sch schema.Schema) error {
+			err := sch.CreateTable("table_test_migration_temp", func(table schema.Blueprint) { table.ID("id") })
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("something wrong")
+		},
+	})
+	_, err := migrator.Migrate()
+	assert.Equal(t, "migrate 2021_06_03_000000_create_temp_table_fail: something wrong", err.Error(), "the error should be returned")
+	assert.False(t, migrator.Schema.MustHasTable("table_test_migration_temp"), "the schema changes of the failed migration should be rolled back")
+	assert.True(t, migrator.Schema.MustHasTable("table_test_migration_posts"), "the migrations before the failed one should be committed")
+}
+
+func TestMigratorRollback(t *testing.T) {
+	migrator := NewMigratorForTest()
+	migrator.MustMigrate()
+	assert.Equal(t, []string{"2021_06_02_000000_create_posts_table", "2021_06_01_000000_create_users_table"}, migrator.MustRollback(0), "the last batch should be rolled back in reverse order")
+	assert.False(t, migrator.Schema.MustHasTable("table_test_migration_users"), "the users table should be dropped")
+	assert.False(t, migrator.Schema.MustHasTable("table_test_migration_posts"), "the posts table should be dropped")
+
+	migrator.MustMigrate()
+	assert.Equal(t, []string{"2021_06_02_000000_create_posts_table"}, migrator.MustRollback(1), "the last migration should be rolled back")
+	assert.True(t, migrator.Schema.MustHasTable("table_test_migration_users"), "the users table should not be dropped")
+	assert.False(t, migrator.Schema.MustHasTable("table_test_migration_posts"), "the posts table should be dropped")
+
+	status := migrator.MustStatus()
+	assert.True(t, status[0].Ran, "the first migration should be ran")
+	assert.False(t, status[1].Ran, "the second migration should not be ran")
+}
+
+func TestMigratorResetAndRefresh(t *testing.T) {
+	migrator := NewMigratorForTest()
+	migrator.MustMigrate()
+	migrator.Add(&Migration{
+		Name: "2021_06_03_000000_create_temp_table",
+		Up: func(sch schema.Schema) error {
+			return sch.CreateTable("table_test_migration_temp", func(table schema.Blueprint) { table.ID("id") })
+		},
+	})
+	migrator.MustMigrate()
+	migrator.Schema.MustDropTable("table_test_migration_temp")
+
+	names := migrator.MustRefresh()
+	assert.Equal(t, 3, len(names), "all the migrations should be run again")
+	for _, status := range migrator.MustStatus() {
+		assert.Equal(t, 1, status.Batch, "the migrations should be recorded with the batch 1")
+	}
+
+	names = migrator.MustReset()
+	assert.Equal(t, []string{"2021_06_03_000000_create_temp_table", "2021_06_02_000000_create_posts_table", "2021_06_01_000000_create_users_table"}, names, "all the migrations should be rolled back")
+	for _, status := range migrator.MustStatus() {
+		assert.False(t, status.Ran, "the migrations should not be ran")
+	}
+}
+
+func TestMigratorLock(t *testing.T) {
+	migrator := NewMigratorForTest()
+	migrator.prepare()
+	assert.Nil(t, migrator.lock(), "the lock should be acquired")
+	assert.True(t, migrator.MustIsLocked(), "the migrations should be locked")
+
+	other := New(getTestSchema(), getTestQuery())
+	_, err := other.Migrate()
+	assert.NotNil(t, err, "the migrations should be locked by the other migrator")
+
+	migrator.MustUnlock()
+	assert.False(t, migrator.MustIsLocked(), "the migrations should not be locked")
+}
+
+func TestMigratorLockStale(t *testing.T) {
+	migrator := NewMigratorForTest()
+	migrator.prepare()
+	migrator.Query.New().Table(migrator.LockTable).MustInsert(xun.R{"id": 1, "owner": "dead", "locked_at": time.Now().Add(-2 * time.Hour)})
+
+	migrator.LockTimeout = 0
+	_, err := migrator.Migrate()
+	assert.Contains(t, err.Error(), "the migrations are locked by dead", "the owner of the lock should be returned")
+
+	migrator.LockTimeout = time.Hour
+	names, err := migrator.Migrate()
+	assert.Nil(t, err, "the stale lock should be broken")
+	assert.Equal(t, 2, len(names), "the migrations should be run")
+	assert.False(t, migrator.MustIsLocked(), "the lock should be released")
+}
+
+func TestMigratorRegister(t *testing.T) {
+	up := func(sch schema.Schema) error { return nil }
+	Register("2021_06_01_000000_test_register", up, nil)
+	assert.Panics(t, func() { Register("2021_06_01_000000_test_register", up, nil) })
+	migrator := New(getTestSchema(), getTestQuery())
+	assert.NotNil(t, migrator.getMigration("2021_06_01_000000_test_register"), "the registered migration should be added")
+	delete(Migrations, "2021_06_01_000000_test_register")
+}
+
+// clean the test data
+func TestMigratorClean(t *testing.T) {
+	sch := getTestSchema()
+	sch.MustDropTableIfExists("table_test_migration_posts")
+	sch.MustDropTableIfExists("table_test_migration_users")
+	sch.MustDropTableIfExists("table_test_migration_temp")
+	sch.MustDropTableIfExists("migrations")
+	sch.MustDropTableIfExists("migrations_lock")
+}
+
+func NewMigratorForTest() *Migrator {
+	TestMigratorClean(nil)
+	migrator := New(getTestSchema(), getTestQuery())
+	migrator.Add(
+		&Migration{
+			Name: "2021_06_02_000000_create_posts_table",
+			Up: func(sch schema.Schema) error {
+				return sch.CreateTable("table_test_migration_posts", func(table schema.Blueprint) {
+					table.ID("id")
+					table.ForeignID("user_id")
+				})
+			},
+			Down: func(sch schema.Schema) error {
+				return sch.DropTable("table_test_migration_posts")
+			},
+		},
+		&Migration{
+			Name: "2021_06_01_000000_create_users_table",
+			Up: func(sch schema.Schema) error {
+				return sch.CreateTable("table_test_migration_users", func(table schema.Blueprint) {
+					table.ID("id")
+					table.String("name", 80)
+				})
+			},
+			Down: func(sch schema.Schema) error {
+				return sch.DropTable("table_test_migration_users")
+			},
+		},
+	)
+	return migrator
+}
@@ -0,0 +1,114 @@
+package migration
+
+import (
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/xun/dbal/schema"
+)
+
+// the migration repository, records the applied migrations and their batch numbers
+
+// prepare create the migrations table and the lock table if they do not exist.
+func (migrator *Migrator) prepare() error {
+	has, err := migrator.Schema.HasTable(migrator.Table)
+	if err != nil {
+		return err
+	}
+
+	if !has {
+		err = migrator.Schema.CreateTable(migrator.Table, func(table schema.Blueprint) {
+			table.ID("id")
+			table.String("migration", 200).Unique()
+			table.Integer("batch").Index()
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	has, err = migrator.Schema.HasTable(migrator.LockTable)
+	if err != nil {
+		return err
+	}
+
+	if !has {
+		err = migrator.Schema.CreateTable(migrator.LockTable, func(table schema.Blueprint) {
+			table.Integer("id").Primary()
+			table.String("owner", 200).Null()
+			table.Timestamp("locked_at").Null()
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// table get a query builder of the migrations table
+func (migrator *Migrator) table() query.Query {
+	return migrator.Query.New().Table(migrator.Table)
+}
+
+// getRan get the batch numbers of the applied migrations
+func (migrator *Migrator) getRan() (map[string]int, error) {
+	rows, err := migrator.table().Select("migration", "batch").OrderBy("id").Get()
+	if err != nil {
+		return nil, err
+	}
+
+	ran := map[string]int{}
+	for _, row := range rows {
+		ran[row.GetString("migration")] = row.GetInt("batch")
+	}
+	return ran, nil
+}
+
+// getLastBatch get the last batch number, returns 0 if no migration has been applied.
+func (migrator *Migrator) getLastBatch() (int, error) {
+	batch, err := migrator.table().Max("batch")
+	if err != nil {
+		return 0, err
+	}
+	if batch.Number == nil {
+		return 0, nil
+	}
+	return batch.Int()
+}
+
+// getLast get the names of the applied migrations, the newest migration first.
+// if steps is greater than 0, returns the last n migrations, otherwise returns the migrations of the last batch.
+func (migrator *Migrator) getLast(steps int) ([]string, error) {
+	qb := migrator.table().Select("migration").OrderByDesc("batch").OrderByDesc("migration")
+	if steps > 0 {
+		qb = qb.Limit(steps)
+	} else {
+		batch, err := migrator.getLastBatch()
+		if err != nil {
+			return nil, err
+		}
+		qb = qb.Where("batch", batch)
+	}
+
+	rows, err := qb.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, row := range rows {
+		names = append(names, row.GetString("migration"))
+	}
+	return names, nil
+}
+
+// log record the migration was applied using the given query builder
+func (migrator *Migrator) log(qb query.Query, name string, batch int) error {
+	return qb.New().Table(migrator.Table).Insert(xun.R{"migration": name, "batch": batch})
+}
+
+// delete remove the migration record using the given query builder
+func (migrator *Migrator) delete(qb query.Query, name string) error {
+	_, err := qb.New().Table(migrator.Table).Where("migration", name).Delete()
+	return err
+}
@@ -0,0 +1,34 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/xun/dbal/schema"
+)
+
+// Migration the migration struct
+type Migration struct {
+	Name string
+	Up   func(schema schema.Schema) error
+	Down func(schema schema.Schema) error
+}
+
+// Migrator the migration runner
+type Migrator struct {
+	Schema      schema.Schema
+	Query       query.Query
+	Table       string
+	LockTable   string
+	LockWait    time.Duration // The duration waiting for the lock held by another process
+	LockTimeout time.Duration // The lock held longer than the duration is stale and can be broken, 0 never breaks the lock
+	Owner       string        // The owner of the lock, defaults to the host name and the process id
+	Migrations  []*Migration
+}
+
+// Status the status of a migration
+type Status struct {
+	Name  string
+	Ran   bool
+	Batch int
+}
@@ -28,6 +28,14 @@ type P struct {
 	Options      map[string]interface{} `json:"options,omtempty"`
 }
 
+// C an cursor paginator struct, C is the first letter of "Cursor"
+type C struct {
+	Items          []interface{} `json:"items"`
+	PageSize       int           `json:"page_size"`
+	NextCursor     string        `json:"next_cursor"`
+	PreviousCursor string        `json:"previous_cursor"`
+}
+
 // UploadFile deprecated -> gou.UploadFile upload file
 type UploadFile struct {
 	Name     string